@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tuantran1810/go-di-template/config"
+	"github.com/tuantran1810/go-di-template/internal/repositories"
+	"github.com/tuantran1810/go-di-template/internal/repositories/encryption"
+)
+
+// rotateKeys adds a master key to the keyfile, unless --no-new-key, and re-encrypts with it the encrypted columns.
+// It exits with a non-zero status when the rotation fails.
+func rotateKeys(cmd *cobra.Command, _ []string) {
+	batchSize, err := cmd.Flags().GetInt("batch-size")
+	if err != nil {
+		log.Fatalf("Failed to read batch size: %v", err)
+	}
+	noNewKey, err := cmd.Flags().GetBool("no-new-key")
+	if err != nil {
+		log.Fatalf("Failed to read no new key: %v", err)
+	}
+
+	cfg := config.MustLoadConfig[config.ServerConfig]()
+	if cfg.Encryption.Keyfile == "" {
+		log.Fatal("No encryption keyfile configured")
+	}
+	if !noNewKey {
+		id, err := encryption.AddMasterKey(cfg.Encryption.Keyfile)
+		if err != nil {
+			log.Fatalf("Failed to add master key: %v", err)
+		}
+		log.Infof("Added master key %s", id)
+	}
+	keyring := mustLoadKeyring(cfg.Encryption)
+
+	rotated, err := runRotate(cfg, keyring, batchSize)
+	if err != nil {
+		log.Fatalf("Failed to rotate keys after %d rows: %v", rotated, err)
+	}
+	log.Infof("Rotated %d rows to master key %s", rotated, keyring.ActiveKeyID())
+}
+
+// runRotate re-encrypts the encrypted columns with the active master key and returns the number of rows rotated.
+// The database is stopped before it returns, so that a failure, e.g. after a partial re-encryption,
+// can exit with a non-zero status.
+func runRotate(cfg config.ServerConfig, keyring *encryption.Keyring, batchSize int) (int, error) {
+	repository, err := openDatabase(cfg, keyring)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open repository: %w", err)
+	}
+	if err := repository.Start(globalContext); err != nil {
+		return 0, fmt.Errorf("failed to start repository: %w", err)
+	}
+	defer func() {
+		if err := repository.Stop(globalContext); err != nil {
+			log.Errorf("Failed to stop repository: %v", err)
+		}
+	}()
+
+	// the blind index columns may not exist yet
+	if err := repository.DB().WithContext(globalContext).AutoMigrate(repositories.EncryptedModels...); err != nil {
+		return 0, fmt.Errorf("failed to migrate encrypted models: %w", err)
+	}
+
+	return encryption.Rotate(globalContext, repository.DB(), batchSize, repositories.EncryptedModels...)
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tuantran1810/go-di-template/config"
+	"github.com/tuantran1810/go-di-template/internal/repositories"
+	"github.com/tuantran1810/go-di-template/internal/seed"
+)
+
+// seedDatabase populates the DB_DRIVER database with fake users, their attributes and messages.
+// It exits with a non-zero status when the seeding fails.
+func seedDatabase(cmd *cobra.Command, _ []string) {
+	flags := cmd.Flags()
+	users, err := flags.GetInt("users")
+	if err != nil {
+		log.Fatalf("Failed to read users: %v", err)
+	}
+	batchSize, err := flags.GetInt("batch-size")
+	if err != nil {
+		log.Fatalf("Failed to read batch size: %v", err)
+	}
+	minAttributes, err := flags.GetInt("min-attributes")
+	if err != nil {
+		log.Fatalf("Failed to read min attributes: %v", err)
+	}
+	maxAttributes, err := flags.GetInt("max-attributes")
+	if err != nil {
+		log.Fatalf("Failed to read max attributes: %v", err)
+	}
+	attributesFlag, err := flags.GetString("attributes")
+	if err != nil {
+		log.Fatalf("Failed to read attributes: %v", err)
+	}
+	messagesPerUser, err := flags.GetInt("messages-per-user")
+	if err != nil {
+		log.Fatalf("Failed to read messages per user: %v", err)
+	}
+	period, err := flags.GetDuration("period")
+	if err != nil {
+		log.Fatalf("Failed to read period: %v", err)
+	}
+	until, err := flags.GetString("until")
+	if err != nil {
+		log.Fatalf("Failed to read until: %v", err)
+	}
+	var now time.Time
+	if until != "" {
+		if now, err = time.Parse(time.RFC3339, until); err != nil {
+			log.Fatalf("Failed to parse until: %v", err)
+		}
+	}
+	rngSeed, err := flags.GetInt64("seed")
+	if err != nil {
+		log.Fatalf("Failed to read seed: %v", err)
+	}
+
+	attributes, err := seed.ParseAttributeWeights(attributesFlag)
+	if err != nil {
+		log.Fatalf("Failed to parse attributes: %v", err)
+	}
+
+	seedConfig := seed.Config{
+		Users:           users,
+		BatchSize:       batchSize,
+		MinAttributes:   minAttributes,
+		MaxAttributes:   maxAttributes,
+		Attributes:      attributes,
+		MessagesPerUser: messagesPerUser,
+		Period:          period,
+		Now:             now,
+		Seed:            rngSeed,
+	}
+	if err := runSeeder(config.MustLoadConfig[config.ServerConfig](), seedConfig); err != nil {
+		log.Fatalf("Failed to seed database: %v", err)
+	}
+}
+
+// runSeeder seeds the database, which is stopped before it returns, so that a failure can exit with a non-zero status.
+func runSeeder(cfg config.ServerConfig, seedConfig seed.Config) error {
+	database, err := openDatabase(cfg, mustLoadKeyring(cfg.Encryption))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	if err := database.Start(globalContext); err != nil {
+		return fmt.Errorf("failed to start repository: %w", err)
+	}
+	defer func() {
+		if err := database.Stop(globalContext); err != nil {
+			log.Errorf("Failed to stop repository: %v", err)
+		}
+	}()
+
+	// the stores migrate their tables and the default tenant when they start
+	tenantRepository := repositories.NewTenantRepository(database)
+	userRepository := repositories.NewUserRepository(database)
+	userAttributeRepository := repositories.NewUserAttributeRepository(database)
+	messageRepository := repositories.NewMessageRepository(database)
+	for _, start := range []func(context.Context) error{
+		tenantRepository.Start,
+		userRepository.Start,
+		userAttributeRepository.Start,
+		messageRepository.Start,
+	} {
+		if err := start(globalContext); err != nil {
+			return fmt.Errorf("failed to start store: %w", err)
+		}
+	}
+
+	seeder, err := seed.NewSeeder(
+		seedConfig,
+		database,
+		userRepository,
+		userAttributeRepository,
+		messageRepository,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create seeder: %w", err)
+	}
+
+	result, err := seeder.Run(globalContext)
+	if err != nil {
+		return fmt.Errorf("failed after %d users: %w", result.Users, err)
+	}
+	log.Infof("Seeded %d users, %d attributes and %d messages", result.Users, result.Attributes, result.Messages)
+	return nil
+}
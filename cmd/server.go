@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/spf13/cobra"
@@ -10,9 +12,21 @@ import (
 	"github.com/tuantran1810/go-di-template/internal/inbound"
 	"github.com/tuantran1810/go-di-template/internal/outbound"
 	"github.com/tuantran1810/go-di-template/internal/repositories"
+	"github.com/tuantran1810/go-di-template/internal/repositories/cache"
+	"github.com/tuantran1810/go-di-template/internal/repositories/connect"
+	"github.com/tuantran1810/go-di-template/internal/repositories/dbtls"
+	"github.com/tuantran1810/go-di-template/internal/repositories/encryption"
+	"github.com/tuantran1810/go-di-template/internal/repositories/memory"
 	"github.com/tuantran1810/go-di-template/internal/repositories/mysql"
+	"github.com/tuantran1810/go-di-template/internal/repositories/postgres"
+	sqlite "github.com/tuantran1810/go-di-template/internal/repositories/sqlite"
 	"github.com/tuantran1810/go-di-template/internal/usecases"
+	"github.com/tuantran1810/go-di-template/libs/health"
+	"github.com/tuantran1810/go-di-template/libs/logger"
+	"github.com/tuantran1810/go-di-template/libs/middlewares/admin"
+	"github.com/tuantran1810/go-di-template/libs/middlewares/auth"
 	"github.com/tuantran1810/go-di-template/libs/middlewares/errorcode"
+	"github.com/tuantran1810/go-di-template/libs/middlewares/tenant"
 	"github.com/tuantran1810/go-di-template/libs/server"
 	pb "github.com/tuantran1810/go-di-template/pkg/go_di_template/v1"
 	"go.uber.org/fx"
@@ -20,28 +34,69 @@ import (
 )
 
 var (
+	_ repositories.Database             = &mysql.Repository{}
+	_ repositories.Database             = &postgres.Repository{}
+	_ repositories.Database             = &sqlite.Repository{}
+	_ usecases.IRepository              = repositories.Database(nil)
 	_ usecases.IUserRepository          = &repositories.UserRepository{}
 	_ usecases.IUserAttributeRepository = &repositories.UserAttributeRepository{}
 	_ usecases.IMessageRepository       = &repositories.MessageRepository{}
+	_ usecases.IOutboxRepository        = &repositories.OutboxRepository{}
+	_ usecases.IAuditEventRepository    = &repositories.AuditEventRepository{}
+	_ usecases.ITenantRepository        = &repositories.TenantRepository{}
+	_ usecases.IRepository              = &memory.Repository{}
+	_ usecases.IUserRepository          = &memory.UserRepository{}
+	_ usecases.IUserAttributeRepository = &memory.UserAttributeRepository{}
+	_ usecases.IMessageRepository       = &memory.MessageRepository{}
+	_ usecases.IOutboxRepository        = &memory.OutboxRepository{}
+	_ usecases.IAuditEventRepository    = &memory.AuditEventRepository{}
+	_ usecases.ITenantRepository        = &memory.TenantRepository{}
+	_ cache.IUserRepository             = &repositories.UserRepository{}
+	_ cache.IUserAttributeRepository    = &repositories.UserAttributeRepository{}
+	_ cache.IUserRepository             = &memory.UserRepository{}
+	_ cache.IUserAttributeRepository    = &memory.UserAttributeRepository{}
+	_ usecases.IUserRepository          = &cache.UserRepository{}
+	_ usecases.IUserAttributeRepository = &cache.UserAttributeRepository{}
 	_ controllers.IUserUsecase          = &usecases.Users{}
 	_ controllers.ILoggingWorker        = &usecases.LoggingWorker{}
+	_ controllers.IAuditEventUsecase    = &usecases.AuditEvents{}
+	_ controllers.ITenantUsecase        = &usecases.Tenants{}
+	_ tenant.IChecker                   = &usecases.Tenants{}
 )
 
-func newRepository(
+// openDatabase returns the repository of the DB_DRIVER database, connected by its Start().
+func openDatabase(cfg config.ServerConfig, keyring *encryption.Keyring) (repositories.Database, error) {
+	switch cfg.DbDriver {
+	case "mysql":
+		return mysql.MustNewRepository(newMysqlRepositoryConfig(cfg, keyring)), nil
+	case "postgres":
+		return postgres.MustNewRepository(newPostgresRepositoryConfig(cfg, keyring)), nil
+	case "sqlite":
+		return sqlite.NewRepository(newSqliteRepositoryConfig(cfg, keyring))
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", cfg.DbDriver)
+	}
+}
+
+func newDatabase(
 	appLifecycle fx.Lifecycle,
-	config mysql.RepositoryConfig,
-) *mysql.Repository {
-	r := mysql.MustNewRepository(config)
+	cfg config.ServerConfig,
+) (repositories.Database, error) {
+	r, err := openDatabase(cfg, mustLoadKeyring(cfg.Encryption))
+	if err != nil {
+		return nil, err
+	}
+
 	appLifecycle.Append(fx.Hook{
 		OnStart: r.Start,
 		OnStop:  r.Stop,
 	})
-	return r
+	return r, nil
 }
 
 func newUserRepository(
 	appLifecycle fx.Lifecycle,
-	repository *mysql.Repository,
+	repository repositories.Database,
 ) *repositories.UserRepository {
 	s := repositories.NewUserRepository(repository)
 	appLifecycle.Append(fx.Hook{
@@ -53,7 +108,7 @@ func newUserRepository(
 
 func newUserAttributeRepository(
 	appLifecycle fx.Lifecycle,
-	repository *mysql.Repository,
+	repository repositories.Database,
 ) *repositories.UserAttributeRepository {
 	s := repositories.NewUserAttributeRepository(repository)
 	appLifecycle.Append(fx.Hook{
@@ -65,7 +120,7 @@ func newUserAttributeRepository(
 
 func newMessageRepository(
 	appLifecycle fx.Lifecycle,
-	repository *mysql.Repository,
+	repository repositories.Database,
 ) *repositories.MessageRepository {
 	s := repositories.NewMessageRepository(repository)
 	appLifecycle.Append(fx.Hook{
@@ -75,17 +130,278 @@ func newMessageRepository(
 	return s
 }
 
+func newOutboxRepository(
+	appLifecycle fx.Lifecycle,
+	repository repositories.Database,
+) *repositories.OutboxRepository {
+	s := repositories.NewOutboxRepository(repository)
+	appLifecycle.Append(fx.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	})
+	return s
+}
+
+func newAuditEventRepository(
+	appLifecycle fx.Lifecycle,
+	repository repositories.Database,
+) *repositories.AuditEventRepository {
+	s := repositories.NewAuditEventRepository(repository)
+	appLifecycle.Append(fx.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	})
+	return s
+}
+
+func newTenantRepository(
+	appLifecycle fx.Lifecycle,
+	repository repositories.Database,
+) *repositories.TenantRepository {
+	s := repositories.NewTenantRepository(repository)
+	appLifecycle.Append(fx.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	})
+	return s
+}
+
+func newMemoryRepository(appLifecycle fx.Lifecycle) *memory.Repository {
+	r := memory.NewRepository()
+	appLifecycle.Append(fx.Hook{
+		OnStart: r.Start,
+		OnStop:  r.Stop,
+	})
+	return r
+}
+
+func newMemoryUserRepository(
+	appLifecycle fx.Lifecycle,
+	repository *memory.Repository,
+) *memory.UserRepository {
+	s := memory.NewUserRepository(repository)
+	appLifecycle.Append(fx.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	})
+	return s
+}
+
+func newMemoryUserAttributeRepository(
+	appLifecycle fx.Lifecycle,
+	repository *memory.Repository,
+	userRepository *memory.UserRepository,
+) *memory.UserAttributeRepository {
+	s := memory.NewUserAttributeRepository(repository, userRepository)
+	appLifecycle.Append(fx.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	})
+	return s
+}
+
+func newMemoryMessageRepository(
+	appLifecycle fx.Lifecycle,
+	repository *memory.Repository,
+) *memory.MessageRepository {
+	s := memory.NewMessageRepository(repository)
+	appLifecycle.Append(fx.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	})
+	return s
+}
+
+func newMemoryOutboxRepository(
+	appLifecycle fx.Lifecycle,
+	repository *memory.Repository,
+) *memory.OutboxRepository {
+	s := memory.NewOutboxRepository(repository)
+	appLifecycle.Append(fx.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	})
+	return s
+}
+
+func newMemoryAuditEventRepository(
+	appLifecycle fx.Lifecycle,
+	repository *memory.Repository,
+) *memory.AuditEventRepository {
+	s := memory.NewAuditEventRepository(repository)
+	appLifecycle.Append(fx.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	})
+	return s
+}
+
+func newMemoryTenantRepository(
+	appLifecycle fx.Lifecycle,
+	repository *memory.Repository,
+) *memory.TenantRepository {
+	s := memory.NewTenantRepository(repository)
+	appLifecycle.Append(fx.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	})
+	return s
+}
+
+// provideRepositories provides the usecase repository interfaces from the configured backend,
+// the database one running on the DB_DRIVER database, the memory one needing no external dependency.
+func provideRepositories(backend string) fx.Option {
+	switch backend {
+	case "memory":
+		return fx.Provide(
+			fx.Annotate(newMemoryRepository, fx.As(fx.Self()), fx.As(new(usecases.IRepository))),
+			fx.Annotate(newMemoryUserRepository, fx.As(fx.Self()), fx.As(new(cache.IUserRepository))),
+			fx.Annotate(newMemoryUserAttributeRepository, fx.As(new(cache.IUserAttributeRepository))),
+			fx.Annotate(newMemoryMessageRepository, fx.As(new(usecases.IMessageRepository))),
+			fx.Annotate(newMemoryOutboxRepository, fx.As(new(usecases.IOutboxRepository))),
+			fx.Annotate(newMemoryAuditEventRepository, fx.As(new(usecases.IAuditEventRepository))),
+			fx.Annotate(newMemoryTenantRepository, fx.As(new(usecases.ITenantRepository))),
+		)
+	case "database":
+		return fx.Options(
+			fx.Provide(
+				fx.Annotate(newDatabase, fx.As(fx.Self()), fx.As(new(usecases.IRepository))),
+				fx.Annotate(newUserRepository, fx.As(new(cache.IUserRepository))),
+				fx.Annotate(newUserAttributeRepository, fx.As(new(cache.IUserAttributeRepository))),
+				fx.Annotate(newMessageRepository, fx.As(new(usecases.IMessageRepository))),
+				fx.Annotate(newOutboxRepository, fx.As(new(usecases.IOutboxRepository))),
+				fx.Annotate(newAuditEventRepository, fx.As(new(usecases.IAuditEventRepository))),
+				fx.Annotate(newTenantRepository, fx.As(new(usecases.ITenantRepository))),
+			),
+			fx.Invoke(registerDatabaseHealthCheck),
+		)
+	default:
+		return fx.Error(fmt.Errorf("unsupported repository backend: %s", backend))
+	}
+}
+
+func newHealthRegistry(cfg config.HealthConfig) *health.Registry {
+	return health.NewRegistry(health.Config{
+		Interval: cfg.Interval,
+		Timeout:  cfg.Timeout,
+	})
+}
+
+// registerDatabaseHealthCheck makes the server and the services on the database not ready when it is unreachable.
+func registerDatabaseHealthCheck(registry *health.Registry, database repositories.Database) {
+	registry.Register(health.Check{
+		Name:     "database",
+		Check:    database.Check,
+		Critical: true,
+		Services: []string{
+			pb.UserService_ServiceDesc.ServiceName,
+			pb.AuditService_ServiceDesc.ServiceName,
+			pb.TenantService_ServiceDesc.ServiceName,
+		},
+	})
+}
+
+// registerHealthChecks registers the checks of the components degrading the server without making it unusable.
+func registerHealthChecks(
+	registry *health.Registry,
+	loggingWorker *usecases.LoggingWorker,
+	client *outbound.FakeClient,
+) {
+	registry.Register(health.Check{
+		Name:  "logging_worker",
+		Check: loggingWorker.Check,
+	})
+	registry.Register(health.Check{
+		Name:  "client",
+		Check: client.Check,
+	})
+}
+
+// startHealthRegistry is invoked last, so that the checks start running once the components they check are started,
+// and stop first, so that the server is reported as not serving while it drains.
+func startHealthRegistry(appLifecycle fx.Lifecycle, registry *health.Registry) {
+	appLifecycle.Append(fx.Hook{
+		OnStart: registry.Start,
+		OnStop:  registry.Stop,
+	})
+}
+
+func newCacheBackend(cfg config.CacheConfig) cache.IBackend {
+	return cache.NewLRUBackend(cfg.Capacity)
+}
+
+// newCachedUserRepository puts the read-through cache in front of the user repository of the backend, if enabled.
+func newCachedUserRepository(
+	cfg config.CacheConfig,
+	backend cache.IBackend,
+	repository cache.IUserRepository,
+) usecases.IUserRepository {
+	if !cfg.Enabled {
+		return repository
+	}
+
+	return cache.NewUserRepository(repository, backend, cache.Config{
+		TTL:         cfg.TTL,
+		NegativeTTL: cfg.NegativeTTL,
+	})
+}
+
+func newCachedUserAttributeRepository(
+	cfg config.CacheConfig,
+	backend cache.IBackend,
+	repository cache.IUserAttributeRepository,
+) usecases.IUserAttributeRepository {
+	if !cfg.Enabled {
+		return repository
+	}
+
+	return cache.NewUserAttributeRepository(repository, backend, cache.Config{
+		TTL:         cfg.TTL,
+		NegativeTTL: cfg.NegativeTTL,
+	})
+}
+
 func newUsersUsecase(
-	userRepository *repositories.UserRepository,
-	userAttributeRepository *repositories.UserAttributeRepository,
+	config usecases.UsersConfig,
+	repository usecases.IRepository,
+	userRepository usecases.IUserRepository,
+	userAttributeRepository usecases.IUserAttributeRepository,
+	outboxRepository usecases.IOutboxRepository,
 ) *usecases.Users {
-	return usecases.NewUsersUsecase(userRepository, userAttributeRepository)
+	return usecases.NewUsersUsecase(config, repository, userRepository, userAttributeRepository, outboxRepository)
+}
+
+func newAuditEventsUsecase(auditEventRepository usecases.IAuditEventRepository) *usecases.AuditEvents {
+	return usecases.NewAuditEventsUsecase(auditEventRepository)
+}
+
+func newTenantsUsecase(
+	config usecases.TenantsConfig,
+	tenantRepository usecases.ITenantRepository,
+) *usecases.Tenants {
+	return usecases.NewTenantsUsecase(config, tenantRepository)
+}
+
+func newOutboxRelay(
+	cfg usecases.OutboxRelayConfig,
+	appLifecycle fx.Lifecycle,
+	repository usecases.IRepository,
+	outboxRepository usecases.IOutboxRepository,
+	messageRepository usecases.IMessageRepository,
+	client *outbound.FakeClient,
+) *usecases.OutboxRelay {
+	r := usecases.NewOutboxRelay(cfg, repository, outboxRepository, messageRepository, client)
+	appLifecycle.Append(fx.Hook{
+		OnStart: r.Start,
+		OnStop:  r.Stop,
+	})
+	return r
 }
 
 func newLoggingWorker(
 	cfg usecases.LoggingWorkerConfig,
 	appLifecycle fx.Lifecycle,
-	messageRepository *repositories.MessageRepository,
+	messageRepository usecases.IMessageRepository,
 	client *outbound.FakeClient,
 ) *usecases.LoggingWorker {
 	w := usecases.NewLoggingWorker(cfg, messageRepository, client)
@@ -103,6 +419,14 @@ func newController(
 	return controllers.NewUserController(usecase, loggingWorker)
 }
 
+func newAuditEventController(usecase *usecases.AuditEvents) *controllers.AuditEventController {
+	return controllers.NewAuditEventController(usecase)
+}
+
+func newTenantController(usecase *usecases.Tenants) *controllers.TenantController {
+	return controllers.NewTenantController(usecase)
+}
+
 func newFakeClient(
 	appLifecycle fx.Lifecycle,
 	config outbound.FakeClientConfig,
@@ -135,11 +459,29 @@ func newFakeConsumer(
 	return c
 }
 
+// newAuthenticator trusts the identity passed by the authentication proxy when its token is configured,
+// otherwise every caller is anonymous.
+func newAuthenticator(cfg config.AuthConfig) auth.IAuthenticator {
+	if cfg.TrustedProxyToken == "" {
+		log.Warn("No trusted proxy token configured, the callers are anonymous and the administration APIs unreachable")
+		return auth.Anonymous{}
+	}
+
+	return auth.NewTrustedProxy(cfg.TrustedProxyToken)
+}
+
 func startInboundServer(
 	appLifecycle fx.Lifecycle,
 	cfg config.ServerConfig,
 	userController *controllers.UserController,
+	auditEventController *controllers.AuditEventController,
+	tenantController *controllers.TenantController,
+	tenantsUsecase *usecases.Tenants,
+	healthRegistry *health.Registry,
 ) *server.Server {
+	authenticator := auth.NewAuthenticator(newAuthenticator(cfg.Auth))
+	admins := admin.NewAdmins(admin.Config{Actors: cfg.Admin.Actors})
+	tenants := tenant.NewTenants(tenantsUsecase)
 	serverConfig := server.NewServerConfig().
 		SetLogger(log).
 		SetGRPCAddr(fmt.Sprintf("0.0.0.0:%d", cfg.GrpcPort)).
@@ -147,13 +489,27 @@ func startInboundServer(
 		SetGRPCReflection(true).
 		RegisterGRPC(func(s *grpc.Server) {
 			pb.RegisterUserServiceServer(s, userController)
+			pb.RegisterAuditServiceServer(s, auditEventController)
+			pb.RegisterTenantServiceServer(s, tenantController)
 		}).
 		RegisterHTTP(func(mux *runtime.ServeMux, conn *grpc.ClientConn) {
 			if err := pb.RegisterUserServiceHandlerServer(globalContext, mux, userController); err != nil {
 				log.Fatalln("Failed to register server:", err)
 			}
+			if err := pb.RegisterAuditServiceHandlerServer(globalContext, mux, auditEventController); err != nil {
+				log.Fatalln("Failed to register server:", err)
+			}
+			if err := pb.RegisterTenantServiceHandlerServer(globalContext, mux, tenantController); err != nil {
+				log.Fatalln("Failed to register server:", err)
+			}
 		}).
-		AddInterceptor(errorcode.HandleErrorCodes)
+		SetHealthServer(healthRegistry.HealthServer()).
+		HandleHTTPPath(http.MethodGet, "/livez", healthRegistry.LivenessHandler).
+		HandleHTTPPath(http.MethodGet, "/readyz", healthRegistry.ReadinessHandler).
+		AddInterceptor(authenticator.Authenticate, admins.InjectAdmin, tenants.CheckTenant, errorcode.HandleErrorCodes).
+		// the HTTP middlewares wrap each other in turn, the last one runs first: the caller is authenticated
+		// before the admin and tenant checks
+		AddMiddleware(tenants.HTTPMiddleware, admins.HTTPMiddleware, authenticator.HTTPMiddleware)
 
 	server, err := server.NewServer(serverConfig)
 	if err != nil {
@@ -168,27 +524,142 @@ func startInboundServer(
 	return server
 }
 
+func mustNewGormLogger(cfg config.DatabaseLogConfig) *logger.GormLogger {
+	level, err := logger.ParseGormLogLevel(cfg.Level)
+	if err != nil {
+		log.Fatalln("Failed to create database logger:", err)
+	}
+
+	return logger.NewGormLogger(
+		logger.MustNamedLogger("gorm"),
+		logger.GormLoggerConfig{
+			LogLevel:                  level,
+			SlowThreshold:             cfg.SlowThreshold,
+			IgnoreRecordNotFoundError: cfg.IgnoreRecordNotFoundError,
+			ShowParams:                cfg.ShowParams,
+		},
+	)
+}
+
+// mustLoadKeyring loads the keyring encrypting the PII columns, nil when no keyfile is configured.
+func mustLoadKeyring(cfg config.EncryptionConfig) *encryption.Keyring {
+	if cfg.Keyfile == "" {
+		log.Warn("No encryption keyfile configured, PII columns are stored in clear text")
+		return nil
+	}
+
+	keyring, err := encryption.LoadKeyring(cfg.Keyfile)
+	if err != nil {
+		log.Fatalln("Failed to load encryption keyring:", err)
+	}
+
+	return keyring
+}
+
+func newConnectConfig(cfg config.DbConnectConfig) connect.Config {
+	return connect.Config{
+		Deadline:       cfg.Deadline,
+		InitialBackoff: cfg.InitialBackoff,
+		MaxBackoff:     cfg.MaxBackoff,
+	}
+}
+
+// connectDeadline is the time the database connection is retried on startup, to add to the start timeout.
+func connectDeadline(cfg config.DbConnectConfig) time.Duration {
+	switch {
+	case cfg.Deadline == 0:
+		return connect.DefaultDeadline
+	case cfg.Deadline < 0:
+		return 0
+	default:
+		return cfg.Deadline
+	}
+}
+
+func newTLSConfig(cfg config.DatabaseTLSConfig) dbtls.Config {
+	return dbtls.Config{
+		Enabled:                    cfg.Enabled,
+		CAFile:                     cfg.CaFile,
+		CertFile:                   cfg.CertFile,
+		KeyFile:                    cfg.KeyFile,
+		ServerName:                 cfg.ServerName,
+		SkipServerNameVerification: cfg.SkipServerNameVerification,
+	}
+}
+
+func newMysqlRepositoryConfig(cfg config.ServerConfig, keyring *encryption.Keyring) mysql.RepositoryConfig {
+	return mysql.RepositoryConfig{
+		Username:        cfg.MySql.Username,
+		Password:        cfg.MySql.Password,
+		Protocol:        cfg.MySql.Protocol,
+		Address:         cfg.MySql.Address,
+		Database:        cfg.MySql.Database,
+		TLS:             newTLSConfig(cfg.MySql.TLS),
+		ParseTime:       true,
+		CreateBatchSize: cfg.DbCreateBatchSize,
+		Connect:         newConnectConfig(cfg.DbConnect),
+		Logger:          mustNewGormLogger(cfg.DatabaseLog),
+		Keyring:         keyring,
+	}
+}
+
+func newPostgresRepositoryConfig(cfg config.ServerConfig, keyring *encryption.Keyring) postgres.RepositoryConfig {
+	timezone := "UTC"
+	return postgres.RepositoryConfig{
+		Host:            cfg.Postgres.Host,
+		Port:            cfg.Postgres.Port,
+		Username:        cfg.Postgres.Username,
+		Password:        cfg.Postgres.Password,
+		Database:        cfg.Postgres.Database,
+		SSLMode:         &cfg.Postgres.SSLMode,
+		TLS:             newTLSConfig(cfg.Postgres.TLS),
+		Timezone:        &timezone,
+		CreateBatchSize: cfg.DbCreateBatchSize,
+		Connect:         newConnectConfig(cfg.DbConnect),
+		Logger:          mustNewGormLogger(cfg.DatabaseLog),
+		Keyring:         keyring,
+	}
+}
+
+func newSqliteRepositoryConfig(cfg config.ServerConfig, keyring *encryption.Keyring) sqlite.RepositoryConfig {
+	return sqlite.RepositoryConfig{
+		DatabasePath:    cfg.Sqlite.DatabasePath,
+		BusyTimeout:     cfg.Sqlite.BusyTimeout,
+		ReaderPoolSize:  cfg.Sqlite.ReaderPoolSize,
+		CreateBatchSize: cfg.DbCreateBatchSize,
+		Logger:          mustNewGormLogger(cfg.DatabaseLog),
+		Keyring:         keyring,
+	}
+}
+
 func newServerApp() *fx.App {
 	cfg := config.MustLoadConfig[config.ServerConfig]()
 	log.Infof("Starting server with config: %+v", cfg)
 
 	return fx.New(
-		fx.StartTimeout(fx.DefaultTimeout),
+		fx.StartTimeout(fx.DefaultTimeout+connectDeadline(cfg.DbConnect)),
 		fx.StopTimeout(fx.DefaultTimeout),
 		fx.Supply(
 			cfg,
-			mysql.RepositoryConfig{
-				Username:  cfg.MySql.Username,
-				Password:  cfg.MySql.Password,
-				Protocol:  cfg.MySql.Protocol,
-				Address:   cfg.MySql.Address,
-				Database:  cfg.MySql.Database,
-				ParseTime: true,
-			},
 			usecases.LoggingWorkerConfig{
 				BufferCapacity: cfg.LoggingWorker.BufferCapacity,
 				FlushInterval:  cfg.LoggingWorker.FlushInterval,
+				MaxBacklog:     cfg.LoggingWorker.MaxBacklog,
+			},
+			usecases.OutboxRelayConfig{
+				BatchSize:    cfg.OutboxRelay.BatchSize,
+				PollInterval: cfg.OutboxRelay.PollInterval,
+				MaxAttempts:  cfg.OutboxRelay.MaxAttempts,
+			},
+			usecases.UsersConfig{
+				BatchGetMaxKeys:  cfg.Users.BatchGetMaxKeys,
+				CoalescedMethods: cfg.Users.CoalescedMethods,
+			},
+			usecases.TenantsConfig{
+				KnownTenantTTL: cfg.Tenants.KnownTenantTTL,
 			},
+			cfg.Cache,
+			cfg.Health,
 			config.ConsumerConfig{
 				PerMs: cfg.Consumer.PerMs,
 			},
@@ -196,18 +667,26 @@ func newServerApp() *fx.App {
 				LatencyMs: cfg.Client.LatencyMs,
 			},
 		),
+		provideRepositories(cfg.RepositoryBackend),
 		fx.Provide(
-			newRepository,
-			newMessageRepository,
-			newUserRepository,
-			newUserAttributeRepository,
+			newCacheBackend,
+			newCachedUserRepository,
+			newCachedUserAttributeRepository,
 			newUsersUsecase,
+			newAuditEventsUsecase,
+			newTenantsUsecase,
 			newFakeClient,
 			newLoggingWorker,
 			newController,
+			newAuditEventController,
+			newTenantController,
+			newHealthRegistry,
 		),
+		fx.Invoke(registerHealthChecks),
 		fx.Invoke(startInboundServer),
 		fx.Invoke(newFakeConsumer),
+		fx.Invoke(newOutboxRelay),
+		fx.Invoke(startHealthRegistry),
 	)
 }
 
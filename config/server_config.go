@@ -2,17 +2,61 @@ package config
 
 import "time"
 
+type DatabaseTLSConfig struct {
+	Enabled bool `env:"ENABLED" envDefault:"false"`
+	// CaFile is the PEM bundle of the CAs of the server, the system ones when empty
+	CaFile string `env:"CA_FILE"`
+	// CertFile and KeyFile are the PEM client certificate and key, none when empty
+	CertFile   string `env:"CERT_FILE"`
+	KeyFile    string `env:"KEY_FILE"`
+	ServerName string `env:"SERVER_NAME"`
+	// SkipServerNameVerification verifies the server certificate against the CAs only
+	SkipServerNameVerification bool `env:"SKIP_SERVER_NAME_VERIFICATION" envDefault:"false"`
+}
+
 type MysqlConfig struct {
-	Username string `env:"USERNAME" envDefault:"root"`
-	Password string `env:"PASSWORD" envDefault:"secret"`
-	Protocol string `env:"PROTOCOL" envDefault:"tcp"`
-	Address  string `env:"ADDRESS" envDefault:"127.0.0.1:3306"`
-	Database string `env:"DATABASE" envDefault:"test"`
+	Username string            `env:"USERNAME" envDefault:"root"`
+	Password string            `env:"PASSWORD" envDefault:"secret"`
+	Protocol string            `env:"PROTOCOL" envDefault:"tcp"`
+	Address  string            `env:"ADDRESS" envDefault:"127.0.0.1:3306"`
+	Database string            `env:"DATABASE" envDefault:"test"`
+	TLS      DatabaseTLSConfig `envPrefix:"TLS_"`
+}
+
+type PostgresConfig struct {
+	Host     string            `env:"HOST" envDefault:"127.0.0.1"`
+	Port     int               `env:"PORT" envDefault:"5432"`
+	Username string            `env:"USERNAME" envDefault:"postgres"`
+	Password string            `env:"PASSWORD" envDefault:"secret"`
+	Database string            `env:"DATABASE" envDefault:"test"`
+	SSLMode  string            `env:"SSL_MODE" envDefault:"disable"`
+	TLS      DatabaseTLSConfig `envPrefix:"TLS_"`
+}
+
+type SqliteConfig struct {
+	DatabasePath   string        `env:"DATABASE_PATH" envDefault:"data.db"`
+	BusyTimeout    time.Duration `env:"BUSY_TIMEOUT" envDefault:"5s"`
+	ReaderPoolSize int           `env:"READER_POOL_SIZE" envDefault:"4"`
+}
+
+type DbConnectConfig struct {
+	// Deadline is the total time of the connection attempts on startup, a single attempt when negative
+	Deadline       time.Duration `env:"DEADLINE" envDefault:"1m"`
+	InitialBackoff time.Duration `env:"INITIAL_BACKOFF" envDefault:"500ms"`
+	MaxBackoff     time.Duration `env:"MAX_BACKOFF" envDefault:"10s"`
+}
+
+type DatabaseLogConfig struct {
+	Level                     string        `env:"LEVEL" envDefault:"warn"`
+	SlowThreshold             time.Duration `env:"SLOW_THRESHOLD" envDefault:"200ms"`
+	IgnoreRecordNotFoundError bool          `env:"IGNORE_RECORD_NOT_FOUND_ERROR" envDefault:"true"`
+	ShowParams                bool          `env:"SHOW_PARAMS" envDefault:"false"`
 }
 
 type LoggingWorkerConfig struct {
 	BufferCapacity int           `env:"BUFFER_CAPACITY" envDefault:"10"`
 	FlushInterval  time.Duration `env:"FLUSH_INTERVAL" envDefault:"1s"`
+	MaxBacklog     int           `env:"MAX_BACKLOG" envDefault:"100"`
 }
 
 type ConsumerConfig struct {
@@ -23,12 +67,81 @@ type ClientConfig struct {
 	LatencyMs uint `env:"LATENCY_MS" envDefault:"100"`
 }
 
+type OutboxRelayConfig struct {
+	BatchSize    int           `env:"BATCH_SIZE" envDefault:"100"`
+	PollInterval time.Duration `env:"POLL_INTERVAL" envDefault:"1s"`
+	MaxAttempts  uint          `env:"MAX_ATTEMPTS" envDefault:"10"`
+}
+
+type CacheConfig struct {
+	Enabled     bool          `env:"ENABLED" envDefault:"true"`
+	Capacity    int           `env:"CAPACITY" envDefault:"10000"`
+	TTL         time.Duration `env:"TTL" envDefault:"30s"`
+	NegativeTTL time.Duration `env:"NEGATIVE_TTL" envDefault:"5s"`
+}
+
+type UsersConfig struct {
+	BatchGetMaxKeys int `env:"BATCH_GET_MAX_KEYS" envDefault:"100"`
+	// CoalescedMethods are the comma separated read methods whose concurrent identical calls share one repository call
+	CoalescedMethods []string `env:"COALESCED_METHODS" envDefault:"GetUserByUsername,GetAttributesByUsername"`
+}
+
+type TenantsConfig struct {
+	// KnownTenantTTL is how long a tenant found by the x-tenant-id check is trusted without looking it up again
+	KnownTenantTTL time.Duration `env:"KNOWN_TENANT_TTL" envDefault:"1m"`
+}
+
+type AdminConfig struct {
+	// Actors are the comma separated x-actor values of the administrators, the callers allowed to use /api/admin
+	Actors []string `env:"ACTORS"`
+}
+
+type AuthConfig struct {
+	// TrustedProxyToken is the x-proxy-token secret of the authentication proxy whose x-actor and x-tenant-id headers
+	// are trusted, no caller can claim an identity when empty
+	TrustedProxyToken string `env:"TRUSTED_PROXY_TOKEN"`
+}
+
+// String keeps the token out of the logged configuration.
+func (c AuthConfig) String() string {
+	if c.TrustedProxyToken == "" {
+		return "{TrustedProxyToken:}"
+	}
+
+	return "{TrustedProxyToken:<redacted>}"
+}
+
+type HealthConfig struct {
+	Interval time.Duration `env:"INTERVAL" envDefault:"5s"`
+	Timeout  time.Duration `env:"TIMEOUT" envDefault:"2s"`
+}
+
+type EncryptionConfig struct {
+	// Keyfile is the path of the JSON keyfile, the PII columns are stored in clear text when empty
+	Keyfile string `env:"KEYFILE"`
+}
+
 type ServerConfig struct {
 	HttpPort              int                 `env:"HTTP_PORT" envDefault:"8080"`
 	HttpServerReadTimeout time.Duration       `env:"HTTP_SERVER_READ_TIMEOUT" envDefault:"5s"`
 	GrpcPort              int                 `env:"GRPC_PORT" envDefault:"9090"`
+	RepositoryBackend     string              `env:"REPOSITORY_BACKEND" envDefault:"database"`
+	DbDriver              string              `env:"DB_DRIVER" envDefault:"mysql"`
+	DbCreateBatchSize     int                 `env:"DB_CREATE_BATCH_SIZE" envDefault:"1000"`
+	DbConnect             DbConnectConfig     `envPrefix:"DB_CONNECT_CONFIG_"`
 	MySql                 MysqlConfig         `envPrefix:"MYSQL_CONFIG_"`
+	Postgres              PostgresConfig      `envPrefix:"POSTGRES_CONFIG_"`
+	Sqlite                SqliteConfig        `envPrefix:"SQLITE_CONFIG_"`
+	DatabaseLog           DatabaseLogConfig   `envPrefix:"DATABASE_LOG_CONFIG_"`
 	LoggingWorker         LoggingWorkerConfig `envPrefix:"LOGGING_WORKER_CONFIG_"`
 	Consumer              ConsumerConfig      `envPrefix:"CONSUMER_CONFIG_"`
 	Client                ClientConfig        `envPrefix:"CLIENT_CONFIG_"`
+	OutboxRelay           OutboxRelayConfig   `envPrefix:"OUTBOX_RELAY_CONFIG_"`
+	Cache                 CacheConfig         `envPrefix:"CACHE_CONFIG_"`
+	Encryption            EncryptionConfig    `envPrefix:"ENCRYPTION_CONFIG_"`
+	Health                HealthConfig        `envPrefix:"HEALTH_CONFIG_"`
+	Users                 UsersConfig         `envPrefix:"USERS_CONFIG_"`
+	Tenants               TenantsConfig       `envPrefix:"TENANTS_CONFIG_"`
+	Admin                 AdminConfig         `envPrefix:"ADMIN_CONFIG_"`
+	Auth                  AuthConfig          `envPrefix:"AUTH_CONFIG_"`
 }
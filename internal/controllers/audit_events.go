@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"buf.build/go/protovalidate"
+	"github.com/tuantran1810/go-di-template/internal/controllers/transformers"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	pb "github.com/tuantran1810/go-di-template/pkg/go_di_template/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type AuditEventController struct {
+	pb.UnimplementedAuditServiceServer
+	auditEventUsecase     IAuditEventUsecase
+	auditEventTransformer *transformers.PbAuditEventTransformer
+}
+
+func NewAuditEventController(auditEventUsecase IAuditEventUsecase) *AuditEventController {
+	return &AuditEventController{
+		auditEventUsecase:     auditEventUsecase,
+		auditEventTransformer: transformers.NewPbAuditEventTransformer(),
+	}
+}
+
+func optionalTime(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	out := t.AsTime()
+	return &out
+}
+
+func (c *AuditEventController) ListAuditEvents(
+	ctx context.Context,
+	req *pb.ListAuditEventsRequest,
+) (*pb.ListAuditEventsResponse, error) {
+	if err := protovalidate.Validate(req); err != nil {
+		return nil, fmt.Errorf("%w - err: %w", entities.ErrInvalid, err)
+	}
+
+	events, err := c.auditEventUsecase.ListAuditEvents(ctx, entities.AuditEventFilter{
+		EntityTable: req.EntityTable,
+		EntityID:    req.EntityId,
+		Actor:       req.Actor,
+		From:        optionalTime(req.From),
+		To:          optionalTime(req.To),
+		Offset:      int(req.Offset),
+		Limit:       int(req.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pbEvents, err := c.auditEventTransformer.FromEntityArray_I2P(events)
+	if err != nil {
+		return nil, fmt.Errorf("%w - cannot transform to pb audit events, err: %w", entities.ErrInvalid, err)
+	}
+
+	return &pb.ListAuditEventsResponse{
+		Events: pbEvents,
+	}, nil
+}
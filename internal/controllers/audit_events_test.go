@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	mocks "github.com/tuantran1810/go-di-template/mocks/controllers"
+	pb "github.com/tuantran1810/go-di-template/pkg/go_di_template/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAuditEventController_ListAuditEvents(t *testing.T) {
+	t.Parallel()
+	now := time.Now().UTC()
+	from := now.Add(-time.Hour)
+
+	mockAuditEventUsecase := mocks.NewMockIAuditEventUsecase(t)
+	mockAuditEventUsecase.EXPECT().
+		ListAuditEvents(mock.Anything, entities.AuditEventFilter{
+			EntityTable: "users",
+			EntityID:    "1",
+			Actor:       "alice",
+			From:        &from,
+			To:          &now,
+			Limit:       10,
+		}).
+		Return([]entities.AuditEvent{
+			{
+				ID:          1,
+				CreatedAt:   now,
+				EntityTable: "users",
+				EntityID:    "1",
+				Operation:   entities.AuditOperationDelete,
+				Actor:       "alice",
+			},
+		}, nil)
+	mockAuditEventUsecase.EXPECT().
+		ListAuditEvents(mock.Anything, entities.AuditEventFilter{Actor: "failed"}).
+		Return(nil, entities.ErrDatabase)
+
+	controller := NewAuditEventController(mockAuditEventUsecase)
+
+	tests := []struct {
+		name    string
+		req     *pb.ListAuditEventsRequest
+		want    *pb.ListAuditEventsResponse
+		wantErr error
+	}{
+		{
+			name: "success",
+			req: &pb.ListAuditEventsRequest{
+				EntityTable: "users",
+				EntityId:    "1",
+				Actor:       "alice",
+				From:        utils.ToTimepb(from),
+				To:          utils.ToTimepb(now),
+				Limit:       10,
+			},
+			want: &pb.ListAuditEventsResponse{
+				Events: []*pb.AuditEvent{
+					{
+						Id:          1,
+						CreatedAt:   utils.ToTimepb(now),
+						EntityTable: "users",
+						EntityId:    "1",
+						Operation:   entities.AuditOperationDelete,
+						Actor:       "alice",
+					},
+				},
+			},
+		},
+		{
+			name:    "usecase error",
+			req:     &pb.ListAuditEventsRequest{Actor: "failed"},
+			wantErr: entities.ErrDatabase,
+		},
+		{
+			name:    "invalid limit",
+			req:     &pb.ListAuditEventsRequest{Limit: 1001},
+			wantErr: entities.ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := controller.ListAuditEvents(context.Background(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AuditEventController.ListAuditEvents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("AuditEventController.ListAuditEvents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
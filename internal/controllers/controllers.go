@@ -8,8 +8,19 @@ import (
 
 type IUserUsecase interface {
 	CreateUser(ctx context.Context, user *entities.User, attributes []entities.KeyValuePair) (*entities.User, []entities.UserAttribute, error)
-	GetUserByUsername(ctx context.Context, username string) (*entities.User, []entities.UserAttribute, error)
+	GetUserByUsername(ctx context.Context, username string, showDeleted bool) (*entities.User, []entities.UserAttribute, error)
+	BatchGetUsers(ctx context.Context, usernames, uuids []string, showDeleted bool) ([]entities.User, []string, error)
 	GetAttributesByUsername(ctx context.Context, username string) ([]entities.UserAttribute, error)
+	GetUserStats(ctx context.Context, query entities.UserStatsQuery) (*entities.UserStats, error)
+}
+
+type IAuditEventUsecase interface {
+	ListAuditEvents(ctx context.Context, filter entities.AuditEventFilter) ([]entities.AuditEvent, error)
+}
+
+type ITenantUsecase interface {
+	CreateTenant(ctx context.Context, tenant *entities.Tenant) (*entities.Tenant, error)
+	ListTenants(ctx context.Context, offset, limit int, showDeleted bool) ([]entities.Tenant, error)
 }
 
 type ILoggingWorker interface {
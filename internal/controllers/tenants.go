@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"buf.build/go/protovalidate"
+	"github.com/tuantran1810/go-di-template/internal/controllers/transformers"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	pb "github.com/tuantran1810/go-di-template/pkg/go_di_template/v1"
+)
+
+type TenantController struct {
+	pb.UnimplementedTenantServiceServer
+	tenantUsecase     ITenantUsecase
+	tenantTransformer *transformers.PbTenantTransformer
+}
+
+func NewTenantController(tenantUsecase ITenantUsecase) *TenantController {
+	return &TenantController{
+		tenantUsecase:     tenantUsecase,
+		tenantTransformer: transformers.NewPbTenantTransformer(),
+	}
+}
+
+// requireAdmin rejects the callers which are not administrators, for the APIs under /api/admin.
+func requireAdmin(ctx context.Context, method string) error {
+	if !utils.IsAdmin(ctx) {
+		return fmt.Errorf("%w - %s is reserved to the administrators", entities.ErrForbidden, method)
+	}
+
+	return nil
+}
+
+func (c *TenantController) CreateTenant(
+	ctx context.Context,
+	req *pb.CreateTenantRequest,
+) (*pb.CreateTenantResponse, error) {
+	if err := requireAdmin(ctx, "CreateTenant"); err != nil {
+		return nil, err
+	}
+	if err := protovalidate.Validate(req); err != nil {
+		return nil, fmt.Errorf("%w - err: %w", entities.ErrInvalid, err)
+	}
+
+	tenant, err := c.tenantTransformer.ToEntity(req.Tenant)
+	if err != nil {
+		return nil, fmt.Errorf("%w - cannot transform to tenant entity, err: %w", entities.ErrInvalid, err)
+	}
+
+	tenant, err = c.tenantUsecase.CreateTenant(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	pbTenant, err := c.tenantTransformer.FromEntity(tenant)
+	if err != nil {
+		return nil, fmt.Errorf("%w - cannot transform to pb tenant, err: %w", entities.ErrInvalid, err)
+	}
+
+	return &pb.CreateTenantResponse{
+		Tenant: pbTenant,
+	}, nil
+}
+
+func (c *TenantController) ListTenants(
+	ctx context.Context,
+	req *pb.ListTenantsRequest,
+) (*pb.ListTenantsResponse, error) {
+	if err := requireAdmin(ctx, "ListTenants"); err != nil {
+		return nil, err
+	}
+	if err := protovalidate.Validate(req); err != nil {
+		return nil, fmt.Errorf("%w - err: %w", entities.ErrInvalid, err)
+	}
+
+	tenants, err := c.tenantUsecase.ListTenants(ctx, int(req.Offset), int(req.Limit), req.ShowDeleted)
+	if err != nil {
+		return nil, err
+	}
+
+	pbTenants, err := c.tenantTransformer.FromEntityArray_I2P(tenants)
+	if err != nil {
+		return nil, fmt.Errorf("%w - cannot transform to pb tenants, err: %w", entities.ErrInvalid, err)
+	}
+
+	return &pb.ListTenantsResponse{
+		Tenants: pbTenants,
+	}, nil
+}
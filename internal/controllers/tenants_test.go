@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	mocks "github.com/tuantran1810/go-di-template/mocks/controllers"
+	pb "github.com/tuantran1810/go-di-template/pkg/go_di_template/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTenantController_CreateTenant(t *testing.T) {
+	t.Parallel()
+	now := time.Now().UTC()
+
+	mockTenantUsecase := mocks.NewMockITenantUsecase(t)
+	mockTenantUsecase.EXPECT().
+		CreateTenant(mock.Anything, &entities.Tenant{Name: "tenant2"}).
+		Return(&entities.Tenant{ID: 2, CreatedAt: now, UpdatedAt: now, Name: "tenant2"}, nil)
+	mockTenantUsecase.EXPECT().
+		CreateTenant(mock.Anything, &entities.Tenant{Name: "failed"}).
+		Return(nil, entities.ErrDatabase)
+
+	controller := NewTenantController(mockTenantUsecase)
+
+	tests := []struct {
+		name     string
+		req      *pb.CreateTenantRequest
+		notAdmin bool
+		want     *pb.CreateTenantResponse
+		wantErr  error
+	}{
+		{
+			name: "success",
+			req:  &pb.CreateTenantRequest{Tenant: &pb.Tenant{Name: "tenant2"}},
+			want: &pb.CreateTenantResponse{
+				Tenant: &pb.Tenant{
+					Id:        2,
+					CreatedAt: utils.ToTimepb(now),
+					UpdatedAt: utils.ToTimepb(now),
+					Name:      "tenant2",
+				},
+			},
+		},
+		{
+			name:    "usecase error",
+			req:     &pb.CreateTenantRequest{Tenant: &pb.Tenant{Name: "failed"}},
+			wantErr: entities.ErrDatabase,
+		},
+		{
+			name:    "empty name",
+			req:     &pb.CreateTenantRequest{Tenant: &pb.Tenant{}},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name:    "no tenant",
+			req:     &pb.CreateTenantRequest{},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name:     "not an admin",
+			req:      &pb.CreateTenantRequest{Tenant: &pb.Tenant{Name: "tenant2"}},
+			notAdmin: true,
+			wantErr:  entities.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := utils.InjectAdminToContext(context.Background())
+			if tt.notAdmin {
+				ctx = context.Background()
+			}
+			got, err := controller.CreateTenant(ctx, tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TenantController.CreateTenant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("TenantController.CreateTenant() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTenantController_ListTenants(t *testing.T) {
+	t.Parallel()
+	now := time.Now().UTC()
+
+	mockTenantUsecase := mocks.NewMockITenantUsecase(t)
+	mockTenantUsecase.EXPECT().
+		ListTenants(mock.Anything, 0, 10, false).
+		Return([]entities.Tenant{{ID: 1, CreatedAt: now, UpdatedAt: now, Name: entities.DefaultTenantName}}, nil)
+	mockTenantUsecase.EXPECT().
+		ListTenants(mock.Anything, 5, 0, false).
+		Return(nil, entities.ErrDatabase)
+	deletedAt := now.Add(-time.Hour)
+	mockTenantUsecase.EXPECT().
+		ListTenants(mock.Anything, 0, 0, true).
+		Return([]entities.Tenant{{ID: 2, CreatedAt: now, UpdatedAt: now, DeletedAt: &deletedAt, Name: "deleted"}}, nil)
+
+	controller := NewTenantController(mockTenantUsecase)
+
+	tests := []struct {
+		name     string
+		req      *pb.ListTenantsRequest
+		notAdmin bool
+		want     *pb.ListTenantsResponse
+		wantErr  error
+	}{
+		{
+			name: "success",
+			req:  &pb.ListTenantsRequest{Limit: 10},
+			want: &pb.ListTenantsResponse{
+				Tenants: []*pb.Tenant{
+					{
+						Id:        1,
+						CreatedAt: utils.ToTimepb(now),
+						UpdatedAt: utils.ToTimepb(now),
+						Name:      entities.DefaultTenantName,
+					},
+				},
+			},
+		},
+		{
+			name: "show deleted",
+			req:  &pb.ListTenantsRequest{ShowDeleted: true},
+			want: &pb.ListTenantsResponse{
+				Tenants: []*pb.Tenant{
+					{
+						Id:        2,
+						CreatedAt: utils.ToTimepb(now),
+						UpdatedAt: utils.ToTimepb(now),
+						DeletedAt: utils.ToTimepb(deletedAt),
+						Name:      "deleted",
+					},
+				},
+			},
+		},
+		{
+			name:    "usecase error",
+			req:     &pb.ListTenantsRequest{Offset: 5},
+			wantErr: entities.ErrDatabase,
+		},
+		{
+			name:    "invalid limit",
+			req:     &pb.ListTenantsRequest{Limit: 1001},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name:     "not an admin",
+			req:      &pb.ListTenantsRequest{ShowDeleted: true},
+			notAdmin: true,
+			wantErr:  entities.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := utils.InjectAdminToContext(context.Background())
+			if tt.notAdmin {
+				ctx = context.Background()
+			}
+			got, err := controller.ListTenants(ctx, tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TenantController.ListTenants() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("TenantController.ListTenants() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
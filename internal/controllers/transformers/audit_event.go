@@ -0,0 +1,75 @@
+package transformers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	pb "github.com/tuantran1810/go-di-template/pkg/go_di_template/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type pbAuditEventTransformer struct{}
+type PbAuditEventTransformer = entities.ExtendedDataTransformer[pb.AuditEvent, entities.AuditEvent]
+
+func NewPbAuditEventTransformer() *PbAuditEventTransformer {
+	return entities.NewExtendedDataTransformer(&pbAuditEventTransformer{})
+}
+
+func (t *pbAuditEventTransformer) ToEntity(data *pb.AuditEvent) (*entities.AuditEvent, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var changes map[string]entities.AuditChange
+	if data.Changes != nil {
+		raw, err := data.Changes.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode changes: %w", err)
+		}
+		if err := json.Unmarshal(raw, &changes); err != nil {
+			return nil, fmt.Errorf("failed to decode changes: %w", err)
+		}
+	}
+
+	return &entities.AuditEvent{
+		ID:            uint(data.Id),
+		CreatedAt:     utils.FromTimepb(data.CreatedAt),
+		EntityTable:   data.EntityTable,
+		EntityID:      data.EntityId,
+		Operation:     data.Operation,
+		Changes:       changes,
+		Actor:         data.Actor,
+		CorrelationID: data.CorrelationId,
+	}, nil
+}
+
+func (t *pbAuditEventTransformer) FromEntity(entity *entities.AuditEvent) (*pb.AuditEvent, error) {
+	if entity == nil {
+		return nil, nil
+	}
+
+	var changes *structpb.Struct
+	if entity.Changes != nil {
+		raw, err := json.Marshal(entity.Changes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode changes: %w", err)
+		}
+		changes = &structpb.Struct{}
+		if err := changes.UnmarshalJSON(raw); err != nil {
+			return nil, fmt.Errorf("failed to decode changes: %w", err)
+		}
+	}
+
+	return &pb.AuditEvent{
+		Id:            uint32(entity.ID),
+		CreatedAt:     utils.ToTimepb(entity.CreatedAt),
+		EntityTable:   entity.EntityTable,
+		EntityId:      entity.EntityID,
+		Operation:     entity.Operation,
+		Changes:       changes,
+		Actor:         entity.Actor,
+		CorrelationId: entity.CorrelationID,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package transformers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	pb "github.com/tuantran1810/go-di-template/pkg/go_di_template/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestPbAuditEventTransformer(t *testing.T) {
+	t.Parallel()
+	now := time.Now().UTC()
+
+	changes, err := structpb.NewStruct(map[string]any{
+		"name":     map[string]any{"before": "name1", "after": "name2"},
+		"password": map[string]any{"before": nil, "after": "[REDACTED]"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create changes: %v", err)
+	}
+
+	tr := &pbAuditEventTransformer{}
+
+	tests := []struct {
+		name   string
+		pb     *pb.AuditEvent
+		entity *entities.AuditEvent
+	}{
+		{
+			name: "success",
+			pb: &pb.AuditEvent{
+				Id:            1,
+				CreatedAt:     utils.ToTimepb(now),
+				EntityTable:   "users",
+				EntityId:      "1",
+				Operation:     "update",
+				Changes:       changes,
+				Actor:         "alice",
+				CorrelationId: "correlation-1",
+			},
+			entity: &entities.AuditEvent{
+				ID:          1,
+				CreatedAt:   now,
+				EntityTable: "users",
+				EntityID:    "1",
+				Operation:   "update",
+				Changes: map[string]entities.AuditChange{
+					"name":     {Before: "name1", After: "name2"},
+					"password": {Before: nil, After: "[REDACTED]"},
+				},
+				Actor:         "alice",
+				CorrelationID: "correlation-1",
+			},
+		},
+		{
+			name:   "nil input",
+			pb:     nil,
+			entity: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotEntity, err := tr.ToEntity(tt.pb)
+			if err != nil {
+				t.Fatalf("PbAuditEventTransformer.ToEntity() error = %v", err)
+			}
+			if !reflect.DeepEqual(gotEntity, tt.entity) {
+				t.Errorf("PbAuditEventTransformer.ToEntity() = %v, want %v", gotEntity, tt.entity)
+			}
+
+			gotPb, err := tr.FromEntity(tt.entity)
+			if err != nil {
+				t.Fatalf("PbAuditEventTransformer.FromEntity() error = %v", err)
+			}
+			if !proto.Equal(gotPb, tt.pb) {
+				t.Errorf("PbAuditEventTransformer.FromEntity() = %v, want %v", gotPb, tt.pb)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package transformers
+
+import (
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	pb "github.com/tuantran1810/go-di-template/pkg/go_di_template/v1"
+)
+
+type pbTenantTransformer struct{}
+type PbTenantTransformer = entities.ExtendedDataTransformer[pb.Tenant, entities.Tenant]
+
+func NewPbTenantTransformer() *PbTenantTransformer {
+	return entities.NewExtendedDataTransformer(&pbTenantTransformer{})
+}
+
+func (t *pbTenantTransformer) ToEntity(tenant *pb.Tenant) (*entities.Tenant, error) {
+	if tenant == nil {
+		return nil, nil
+	}
+
+	return &entities.Tenant{
+		ID:        uint(tenant.Id),
+		CreatedAt: utils.FromTimepb(tenant.CreatedAt),
+		UpdatedAt: utils.FromTimepb(tenant.UpdatedAt),
+		DeletedAt: utils.FromOptionalTimepb(tenant.DeletedAt),
+		Name:      tenant.Name,
+	}, nil
+}
+
+func (t *pbTenantTransformer) FromEntity(tenant *entities.Tenant) (*pb.Tenant, error) {
+	if tenant == nil {
+		return nil, nil
+	}
+
+	return &pb.Tenant{
+		Id:        uint32(tenant.ID),
+		CreatedAt: utils.ToTimepb(tenant.CreatedAt),
+		UpdatedAt: utils.ToTimepb(tenant.UpdatedAt),
+		DeletedAt: utils.ToOptionalTimepb(tenant.DeletedAt),
+		Name:      tenant.Name,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package transformers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	pb "github.com/tuantran1810/go-di-template/pkg/go_di_template/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPbTenantTransformer(t *testing.T) {
+	t.Parallel()
+	now := time.Now().UTC()
+
+	tr := &pbTenantTransformer{}
+
+	tests := []struct {
+		name   string
+		pb     *pb.Tenant
+		entity *entities.Tenant
+	}{
+		{
+			name: "success",
+			pb: &pb.Tenant{
+				Id:        2,
+				CreatedAt: utils.ToTimepb(now),
+				UpdatedAt: utils.ToTimepb(now),
+				Name:      "tenant2",
+			},
+			entity: &entities.Tenant{
+				ID:        2,
+				CreatedAt: now,
+				UpdatedAt: now,
+				Name:      "tenant2",
+			},
+		},
+		{
+			name:   "nil input",
+			pb:     nil,
+			entity: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotEntity, err := tr.ToEntity(tt.pb)
+			if err != nil {
+				t.Fatalf("PbTenantTransformer.ToEntity() error = %v", err)
+			}
+			if !reflect.DeepEqual(gotEntity, tt.entity) {
+				t.Errorf("PbTenantTransformer.ToEntity() = %v, want %v", gotEntity, tt.entity)
+			}
+
+			gotPb, err := tr.FromEntity(tt.entity)
+			if err != nil {
+				t.Fatalf("PbTenantTransformer.FromEntity() error = %v", err)
+			}
+			if !proto.Equal(gotPb, tt.pb) {
+				t.Errorf("PbTenantTransformer.FromEntity() = %v, want %v", gotPb, tt.pb)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ func (t *pbUserTransformer) ToEntity(user *pb.User) (*entities.User, error) {
 		ID:        uint(user.Id),
 		CreatedAt: utils.FromTimepb(user.CreatedAt),
 		UpdatedAt: utils.FromTimepb(user.UpdatedAt),
+		DeletedAt: utils.FromOptionalTimepb(user.DeletedAt),
 		Username:  user.Username,
 		Password:  user.Password,
 		Uuid:      user.Uuid,
@@ -39,6 +40,7 @@ func (t *pbUserTransformer) FromEntity(user *entities.User) (*pb.User, error) {
 		Id:        uint32(user.ID),
 		CreatedAt: utils.ToTimepb(user.CreatedAt),
 		UpdatedAt: utils.ToTimepb(user.UpdatedAt),
+		DeletedAt: utils.ToOptionalTimepb(user.DeletedAt),
 		Username:  user.Username,
 		Password:  user.Password,
 		Uuid:      user.Uuid,
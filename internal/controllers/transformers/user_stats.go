@@ -0,0 +1,56 @@
+package transformers
+
+import (
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	pb "github.com/tuantran1810/go-di-template/pkg/go_di_template/v1"
+)
+
+// ToTimeBucket returns the time bucket of the pb enum, empty for the unspecified one.
+func ToTimeBucket(bucket pb.TimeBucket) entities.TimeBucket {
+	switch bucket {
+	case pb.TimeBucket_TIME_BUCKET_DAY:
+		return entities.TimeBucketDay
+	case pb.TimeBucket_TIME_BUCKET_WEEK:
+		return entities.TimeBucketWeek
+	case pb.TimeBucket_TIME_BUCKET_MONTH:
+		return entities.TimeBucketMonth
+	default:
+		return ""
+	}
+}
+
+type pbSignupCountTransformer struct{}
+type PbSignupCountTransformer = entities.ExtendedDataTransformer[pb.SignupCount, entities.SignupCount]
+
+func NewPbSignupCountTransformer() *PbSignupCountTransformer {
+	return entities.NewExtendedDataTransformer(&pbSignupCountTransformer{})
+}
+
+func (t *pbSignupCountTransformer) ToEntity(count *pb.SignupCount) (*entities.SignupCount, error) {
+	if count == nil {
+		return nil, nil
+	}
+
+	return &entities.SignupCount{
+		BucketStart: utils.FromTimepb(count.BucketStart),
+		Count:       count.Count,
+	}, nil
+}
+
+func (t *pbSignupCountTransformer) FromEntity(count *entities.SignupCount) (*pb.SignupCount, error) {
+	if count == nil {
+		return nil, nil
+	}
+
+	return &pb.SignupCount{
+		BucketStart: utils.ToTimepb(count.BucketStart),
+		Count:       count.Count,
+	}, nil
+}
+
+type PbAttributeValueCountTransformer = entities.ExtendedDataTransformer[pb.AttributeValueCount, entities.AttributeValueCount]
+
+func NewPbAttributeValueCountTransformer() *PbAttributeValueCountTransformer {
+	return entities.NewBaseExtendedTransformer[pb.AttributeValueCount, entities.AttributeValueCount]()
+}
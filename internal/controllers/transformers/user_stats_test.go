@@ -0,0 +1,81 @@
+package transformers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	pb "github.com/tuantran1810/go-di-template/pkg/go_di_template/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestToTimeBucket(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		bucket pb.TimeBucket
+		want   entities.TimeBucket
+	}{
+		{name: "unspecified", bucket: pb.TimeBucket_TIME_BUCKET_UNSPECIFIED, want: ""},
+		{name: "day", bucket: pb.TimeBucket_TIME_BUCKET_DAY, want: entities.TimeBucketDay},
+		{name: "week", bucket: pb.TimeBucket_TIME_BUCKET_WEEK, want: entities.TimeBucketWeek},
+		{name: "month", bucket: pb.TimeBucket_TIME_BUCKET_MONTH, want: entities.TimeBucketMonth},
+		{name: "unknown", bucket: pb.TimeBucket(42), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ToTimeBucket(tt.bucket); got != tt.want {
+				t.Errorf("ToTimeBucket() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPbSignupCountTransformer(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)
+
+	tr := &pbSignupCountTransformer{}
+
+	tests := []struct {
+		name   string
+		pb     *pb.SignupCount
+		entity *entities.SignupCount
+	}{
+		{
+			name:   "success",
+			pb:     &pb.SignupCount{BucketStart: utils.ToTimepb(start), Count: 3},
+			entity: &entities.SignupCount{BucketStart: start, Count: 3},
+		},
+		{
+			name:   "nil input",
+			pb:     nil,
+			entity: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotEntity, err := tr.ToEntity(tt.pb)
+			if err != nil {
+				t.Fatalf("PbSignupCountTransformer.ToEntity() error = %v", err)
+			}
+			if !reflect.DeepEqual(gotEntity, tt.entity) {
+				t.Errorf("PbSignupCountTransformer.ToEntity() = %v, want %v", gotEntity, tt.entity)
+			}
+
+			gotPb, err := tr.FromEntity(tt.entity)
+			if err != nil {
+				t.Fatalf("PbSignupCountTransformer.FromEntity() error = %v", err)
+			}
+			if !proto.Equal(gotPb, tt.pb) {
+				t.Errorf("PbSignupCountTransformer.FromEntity() = %v, want %v", gotPb, tt.pb)
+			}
+		})
+	}
+}
@@ -106,6 +106,24 @@ func TestPbUserTransformer_FromEntity(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "deleted",
+			user: &entities.User{
+				ID:        uint(1),
+				CreatedAt: now,
+				UpdatedAt: now,
+				DeletedAt: &now,
+				Username:  "test",
+			},
+			want: &pb.User{
+				Id:        uint32(1),
+				CreatedAt: utils.ToTimepb(now),
+				UpdatedAt: utils.ToTimepb(now),
+				DeletedAt: utils.ToTimepb(now),
+				Username:  "test",
+			},
+			wantErr: false,
+		},
 		{
 			name:    "nil input",
 			user:    nil,
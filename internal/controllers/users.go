@@ -12,11 +12,13 @@ import (
 
 type UserController struct {
 	pb.UnimplementedUserServiceServer
-	userUsecase              IUserUsecase
-	loggingWorker            ILoggingWorker
-	userTransformer          *transformers.PbUserTransformer
-	keyValuePairTransformer  *entities.ExtendedDataTransformer[pb.KeyValuePair, entities.KeyValuePair]
-	userAttributeTransformer *transformers.PbUserAttributesTransformer
+	userUsecase               IUserUsecase
+	loggingWorker             ILoggingWorker
+	userTransformer           *transformers.PbUserTransformer
+	keyValuePairTransformer   *entities.ExtendedDataTransformer[pb.KeyValuePair, entities.KeyValuePair]
+	userAttributeTransformer  *transformers.PbUserAttributesTransformer
+	signupCountTransformer    *transformers.PbSignupCountTransformer
+	attributeCountTransformer *transformers.PbAttributeValueCountTransformer
 }
 
 func NewUserController(
@@ -24,11 +26,13 @@ func NewUserController(
 	loggingWorker ILoggingWorker,
 ) *UserController {
 	return &UserController{
-		userUsecase:              userUsecase,
-		loggingWorker:            loggingWorker,
-		userTransformer:          transformers.NewPbUserTransformer(),
-		keyValuePairTransformer:  entities.NewBaseExtendedTransformer[pb.KeyValuePair, entities.KeyValuePair](),
-		userAttributeTransformer: transformers.NewPbUserAttributesTransformer(),
+		userUsecase:               userUsecase,
+		loggingWorker:             loggingWorker,
+		userTransformer:           transformers.NewPbUserTransformer(),
+		keyValuePairTransformer:   entities.NewBaseExtendedTransformer[pb.KeyValuePair, entities.KeyValuePair](),
+		userAttributeTransformer:  transformers.NewPbUserAttributesTransformer(),
+		signupCountTransformer:    transformers.NewPbSignupCountTransformer(),
+		attributeCountTransformer: transformers.NewPbAttributeValueCountTransformer(),
 	}
 }
 
@@ -69,11 +73,6 @@ func (c *UserController) CreateUser(
 		return nil, fmt.Errorf("%w - cannot transform to pb user attributes, err: %w", entities.ErrInvalid, err)
 	}
 
-	c.loggingWorker.Inject(entities.Message{
-		Key:   "user_created",
-		Value: fmt.Sprintf("user_id: %d, username: %s", outUser.ID, outUser.Username),
-	})
-
 	return &pb.CreateUserResponse{
 		User:       pbUser,
 		Attributes: pbAttributes,
@@ -88,7 +87,13 @@ func (c *UserController) GetUserByUsername(
 		return nil, fmt.Errorf("%w - err: %w", entities.ErrInvalid, err)
 	}
 
-	user, atts, err := c.userUsecase.GetUserByUsername(ctx, req.Username)
+	if req.ShowDeleted {
+		if err := requireAdmin(ctx, "show_deleted"); err != nil {
+			return nil, err
+		}
+	}
+
+	user, atts, err := c.userUsecase.GetUserByUsername(ctx, req.Username, req.ShowDeleted)
 	if err != nil {
 		return nil, fmt.Errorf("%w - cannot get user by username, err: %w", entities.ErrInvalid, err)
 	}
@@ -104,8 +109,9 @@ func (c *UserController) GetUserByUsername(
 	}
 
 	c.loggingWorker.Inject(entities.Message{
-		Key:   "user_get",
-		Value: fmt.Sprintf("user_id: %d, username: %s", user.ID, user.Username),
+		TenantID: entities.GetTenantIDFromContext(ctx),
+		Key:      "user_get",
+		Value:    fmt.Sprintf("user_id: %d, username: %s", user.ID, user.Username),
 	})
 
 	return &pb.GetUserByUsernameResponse{
@@ -114,6 +120,55 @@ func (c *UserController) GetUserByUsername(
 	}, nil
 }
 
+func (c *UserController) BatchGetUsers(
+	ctx context.Context,
+	req *pb.BatchGetUsersRequest,
+) (*pb.BatchGetUsersResponse, error) {
+	if err := protovalidate.Validate(req); err != nil {
+		return nil, fmt.Errorf("%w - err: %w", entities.ErrInvalid, err)
+	}
+
+	if req.ShowDeleted {
+		if err := requireAdmin(ctx, "show_deleted"); err != nil {
+			return nil, err
+		}
+	}
+
+	users, missing, err := c.userUsecase.BatchGetUsers(ctx, req.Usernames, req.Uuids, req.ShowDeleted)
+	if err != nil {
+		return nil, err
+	}
+
+	pbUsers := make([]*pb.UserWithAttributes, len(users))
+	for i := range users {
+		pbUser, err := c.userTransformer.FromEntity(&users[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w - cannot transform to pb user, err: %w", entities.ErrInvalid, err)
+		}
+
+		pbAttributes, err := c.userAttributeTransformer.FromEntityArray_I2P(users[i].Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("%w - cannot transform to pb user attributes, err: %w", entities.ErrInvalid, err)
+		}
+
+		pbUsers[i] = &pb.UserWithAttributes{
+			User:       pbUser,
+			Attributes: pbAttributes,
+		}
+	}
+
+	c.loggingWorker.Inject(entities.Message{
+		TenantID: entities.GetTenantIDFromContext(ctx),
+		Key:      "users_batch_get",
+		Value:    fmt.Sprintf("found: %d, missing: %d", len(users), len(missing)),
+	})
+
+	return &pb.BatchGetUsersResponse{
+		Users:       pbUsers,
+		MissingKeys: missing,
+	}, nil
+}
+
 func (c *UserController) GetAttributesByUsername(
 	ctx context.Context,
 	req *pb.GetAttributesByUsernameRequest,
@@ -133,11 +188,52 @@ func (c *UserController) GetAttributesByUsername(
 	}
 
 	c.loggingWorker.Inject(entities.Message{
-		Key:   "user_attributes_get",
-		Value: fmt.Sprintf("username: %s", req.Username),
+		TenantID: entities.GetTenantIDFromContext(ctx),
+		Key:      "user_attributes_get",
+		Value:    fmt.Sprintf("username: %s", req.Username),
 	})
 
 	return &pb.GetAttributesByUsernameResponse{
 		Attributes: pbAttributes,
 	}, nil
 }
+
+func (c *UserController) GetUserStats(
+	ctx context.Context,
+	req *pb.GetUserStatsRequest,
+) (*pb.GetUserStatsResponse, error) {
+	if err := protovalidate.Validate(req); err != nil {
+		return nil, fmt.Errorf("%w - err: %w", entities.ErrInvalid, err)
+	}
+
+	query := entities.UserStatsQuery{
+		Bucket:       transformers.ToTimeBucket(req.Bucket),
+		AttributeKey: req.AttributeKey,
+	}
+	if req.From != nil {
+		query.From = req.From.AsTime()
+	}
+	if req.To != nil {
+		query.To = req.To.AsTime()
+	}
+
+	stats, err := c.userUsecase.GetUserStats(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	pbSignups, err := c.signupCountTransformer.FromEntityArray_I2P(stats.Signups)
+	if err != nil {
+		return nil, fmt.Errorf("%w - cannot transform to pb signup counts, err: %w", entities.ErrInvalid, err)
+	}
+
+	pbAttributeValues, err := c.attributeCountTransformer.FromEntityArray_I2P(stats.AttributeValues)
+	if err != nil {
+		return nil, fmt.Errorf("%w - cannot transform to pb attribute value counts, err: %w", entities.ErrInvalid, err)
+	}
+
+	return &pb.GetUserStatsResponse{
+		Signups:         pbSignups,
+		AttributeValues: pbAttributeValues,
+	}, nil
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -86,13 +87,8 @@ func TestUserController_CreateUser(t *testing.T) {
 		},
 	).Return(nil, nil, fmt.Errorf("fake error"))
 
+	// the user_created event goes through the outbox, nothing is injected here
 	mockLoggingWorker := mocks.NewMockILoggingWorker(t)
-	mockLoggingWorker.EXPECT().
-		Inject(entities.Message{
-			Key:   "user_created",
-			Value: "user_id: 1, username: test1",
-		}).
-		Return()
 
 	c := &UserController{
 		userUsecase:              mockUserUsecase,
@@ -222,7 +218,7 @@ func TestUserController_GetUserByUsername(t *testing.T) {
 
 	mockUserUsecase := mocks.NewMockIUserUsecase(t)
 	mockUserUsecase.EXPECT().
-		GetUserByUsername(mock.Anything, "test1").
+		GetUserByUsername(mock.Anything, "test1", false).
 		Return(&entities.User{
 			ID:        1,
 			CreatedAt: now,
@@ -252,14 +248,35 @@ func TestUserController_GetUserByUsername(t *testing.T) {
 		}, nil)
 
 	mockUserUsecase.EXPECT().
-		GetUserByUsername(mock.Anything, "test_failed").
+		GetUserByUsername(mock.Anything, "test_failed", false).
 		Return(nil, nil, fmt.Errorf("fake error"))
 
+	deletedAt := now.Add(-time.Hour)
+	mockUserUsecase.EXPECT().
+		GetUserByUsername(mock.Anything, "deleted", true).
+		Return(&entities.User{
+			ID:        4,
+			CreatedAt: now,
+			UpdatedAt: now,
+			DeletedAt: &deletedAt,
+			Username:  "deleted",
+			Uuid:      "deleted",
+			Name:      "deleted",
+		}, []entities.UserAttribute{}, nil)
+
 	mockLoggingWorker := mocks.NewMockILoggingWorker(t)
 	mockLoggingWorker.EXPECT().
 		Inject(entities.Message{
-			Key:   "user_get",
-			Value: "user_id: 1, username: test1",
+			TenantID: entities.DefaultTenantID,
+			Key:      "user_get",
+			Value:    "user_id: 1, username: test1",
+		}).
+		Return()
+	mockLoggingWorker.EXPECT().
+		Inject(entities.Message{
+			TenantID: entities.DefaultTenantID,
+			Key:      "user_get",
+			Value:    "user_id: 4, username: deleted",
 		}).
 		Return()
 
@@ -274,6 +291,7 @@ func TestUserController_GetUserByUsername(t *testing.T) {
 	tests := []struct {
 		name    string
 		req     *pb.GetUserByUsernameRequest
+		admin   bool
 		want    *pb.GetUserByUsernameResponse
 		wantErr bool
 	}{
@@ -336,11 +354,35 @@ func TestUserController_GetUserByUsername(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "deleted user",
+			req: &pb.GetUserByUsernameRequest{
+				Username:    "deleted",
+				ShowDeleted: true,
+			},
+			admin: true,
+			want: &pb.GetUserByUsernameResponse{
+				User: &pb.User{
+					Id:        4,
+					CreatedAt: utils.ToTimepb(now),
+					UpdatedAt: utils.ToTimepb(now),
+					DeletedAt: utils.ToTimepb(deletedAt),
+					Uuid:      "deleted",
+					Username:  "deleted",
+					Name:      "deleted",
+				},
+				Attributes: []*pb.UserAttribute{},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got, err := c.GetUserByUsername(context.TODO(), tt.req)
+			ctx := context.TODO()
+			if tt.admin {
+				ctx = utils.InjectAdminToContext(ctx)
+			}
+			got, err := c.GetUserByUsername(ctx, tt.req)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UserController.GetUserByUsername() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -352,6 +394,163 @@ func TestUserController_GetUserByUsername(t *testing.T) {
 	}
 }
 
+func TestUserController_BatchGetUsers(t *testing.T) {
+	t.Parallel()
+	now := time.Now().UTC()
+
+	mockUserUsecase := mocks.NewMockIUserUsecase(t)
+	mockUserUsecase.EXPECT().
+		BatchGetUsers(mock.Anything, []string{"test1", "unknown"}, []string{"uuid2"}, false).
+		Return([]entities.User{
+			{
+				ID:        1,
+				CreatedAt: now,
+				UpdatedAt: now,
+				Username:  "test1",
+				Uuid:      "uuid1",
+				Name:      "test1",
+				Attributes: []entities.UserAttribute{
+					{
+						ID:        1,
+						CreatedAt: now,
+						UpdatedAt: now,
+						UserID:    1,
+						Key:       "key1",
+						Value:     "value1",
+					},
+				},
+			},
+			{
+				ID:         2,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+				Username:   "test2",
+				Uuid:       "uuid2",
+				Name:       "test2",
+				Attributes: []entities.UserAttribute{},
+			},
+		}, []string{"unknown"}, nil)
+
+	mockUserUsecase.EXPECT().
+		BatchGetUsers(mock.Anything, []string{"test_failed"}, []string(nil), false).
+		Return(nil, nil, fmt.Errorf("fake error"))
+
+	mockLoggingWorker := mocks.NewMockILoggingWorker(t)
+	mockLoggingWorker.EXPECT().
+		Inject(entities.Message{
+			TenantID: entities.DefaultTenantID,
+			Key:      "users_batch_get",
+			Value:    "found: 2, missing: 1",
+		}).
+		Return()
+
+	c := &UserController{
+		userUsecase:              mockUserUsecase,
+		loggingWorker:            mockLoggingWorker,
+		userTransformer:          transformers.NewPbUserTransformer(),
+		userAttributeTransformer: transformers.NewPbUserAttributesTransformer(),
+	}
+
+	tests := []struct {
+		name    string
+		req     *pb.BatchGetUsersRequest
+		want    *pb.BatchGetUsersResponse
+		wantErr bool
+	}{
+		{
+			name: "success",
+			req: &pb.BatchGetUsersRequest{
+				Usernames: []string{"test1", "unknown"},
+				Uuids:     []string{"uuid2"},
+			},
+			want: &pb.BatchGetUsersResponse{
+				Users: []*pb.UserWithAttributes{
+					{
+						User: &pb.User{
+							Id:        1,
+							CreatedAt: utils.ToTimepb(now),
+							UpdatedAt: utils.ToTimepb(now),
+							Uuid:      "uuid1",
+							Username:  "test1",
+							Name:      "test1",
+						},
+						Attributes: []*pb.UserAttribute{
+							{
+								Id:        1,
+								CreatedAt: utils.ToTimepb(now),
+								UpdatedAt: utils.ToTimepb(now),
+								UserId:    1,
+								Key:       "key1",
+								Value:     "value1",
+							},
+						},
+					},
+					{
+						User: &pb.User{
+							Id:        2,
+							CreatedAt: utils.ToTimepb(now),
+							UpdatedAt: utils.ToTimepb(now),
+							Uuid:      "uuid2",
+							Username:  "test2",
+							Name:      "test2",
+						},
+						Attributes: []*pb.UserAttribute{},
+					},
+				},
+				MissingKeys: []string{"unknown"},
+			},
+		},
+		{
+			name: "invalid request",
+			req: &pb.BatchGetUsersRequest{
+				Usernames: []string{""},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to batch get users",
+			req: &pb.BatchGetUsersRequest{
+				Usernames: []string{"test_failed"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := c.BatchGetUsers(context.TODO(), tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserController.BatchGetUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UserController.BatchGetUsers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestUserController_ShowDeletedForbidden checks that only the administrators read the soft deleted users.
+func TestUserController_ShowDeletedForbidden(t *testing.T) {
+	t.Parallel()
+
+	c := NewUserController(mocks.NewMockIUserUsecase(t), mocks.NewMockILoggingWorker(t))
+	ctx := utils.InjectActorToContext(context.TODO(), "alice")
+
+	if _, err := c.GetUserByUsername(ctx, &pb.GetUserByUsernameRequest{
+		Username:    "deleted",
+		ShowDeleted: true,
+	}); !errors.Is(err, entities.ErrForbidden) {
+		t.Errorf("UserController.GetUserByUsername() error = %v, want %v", err, entities.ErrForbidden)
+	}
+	if _, err := c.BatchGetUsers(ctx, &pb.BatchGetUsersRequest{
+		Usernames:   []string{"deleted"},
+		ShowDeleted: true,
+	}); !errors.Is(err, entities.ErrForbidden) {
+		t.Errorf("UserController.BatchGetUsers() error = %v, want %v", err, entities.ErrForbidden)
+	}
+}
+
 func TestUserController_GetAttributesByUsername(t *testing.T) {
 	t.Parallel()
 	now := time.Now().UTC()
@@ -389,15 +588,17 @@ func TestUserController_GetAttributesByUsername(t *testing.T) {
 	mockLoggingWorker := mocks.NewMockILoggingWorker(t)
 	mockLoggingWorker.EXPECT().
 		Inject(entities.Message{
-			Key:   "user_attributes_get",
-			Value: "username: test1",
+			TenantID: entities.DefaultTenantID,
+			Key:      "user_attributes_get",
+			Value:    "username: test1",
 		}).
 		Return()
 
 	mockLoggingWorker.EXPECT().
 		Inject(entities.Message{
-			Key:   "user_attributes_get",
-			Value: "username: no_atts",
+			TenantID: entities.DefaultTenantID,
+			Key:      "user_attributes_get",
+			Value:    "username: no_atts",
 		}).
 		Return()
 
@@ -489,3 +690,90 @@ func TestUserController_GetAttributesByUsername(t *testing.T) {
 		})
 	}
 }
+
+func TestUserController_GetUserStats(t *testing.T) {
+	t.Parallel()
+	from := time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 2)
+
+	mockUserUsecase := mocks.NewMockIUserUsecase(t)
+	mockUserUsecase.EXPECT().
+		GetUserStats(mock.Anything, entities.UserStatsQuery{
+			From:         from,
+			To:           to,
+			Bucket:       entities.TimeBucketDay,
+			AttributeKey: "plan",
+		}).
+		Return(&entities.UserStats{
+			Signups: []entities.SignupCount{
+				{BucketStart: from, Count: 2},
+				{BucketStart: from.AddDate(0, 0, 1), Count: 0},
+			},
+			AttributeValues: []entities.AttributeValueCount{
+				{Value: "free", Count: 2},
+			},
+		}, nil)
+
+	mockUserUsecase.EXPECT().
+		GetUserStats(mock.Anything, entities.UserStatsQuery{Bucket: entities.TimeBucketWeek}).
+		Return(nil, fmt.Errorf("fake error"))
+
+	c := &UserController{
+		userUsecase:               mockUserUsecase,
+		signupCountTransformer:    transformers.NewPbSignupCountTransformer(),
+		attributeCountTransformer: transformers.NewPbAttributeValueCountTransformer(),
+	}
+
+	tests := []struct {
+		name    string
+		req     *pb.GetUserStatsRequest
+		want    *pb.GetUserStatsResponse
+		wantErr bool
+	}{
+		{
+			name: "success",
+			req: &pb.GetUserStatsRequest{
+				From:         utils.ToTimepb(from),
+				To:           utils.ToTimepb(to),
+				Bucket:       pb.TimeBucket_TIME_BUCKET_DAY,
+				AttributeKey: "plan",
+			},
+			want: &pb.GetUserStatsResponse{
+				Signups: []*pb.SignupCount{
+					{BucketStart: utils.ToTimepb(from), Count: 2},
+					{BucketStart: utils.ToTimepb(from.AddDate(0, 0, 1)), Count: 0},
+				},
+				AttributeValues: []*pb.AttributeValueCount{
+					{Value: "free", Count: 2},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid request",
+			req: &pb.GetUserStatsRequest{
+				Bucket: pb.TimeBucket(42),
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to get user stats",
+			req: &pb.GetUserStatsRequest{
+				Bucket: pb.TimeBucket_TIME_BUCKET_WEEK,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetUserStats(context.TODO(), tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserController.GetUserStats() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UserController.GetUserStats() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package entities
+
+import "time"
+
+type AggregateFunc string
+
+const (
+	AggregateCount AggregateFunc = "count"
+	AggregateSum   AggregateFunc = "sum"
+	AggregateMin   AggregateFunc = "min"
+	AggregateMax   AggregateFunc = "max"
+)
+
+// TimeBucket truncates the times to the start of their day, week (starting on Monday) or month, in UTC.
+type TimeBucket string
+
+const (
+	TimeBucketDay   TimeBucket = "day"
+	TimeBucketWeek  TimeBucket = "week"
+	TimeBucketMonth TimeBucket = "month"
+)
+
+// Truncate returns the start of the bucket of t, in UTC.
+func (b TimeBucket) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch b {
+	case TimeBucketWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case TimeBucketMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+// Add returns t moved by n buckets.
+func (b TimeBucket) Add(t time.Time, n int) time.Time {
+	switch b {
+	case TimeBucketWeek:
+		return t.AddDate(0, 0, 7*n)
+	case TimeBucketMonth:
+		return t.AddDate(0, n, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
+
+func (b TimeBucket) Valid() bool {
+	return b == TimeBucketDay || b == TimeBucketWeek || b == TimeBucketMonth
+}
+
+// Aggregate is an aggregate function over a column, the column of a count is empty to count the records.
+type Aggregate struct {
+	Func   AggregateFunc
+	Column string
+}
+
+// GroupBy groups the records by the values of a column, truncated to their time bucket when Bucket is set.
+type GroupBy struct {
+	Column string
+	Bucket TimeBucket
+}
+
+// AggregateQuery groups the records matching the criterias and aggregates each group.
+// Without GroupBys, all the records are aggregated in a single row.
+type AggregateQuery struct {
+	GroupBys   []GroupBy
+	Aggregates []Aggregate
+	Criterias  map[string]any
+	// OrderByValue sorts the rows by the value of the first aggregate descending, instead of by the groups
+	OrderByValue bool
+	Limit        int
+}
+
+// AggregateRow holds the values of the GroupBys of a group and of the Aggregates over it, in the order of the query.
+// The groups of a time bucket are the start times of the buckets in UTC, the aggregates of no value are 0.
+type AggregateRow struct {
+	Groups []any
+	Values []float64
+}
@@ -0,0 +1,38 @@
+package entities
+
+import "time"
+
+const (
+	AuditOperationCreate = "create"
+	AuditOperationUpdate = "update"
+	AuditOperationDelete = "delete"
+)
+
+// AuditChange holds the values of a column before and after a mutation, nil stands for no row.
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type AuditEvent struct {
+	ID            uint
+	CreatedAt     time.Time
+	TenantID      uint
+	EntityTable   string
+	EntityID      string
+	Operation     string
+	Changes       map[string]AuditChange
+	Actor         string
+	CorrelationID string
+}
+
+// AuditEventFilter selects audit events, the zero value of a field does not filter.
+type AuditEventFilter struct {
+	EntityTable string
+	EntityID    string
+	Actor       string
+	From        *time.Time
+	To          *time.Time
+	Offset      int
+	Limit       int
+}
@@ -9,7 +9,9 @@ var (
 	ErrInvalid         = errors.New("invalid input")
 	ErrCanceled        = errors.New("canceled")
 	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
 	ErrTooManyRequests = errors.New("too many requests")
 	ErrConflicted      = errors.New("conflicted")
 	ErrMalformed       = errors.New("malformed")
+	ErrLocked          = errors.New("locked")
 )
@@ -6,6 +6,7 @@ type Message struct {
 	ID        uint
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	TenantID  uint
 	Key       string
 	Value     string
 }
@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+type OutboxMessage struct {
+	ID        uint
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	TenantID  uint
+	Key       string
+	Value     string
+	Attempts  uint
+	LastError string
+	SentAt    *time.Time
+	// DeadAt is set once the relay gave up the message after its max attempts, it is never sent then
+	DeadAt *time.Time
+}
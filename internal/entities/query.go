@@ -0,0 +1,117 @@
+package entities
+
+// LockStrength is the lock taken on the rows read by a query, held until the end of its transaction.
+type LockStrength string
+
+const (
+	LockNone LockStrength = ""
+	// LockForUpdate locks the rows against the other locks and writes, for a read-modify-write
+	LockForUpdate LockStrength = "UPDATE"
+	// LockForShare locks the rows against the writes, but not against the other shared locks
+	LockForShare LockStrength = "SHARE"
+)
+
+// LockWait is what a locking query does with the rows locked by another transaction.
+type LockWait string
+
+const (
+	// LockWaitDefault waits for the rows, up to the lock timeout of the database, failing with ErrLocked
+	LockWaitDefault LockWait = ""
+	// LockNoWait fails with ErrLocked right away
+	LockNoWait LockWait = "NOWAIT"
+	// LockSkipLocked leaves the locked rows out of the result
+	LockSkipLocked LockWait = "SKIP LOCKED"
+)
+
+// DeletedScope is the soft deleted records a query reads.
+type DeletedScope string
+
+const (
+	// DeletedScopeExclude leaves the soft deleted records out, the default
+	DeletedScopeExclude DeletedScope = ""
+	// DeletedScopeInclude reads the soft deleted records along with the other ones
+	DeletedScopeInclude DeletedScope = "INCLUDE"
+	// DeletedScopeOnly reads the soft deleted records only
+	DeletedScopeOnly DeletedScope = "ONLY"
+)
+
+// QueryOptions are the options of the Get, GetMany, GetByCriterias, GetManyByCriterias and Count queries
+// of the repositories, Count taking the deleted scope only.
+//
+// The preloads are the associations loaded along with the records, by a query per association whatever the number
+// of records, e.g. "Attributes" for the attributes of the users.
+//
+// The locks are row locks of MySQL and PostgreSQL, they only make sense in a transaction (RunTx), and the wait is
+// ignored without a lock. SQLite and the memory backend have no row locks and ignore the options, a locking read is
+// a plain read there, never skipping a row: both serialize their transactions, SQLite failing with ErrLocked when
+// another process holds the write lock longer than its busy timeout.
+//
+// The deleted scope reads the soft deleted records, e.g. for the support of deleted accounts. The preloads of such
+// a query include the soft deleted associated records too, and its models must have a gorm.DeletedAt with
+// DeletedScopeOnly. The records stay in the tenant scope of the query whatever the deleted scope.
+type QueryOptions struct {
+	Lock     LockStrength
+	LockWait LockWait
+	Preloads []string
+	Deleted  DeletedScope
+}
+
+type QueryOption func(*QueryOptions)
+
+func NewQueryOptions(opts ...QueryOption) QueryOptions {
+	var options QueryOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// ForUpdate is SELECT ... FOR UPDATE.
+func ForUpdate() QueryOption {
+	return func(o *QueryOptions) {
+		o.Lock = LockForUpdate
+	}
+}
+
+// ForShare is SELECT ... FOR SHARE.
+func ForShare() QueryOption {
+	return func(o *QueryOptions) {
+		o.Lock = LockForShare
+	}
+}
+
+// NoWait is SELECT ... FOR UPDATE/SHARE NOWAIT, with ForUpdate or ForShare.
+func NoWait() QueryOption {
+	return func(o *QueryOptions) {
+		o.LockWait = LockNoWait
+	}
+}
+
+// SkipLocked is SELECT ... FOR UPDATE/SHARE SKIP LOCKED, with ForUpdate or ForShare.
+func SkipLocked() QueryOption {
+	return func(o *QueryOptions) {
+		o.LockWait = LockSkipLocked
+	}
+}
+
+// Preload loads the associations along with the records, by the names of their fields, e.g. "Attributes".
+func Preload(associations ...string) QueryOption {
+	return func(o *QueryOptions) {
+		o.Preloads = append(o.Preloads, associations...)
+	}
+}
+
+// WithDeleted reads the soft deleted records along with the other ones.
+func WithDeleted() QueryOption {
+	return func(o *QueryOptions) {
+		o.Deleted = DeletedScopeInclude
+	}
+}
+
+// OnlyDeleted reads the soft deleted records only.
+func OnlyDeleted() QueryOption {
+	return func(o *QueryOptions) {
+		o.Deleted = DeletedScopeOnly
+	}
+}
@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultTenantID is the tenant of the calls which do not carry one, it always exists.
+	DefaultTenantID uint = 1
+	// DefaultTenantName is the name the default tenant is created with.
+	DefaultTenantName = "default"
+)
+
+type Tenant struct {
+	ID        uint
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// DeletedAt is set for the soft deleted tenants, which are read with WithDeleted or OnlyDeleted only
+	DeletedAt *time.Time
+	Name      string
+}
+
+type tenantContextKey struct{}
+
+type allTenantsContextKey struct{}
+
+// InjectTenantIDToContext returns a copy of ctx bound to the tenant,
+// the repository calls made with this context only see the data of this tenant.
+func InjectTenantIDToContext(ctx context.Context, tenantID uint) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// GetTenantIDFromContext returns the tenant carried by ctx, or DefaultTenantID if there is none.
+func GetTenantIDFromContext(ctx context.Context) uint {
+	if ctx == nil {
+		return DefaultTenantID
+	}
+
+	tenantID, ok := ctx.Value(tenantContextKey{}).(uint)
+	if !ok || tenantID == 0 {
+		return DefaultTenantID
+	}
+
+	return tenantID
+}
+
+// WithAllTenants returns a copy of ctx whose repository calls are not scoped to a tenant,
+// for the background processes working on the data of every tenant. The records created
+// with it keep the tenant they are given.
+func WithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allTenantsContextKey{}, true)
+}
+
+func IsAllTenants(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+
+	all, _ := ctx.Value(allTenantsContextKey{}).(bool)
+	return all
+}
@@ -0,0 +1,55 @@
+package entities_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+func TestGetTenantIDFromContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want uint
+	}{
+		{
+			name: "tenant injected to the context",
+			ctx:  entities.InjectTenantIDToContext(context.Background(), 7),
+			want: 7,
+		},
+		{
+			name: "default tenant when the context has none",
+			ctx:  context.Background(),
+			want: entities.DefaultTenantID,
+		},
+		{
+			name: "default tenant when the injected tenant is zero",
+			ctx:  entities.InjectTenantIDToContext(context.Background(), 0),
+			want: entities.DefaultTenantID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := entities.GetTenantIDFromContext(tt.ctx); got != tt.want {
+				t.Errorf("GetTenantIDFromContext() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAllTenants(t *testing.T) {
+	t.Parallel()
+
+	if entities.IsAllTenants(context.Background()) {
+		t.Errorf("IsAllTenants() = true for a context without marker")
+	}
+	if !entities.IsAllTenants(entities.WithAllTenants(context.Background())) {
+		t.Errorf("IsAllTenants() = false for a context from WithAllTenants()")
+	}
+}
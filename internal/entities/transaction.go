@@ -2,6 +2,7 @@ package entities
 
 import (
 	"context"
+	"sync"
 )
 
 type Transaction interface {
@@ -9,3 +10,77 @@ type Transaction interface {
 }
 
 type DBTxHandleFunc func(ctx context.Context, dbtx Transaction) (err error)
+
+type transactionContextKey struct{}
+
+// InjectTransactionToContext returns a copy of ctx carrying the transaction,
+// so that repository calls made with this context join it automatically.
+func InjectTransactionToContext(ctx context.Context, tx Transaction) context.Context {
+	if tx == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, transactionContextKey{}, tx)
+}
+
+// GetTransactionFromContext returns the transaction carried by ctx, or nil if there is none.
+func GetTransactionFromContext(ctx context.Context) Transaction {
+	if ctx == nil {
+		return nil
+	}
+
+	tx, ok := ctx.Value(transactionContextKey{}).(Transaction)
+	if !ok {
+		return nil
+	}
+
+	return tx
+}
+
+type commitHooksContextKey struct{}
+
+type commitHooks struct {
+	mutex sync.Mutex
+	hooks []func(ctx context.Context)
+}
+
+// WithCommitHooks returns a copy of ctx collecting the functions registered by AfterCommit, and
+// the function to call with the outcome of the transaction: the hooks run once it committed, i.e. with a nil error.
+// A ctx already collecting the hooks, i.e. in a nested transaction, is left as is and the outermost one runs them.
+func WithCommitHooks(ctx context.Context) (context.Context, func(err error)) {
+	if _, ok := ctx.Value(commitHooksContextKey{}).(*commitHooks); ok {
+		return ctx, func(error) {}
+	}
+
+	hooks := &commitHooks{}
+	return context.WithValue(ctx, commitHooksContextKey{}, hooks), func(err error) {
+		if err != nil {
+			return
+		}
+
+		hooks.mutex.Lock()
+		fns := hooks.hooks
+		hooks.hooks = nil
+		hooks.mutex.Unlock()
+		for _, fn := range fns {
+			fn(ctx)
+		}
+	}
+}
+
+// AfterCommit runs fn once the transaction in play, tx or the one carried by ctx, is committed,
+// and drops it on a rollback. Without a transaction, or when its commit cannot be awaited, fn runs right away.
+func AfterCommit(ctx context.Context, tx Transaction, fn func(ctx context.Context)) {
+	if tx == nil {
+		tx = GetTransactionFromContext(ctx)
+	}
+	hooks, ok := ctx.Value(commitHooksContextKey{}).(*commitHooks)
+	if tx == nil || !ok {
+		fn(ctx)
+		return
+	}
+
+	hooks.mutex.Lock()
+	defer hooks.mutex.Unlock()
+	hooks.hooks = append(hooks.hooks, fn)
+}
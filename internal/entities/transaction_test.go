@@ -0,0 +1,93 @@
+package entities_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+type fakeTransaction struct{}
+
+func (fakeTransaction) GetTransaction() any {
+	return nil
+}
+
+func TestAfterCommit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		run         func(ctx context.Context, hook func(ctx context.Context)) error
+		wantBefore  bool
+		wantHookRun bool
+	}{
+		{
+			name: "no transaction",
+			run: func(ctx context.Context, hook func(ctx context.Context)) error {
+				entities.AfterCommit(ctx, nil, hook)
+				return nil
+			},
+			wantBefore:  true,
+			wantHookRun: true,
+		},
+		{
+			name: "transaction without commit hooks",
+			run: func(_ context.Context, hook func(ctx context.Context)) error {
+				entities.AfterCommit(context.Background(), fakeTransaction{}, hook)
+				return nil
+			},
+			wantBefore:  true,
+			wantHookRun: true,
+		},
+		{
+			name: "committed transaction",
+			run: func(ctx context.Context, hook func(ctx context.Context)) error {
+				ctx = entities.InjectTransactionToContext(ctx, fakeTransaction{})
+				entities.AfterCommit(ctx, nil, hook)
+				return nil
+			},
+			wantBefore:  false,
+			wantHookRun: true,
+		},
+		{
+			name: "rolled back transaction",
+			run: func(ctx context.Context, hook func(ctx context.Context)) error {
+				entities.AfterCommit(ctx, fakeTransaction{}, hook)
+				return errors.New("fake error")
+			},
+			wantBefore:  false,
+			wantHookRun: false,
+		},
+		{
+			name: "nested transaction",
+			run: func(ctx context.Context, hook func(ctx context.Context)) error {
+				nested, runNested := entities.WithCommitHooks(ctx)
+				entities.AfterCommit(nested, fakeTransaction{}, hook)
+				runNested(nil)
+				return nil
+			},
+			wantBefore:  false,
+			wantHookRun: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, runCommitHooks := entities.WithCommitHooks(context.Background())
+			hookRun := false
+			err := tt.run(ctx, func(context.Context) { hookRun = true })
+			if hookRun != tt.wantBefore {
+				t.Errorf("hook run before the commit = %v, want %v", hookRun, tt.wantBefore)
+			}
+
+			runCommitHooks(err)
+			if hookRun != tt.wantHookRun {
+				t.Errorf("hook run = %v, want %v", hookRun, tt.wantHookRun)
+			}
+		})
+	}
+}
@@ -8,9 +8,40 @@ type User struct {
 	ID        uint
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// DeletedAt is set for the soft deleted users, which are read with WithDeleted or OnlyDeleted only
+	DeletedAt *time.Time
+	TenantID  uint
 	Username  string
 	Password  string
 	Uuid      string
 	Name      string
 	Email     *string
+	// Attributes are loaded by the Preload of "Attributes" only
+	Attributes []UserAttribute
+}
+
+// UserStatsQuery selects the users created in [From, To), counted per time bucket,
+// and the values of the attributes of key AttributeKey when it is set.
+type UserStatsQuery struct {
+	From         time.Time
+	To           time.Time
+	Bucket       TimeBucket
+	AttributeKey string
+}
+
+type SignupCount struct {
+	BucketStart time.Time
+	Count       uint64
+}
+
+type AttributeValueCount struct {
+	Value string
+	Count uint64
+}
+
+type UserStats struct {
+	// Signups has a count per time bucket of the query, in time order, the buckets without signups included
+	Signups []SignupCount
+	// AttributeValues has the most frequent values first
+	AttributeValues []AttributeValueCount
 }
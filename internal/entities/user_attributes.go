@@ -11,6 +11,7 @@ type UserAttribute struct {
 	ID        uint
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	TenantID  uint
 	UserID    uint
 	Key       string
 	Value     string
@@ -34,6 +34,16 @@ func (c *FakeClient) Stop(_ context.Context) error {
 	return nil
 }
 
+// Check simulates a round trip to the remote service, failing when it does not answer before the context is done.
+func (c *FakeClient) Check(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Millisecond * time.Duration(c.LatencyMs)):
+		return nil
+	}
+}
+
 func (c *FakeClient) Send(ctx context.Context, msg *entities.Message) error {
 	latencyCtx, cancel := context.WithTimeout(
 		context.Background(),
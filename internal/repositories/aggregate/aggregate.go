@@ -0,0 +1,183 @@
+// Package aggregate builds the GROUP BY queries of entities.AggregateQuery for the GORM generic repositories
+// and scans their rows, the SQL dialects only differ by their time bucketing expressions.
+package aggregate
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"gorm.io/gorm"
+)
+
+const DefaultLimit = 100
+
+// BucketFunc returns the expression truncating the quoted column to the start of its time bucket, in UTC.
+type BucketFunc func(column string, bucket entities.TimeBucket) string
+
+// Build adds to db the selects, groups, order and limit of the query, the grouped and aggregated columns must be
+// in columns: they are part of the SQL, unlike the criterias values.
+func Build(db *gorm.DB, columns []string, query entities.AggregateQuery, bucketOf BucketFunc) (*gorm.DB, error) {
+	if len(query.Aggregates) == 0 {
+		return nil, fmt.Errorf("%w - no aggregate", entities.ErrInvalid)
+	}
+
+	column := func(name string) (string, error) {
+		if !slices.Contains(columns, name) {
+			return "", fmt.Errorf("%w - column %q cannot be aggregated", entities.ErrInvalid, name)
+		}
+		return db.Statement.Quote(name), nil
+	}
+
+	selects := make([]string, 0, len(query.GroupBys)+len(query.Aggregates))
+	groups := make([]string, 0, len(query.GroupBys))
+	for i, groupBy := range query.GroupBys {
+		expr, err := column(groupBy.Column)
+		if err != nil {
+			return nil, err
+		}
+		if groupBy.Bucket != "" {
+			if !groupBy.Bucket.Valid() {
+				return nil, fmt.Errorf("%w - unknown time bucket %q", entities.ErrInvalid, groupBy.Bucket)
+			}
+			expr = bucketOf(expr, groupBy.Bucket)
+		}
+		alias := fmt.Sprintf("g%d", i)
+		selects = append(selects, expr+" AS "+alias)
+		groups = append(groups, alias)
+	}
+
+	for i, aggregate := range query.Aggregates {
+		expr := "*"
+		if aggregate.Column != "" || aggregate.Func != entities.AggregateCount {
+			var err error
+			if expr, err = column(aggregate.Column); err != nil {
+				return nil, err
+			}
+		}
+		switch aggregate.Func {
+		case entities.AggregateCount, entities.AggregateSum, entities.AggregateMin, entities.AggregateMax:
+			selects = append(selects, fmt.Sprintf("%s(%s) AS a%d", strings.ToUpper(string(aggregate.Func)), expr, i))
+		default:
+			return nil, fmt.Errorf("%w - unknown aggregate %q", entities.ErrInvalid, aggregate.Func)
+		}
+	}
+
+	for k, v := range query.Criterias {
+		if v == nil {
+			db = db.Where(k)
+		} else {
+			db = db.Where(k, v)
+		}
+	}
+
+	orders := groups
+	if query.OrderByValue {
+		orders = append([]string{"a0 DESC"}, groups...)
+	}
+	if len(groups) > 0 {
+		db = db.Group(strings.Join(groups, ", "))
+	}
+	if len(orders) > 0 {
+		db = db.Order(strings.Join(orders, ", "))
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	return db.Select(selects).Limit(limit), nil
+}
+
+// Scan reads the rows of a query built by Build.
+func Scan(rows *sql.Rows, query entities.AggregateQuery) ([]entities.AggregateRow, error) {
+	defer rows.Close()
+
+	out := make([]entities.AggregateRow, 0)
+	for rows.Next() {
+		values := make([]any, len(query.GroupBys)+len(query.Aggregates))
+		dests := make([]any, len(values))
+		for i := range values {
+			dests[i] = &values[i]
+		}
+		if err := rows.Scan(dests...); err != nil {
+			return nil, err
+		}
+
+		row := entities.AggregateRow{
+			Groups: make([]any, len(query.GroupBys)),
+			Values: make([]float64, len(query.Aggregates)),
+		}
+		for i, groupBy := range query.GroupBys {
+			group, err := groupValue(values[i], groupBy.Bucket)
+			if err != nil {
+				return nil, err
+			}
+			row.Groups[i] = group
+		}
+		for i := range query.Aggregates {
+			value, err := aggregateValue(values[len(query.GroupBys)+i])
+			if err != nil {
+				return nil, err
+			}
+			row.Values[i] = value
+		}
+		out = append(out, row)
+	}
+
+	return out, rows.Err()
+}
+
+func groupValue(value any, bucket entities.TimeBucket) (any, error) {
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	if bucket == "" || value == nil {
+		return value, nil
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		// the bucket was computed on the UTC time, whatever the location of the driver
+		return time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC), nil
+	case string:
+		if len(v) >= len(time.DateOnly) {
+			if t, err := time.Parse(time.DateOnly, v[:len(time.DateOnly)]); err == nil {
+				return t, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w - unexpected time bucket %v", entities.ErrDatabase, value)
+}
+
+func aggregateValue(value any) (float64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case []byte:
+		return parseFloat(string(v))
+	case string:
+		return parseFloat(v)
+	default:
+		return 0, fmt.Errorf("%w - unexpected aggregate %T", entities.ErrDatabase, value)
+	}
+}
+
+func parseFloat(s string) (float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w - unexpected aggregate %q", entities.ErrDatabase, s)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,429 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/tenancy"
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	pluginName    = "audit"
+	beforeRowsKey = "audit:before_rows"
+
+	// TableName is the table of the audit events, mutations of it are not audited
+	TableName = "audit_events"
+
+	// tagName is the struct tag marking the columns whose values are not written to the audit trail,
+	// e.g. `audit:"redact"`
+	tagName  = "audit"
+	redact   = "redact"
+	redacted = "[REDACTED]"
+)
+
+// Event is an audit record, one per row created, updated or deleted.
+type Event struct {
+	ID            uint      `gorm:"primarykey"`
+	CreatedAt     time.Time `gorm:"index"`
+	TenantID      uint      `gorm:"not null;default:1;index"`
+	EntityTable   string    `gorm:"size:64;index:idx_audit_events_entity"`
+	EntityID      string    `gorm:"size:191;index:idx_audit_events_entity"`
+	Operation     string    `gorm:"size:16"`
+	Changes       string    `gorm:"type:text"`
+	Actor         string    `gorm:"size:191;index"`
+	CorrelationID string    `gorm:"size:64"`
+}
+
+// eventColumns is the number of columns of Event.
+const eventColumns = 9
+
+func (Event) TableName() string {
+	return TableName
+}
+
+type withoutAuditContextKey struct{}
+
+// WithoutAudit returns a copy of ctx whose statements are not audited, for the copies of rows
+// which are not mutations of the data, e.g. between two databases.
+func WithoutAudit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutAuditContextKey{}, true)
+}
+
+func isWithoutAudit(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+
+	without, _ := ctx.Value(withoutAuditContextKey{}).(bool)
+	return without
+}
+
+// Plugin is a GORM plugin writing an audit event for every row created, updated or deleted through a model,
+// in the transaction of the statement. The rows updated or deleted are read before the statement
+// and again after it, to record the values of the changed columns.
+// Raw statements and statements without a model are not audited.
+type Plugin struct {
+	maxPlaceholders int
+}
+
+// NewPlugin creates the plugin for a database allowing maxPlaceholders placeholders per statement,
+// the events of a statement are written by as many statements as needed.
+func NewPlugin(maxPlaceholders int) *Plugin {
+	return &Plugin{maxPlaceholders: maxPlaceholders}
+}
+
+func (p *Plugin) Name() string {
+	return pluginName
+}
+
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	if err := db.AutoMigrate(&Event{}); err != nil {
+		return fmt.Errorf("failed to migrate %s: %w", TableName, err)
+	}
+
+	callback := db.Callback()
+	registers := []func() error{
+		func() error {
+			return callback.Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").
+				Register("audit:after_create", p.afterCreate)
+		},
+		func() error {
+			return callback.Update().After("gorm:begin_transaction").Before("gorm:update").
+				Register("audit:before_update", p.before)
+		},
+		func() error {
+			return callback.Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").
+				Register("audit:after_update", p.after(entities.AuditOperationUpdate))
+		},
+		func() error {
+			return callback.Delete().After("gorm:begin_transaction").Before("gorm:delete").
+				Register("audit:before_delete", p.before)
+		},
+		func() error {
+			return callback.Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").
+				Register("audit:after_delete", p.after(entities.AuditOperationDelete))
+		},
+	}
+	for _, register := range registers {
+		if err := register(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Plugin) skip(db *gorm.DB) bool {
+	return db.Error != nil ||
+		db.DryRun ||
+		isWithoutAudit(db.Statement.Context) ||
+		db.Statement.Schema == nil ||
+		len(db.Statement.Schema.PrimaryFields) == 0 ||
+		db.Statement.Table == TableName
+}
+
+// before loads the rows matched by an update or a delete.
+func (p *Plugin) before(db *gorm.DB) {
+	if p.skip(db) {
+		return
+	}
+
+	stmt := db.Statement
+	query := db.Session(&gorm.Session{NewDB: true}).Table(stmt.Table)
+	if stmt.Unscoped {
+		query = query.Unscoped()
+	}
+
+	conditions := 0
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			query = query.Clauses(where)
+			conditions++
+		}
+	}
+	// Updates of a model add the condition on its primary key only when the statement is built
+	if models := modelValues(stmt.Schema, stmt.ReflectValue); len(models) == 1 {
+		for _, field := range stmt.Schema.PrimaryFields {
+			if value, zero := field.ValueOf(stmt.Context, models[0]); !zero {
+				query = query.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value})
+				conditions++
+			}
+		}
+	}
+	if conditions == 0 {
+		// GORM rejects the statement without conditions
+		return
+	}
+
+	rows := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+	if err := query.Find(rows.Interface()).Error; err != nil {
+		_ = db.AddError(fmt.Errorf("failed to load rows to audit: %w", err))
+		return
+	}
+
+	db.InstanceSet(beforeRowsKey, rows.Elem())
+}
+
+// after reloads the rows loaded by before and records the columns which changed.
+func (p *Plugin) after(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if p.skip(db) {
+			return
+		}
+
+		value, ok := db.InstanceGet(beforeRowsKey)
+		if !ok {
+			return
+		}
+		befores, ok := value.(reflect.Value)
+		if !ok || befores.Len() == 0 {
+			return
+		}
+
+		stmt := db.Statement
+		afters := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+		if err := db.Session(&gorm.Session{NewDB: true}).
+			Table(stmt.Table).
+			Unscoped().
+			Clauses(clause.Where{Exprs: []clause.Expression{primaryKeyCondition(db, befores)}}).
+			Find(afters.Interface()).Error; err != nil {
+			_ = db.AddError(fmt.Errorf("failed to load audited rows: %w", err))
+			return
+		}
+
+		afterByID := make(map[string]map[string]any, afters.Elem().Len())
+		for i := range afters.Elem().Len() {
+			row := afters.Elem().Index(i)
+			afterByID[primaryKey(db, row)] = snapshot(db, row)
+		}
+
+		events := make([]Event, 0, befores.Len())
+		for i := range befores.Len() {
+			row := befores.Index(i)
+			id := primaryKey(db, row)
+			changes := diff(stmt.Schema, snapshot(db, row), afterByID[id])
+			if len(changes) == 0 {
+				continue
+			}
+			events = append(events, newEvent(db, row, id, operation, changes))
+		}
+
+		p.write(db, events)
+	}
+}
+
+func (p *Plugin) afterCreate(db *gorm.DB) {
+	if p.skip(db) {
+		return
+	}
+
+	rows := modelValues(db.Statement.Schema, db.Statement.ReflectValue)
+	events := make([]Event, 0, len(rows))
+	for _, row := range rows {
+		changes := diff(db.Statement.Schema, nil, snapshot(db, row))
+		events = append(events, newEvent(db, row, primaryKey(db, row), entities.AuditOperationCreate, changes))
+	}
+
+	p.write(db, events)
+}
+
+func (p *Plugin) write(db *gorm.DB, events []Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	batchSize := max(p.maxPlaceholders/eventColumns, 1)
+	if err := db.Session(&gorm.Session{NewDB: true}).CreateInBatches(&events, batchSize).Error; err != nil {
+		_ = db.AddError(fmt.Errorf("failed to write audit events: %w", err))
+	}
+}
+
+// newEvent creates the event of the row, in the tenant of the row if it belongs to one.
+func newEvent(db *gorm.DB, row reflect.Value, id, operation string, changes map[string]entities.AuditChange) Event {
+	ctx := db.Statement.Context
+	actor, _ := utils.LookupActor(ctx)
+	correlationID, _ := utils.LookupCorrelationID(ctx)
+
+	// the changes are made of JSON values only
+	data, _ := json.Marshal(changes)
+
+	var tenantID uint
+	if field := db.Statement.Schema.LookUpField(tenancy.Column); field != nil {
+		if value, ok := field.ReflectValueOf(ctx, row).Interface().(uint); ok {
+			tenantID = value
+		}
+	}
+
+	return Event{
+		TenantID:      tenantID,
+		EntityTable:   db.Statement.Table,
+		EntityID:      id,
+		Operation:     operation,
+		Changes:       string(data),
+		Actor:         actor,
+		CorrelationID: correlationID,
+	}
+}
+
+// modelValues returns the rows of the model held by value, a model, or a slice or an array of models.
+func modelValues(s *schema.Schema, value reflect.Value) []reflect.Value {
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.Type() == s.ModelType {
+			return []reflect.Value{value}
+		}
+	case reflect.Slice, reflect.Array:
+		out := make([]reflect.Value, 0, value.Len())
+		for i := range value.Len() {
+			if row := reflect.Indirect(value.Index(i)); row.Kind() == reflect.Struct && row.Type() == s.ModelType {
+				out = append(out, row)
+			}
+		}
+		return out
+	}
+
+	return nil
+}
+
+// primaryKey formats the primary key of the row, the values of a composite key are separated by commas.
+func primaryKey(db *gorm.DB, row reflect.Value) string {
+	values := make([]string, 0, len(db.Statement.Schema.PrimaryFields))
+	for _, field := range db.Statement.Schema.PrimaryFields {
+		value, _ := field.ValueOf(db.Statement.Context, row)
+		values = append(values, fmt.Sprint(normalize(value)))
+	}
+
+	return strings.Join(values, ",")
+}
+
+func primaryKeyCondition(db *gorm.DB, rows reflect.Value) clause.Expression {
+	fields := db.Statement.Schema.PrimaryFields
+	if len(fields) == 1 {
+		values := make([]any, 0, rows.Len())
+		for i := range rows.Len() {
+			value, _ := fields[0].ValueOf(db.Statement.Context, rows.Index(i))
+			values = append(values, value)
+		}
+		return clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: fields[0].DBName}, Values: values}
+	}
+
+	exprs := make([]clause.Expression, 0, rows.Len())
+	for i := range rows.Len() {
+		eqs := make([]clause.Expression, 0, len(fields))
+		for _, field := range fields {
+			value, _ := field.ValueOf(db.Statement.Context, rows.Index(i))
+			eqs = append(eqs, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value})
+		}
+		exprs = append(exprs, clause.And(eqs...))
+	}
+
+	return clause.Or(exprs...)
+}
+
+// snapshot returns the column values of the row.
+func snapshot(db *gorm.DB, row reflect.Value) map[string]any {
+	values := make(map[string]any, len(db.Statement.Schema.DBNames))
+	for _, field := range db.Statement.Schema.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		var value any
+		if field.Serializer != nil {
+			// the serialized value, e.g. a ciphertext, changes on every write
+			value = field.ReflectValueOf(db.Statement.Context, row).Interface()
+		} else {
+			value, _ = field.ValueOf(db.Statement.Context, row)
+		}
+		values[field.DBName] = normalize(value)
+	}
+
+	return values
+}
+
+// normalize turns a column value into the value stored in the database, times are in UTC.
+func normalize(value any) any {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return nil
+	}
+
+	if valuer, ok := value.(driver.Valuer); ok {
+		stored, err := valuer.Value()
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		value = stored
+		v = reflect.ValueOf(value)
+		if !v.IsValid() {
+			return nil
+		}
+	}
+
+	if v.Kind() == reflect.Pointer {
+		return normalize(v.Elem().Interface())
+	}
+	if t, ok := value.(time.Time); ok {
+		return t.UTC()
+	}
+
+	return value
+}
+
+// diff returns the columns whose values differ between the snapshots, a nil snapshot stands for no row.
+func diff(s *schema.Schema, before, after map[string]any) map[string]entities.AuditChange {
+	changes := make(map[string]entities.AuditChange)
+	for _, field := range s.Fields {
+		if field.DBName == "" {
+			continue
+		}
+
+		var change entities.AuditChange
+		if before != nil {
+			change.Before = before[field.DBName]
+		}
+		if after != nil {
+			change.After = after[field.DBName]
+		}
+		if before != nil && after != nil && equal(change.Before, change.After) {
+			continue
+		}
+
+		if field.Tag.Get(tagName) == redact {
+			change.Before = redactValue(change.Before)
+			change.After = redactValue(change.After)
+		}
+		changes[field.DBName] = change
+	}
+
+	return changes
+}
+
+func equal(a, b any) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	if aErr != nil || bErr != nil {
+		return reflect.DeepEqual(a, b)
+	}
+
+	return bytes.Equal(aData, bData)
+}
+
+func redactValue(value any) any {
+	if value == nil {
+		return nil
+	}
+
+	return redacted
+}
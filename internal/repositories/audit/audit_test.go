@@ -0,0 +1,242 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type Account struct {
+	gorm.Model
+	Name   string
+	Secret string `audit:"redact"`
+}
+
+func newTestDB(t *testing.T, maxPlaceholders int) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(
+		sqlite.Open(filepath.Join(t.TempDir(), "audit.db")),
+		&gorm.Config{Logger: gormlogger.Discard},
+	)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.Use(NewPlugin(maxPlaceholders)); err != nil {
+		t.Fatalf("failed to install audit plugin: %v", err)
+	}
+	if err := db.AutoMigrate(&Account{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	return db
+}
+
+func events(t *testing.T, db *gorm.DB) []Event {
+	t.Helper()
+
+	var out []Event
+	if err := db.Order("id").Find(&out).Error; err != nil {
+		t.Fatalf("failed to get audit events: %v", err)
+	}
+
+	return out
+}
+
+func changes(t *testing.T, event Event) map[string]entities.AuditChange {
+	t.Helper()
+
+	out := make(map[string]entities.AuditChange)
+	if err := json.Unmarshal([]byte(event.Changes), &out); err != nil {
+		t.Fatalf("failed to decode changes %s: %v", event.Changes, err)
+	}
+
+	return out
+}
+
+func TestPlugin(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t, 32766)
+	ctx := utils.InjectActorToContext(context.Background(), "alice")
+	ctx = utils.InjectCorrelationIDToContext(ctx, "correlation-1")
+	db = db.WithContext(ctx)
+
+	// create
+	if err := db.Create(&Account{Name: "name1", Secret: "secret1"}).Error; err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+	got := events(t, db)
+	if len(got) != 1 {
+		t.Fatalf("got %d events after create, want 1", len(got))
+	}
+	if got[0].EntityTable != "accounts" || got[0].EntityID != "1" || got[0].Operation != entities.AuditOperationCreate {
+		t.Errorf("unexpected create event %+v", got[0])
+	}
+	if got[0].Actor != "alice" || got[0].CorrelationID != "correlation-1" {
+		t.Errorf("unexpected actor or correlation id in %+v", got[0])
+	}
+	created := changes(t, got[0])
+	if created["name"].Before != nil || created["name"].After != "name1" {
+		t.Errorf("unexpected name change %+v", created["name"])
+	}
+	if created["secret"].After != redacted {
+		t.Errorf("secret is not redacted: %+v", created["secret"])
+	}
+
+	// update through the primary key of the model
+	if err := db.Updates(&Account{Model: gorm.Model{ID: 1}, Name: "name2"}).Error; err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	got = events(t, db)
+	if len(got) != 2 || got[1].Operation != entities.AuditOperationUpdate || got[1].EntityID != "1" {
+		t.Fatalf("unexpected events after update %+v", got)
+	}
+	updated := changes(t, got[1])
+	if updated["name"].Before != "name1" || updated["name"].After != "name2" {
+		t.Errorf("unexpected name change %+v", updated["name"])
+	}
+	if _, ok := updated["updated_at"]; !ok {
+		t.Errorf("updated_at change is missing in %+v", updated)
+	}
+	for _, column := range []string{"id", "created_at", "secret"} {
+		if _, ok := updated[column]; ok {
+			t.Errorf("unchanged column %s is recorded in %+v", column, updated)
+		}
+	}
+
+	// update through conditions, the redacted column is recorded without its values
+	if err := db.Model(&Account{}).Where("name = ?", "name2").Update("secret", "secret2").Error; err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	got = events(t, db)
+	if len(got) != 3 {
+		t.Fatalf("got %d events after update, want 3", len(got))
+	}
+	if secret := changes(t, got[2])["secret"]; secret.Before != redacted || secret.After != redacted {
+		t.Errorf("unexpected secret change %+v", secret)
+	}
+
+	// an update matching no row is not recorded
+	if err := db.Model(&Account{}).Where("name = ?", "unknown").Update("name", "name3").Error; err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if got = events(t, db); len(got) != 3 {
+		t.Fatalf("got %d events after an update without rows, want 3", len(got))
+	}
+
+	// soft delete
+	if err := db.Model(&Account{}).Delete("id = ?", 1).Error; err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	got = events(t, db)
+	if len(got) != 4 || got[3].Operation != entities.AuditOperationDelete {
+		t.Fatalf("unexpected events after soft delete %+v", got)
+	}
+	deleted := changes(t, got[3])
+	if deleted["deleted_at"].Before != nil || deleted["deleted_at"].After == nil {
+		t.Errorf("unexpected deleted_at change %+v", deleted["deleted_at"])
+	}
+
+	// permanent delete of the soft deleted row
+	if err := db.Unscoped().Model(&Account{}).Delete("id = ?", 1).Error; err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	got = events(t, db)
+	if len(got) != 5 || got[4].Operation != entities.AuditOperationDelete {
+		t.Fatalf("unexpected events after permanent delete %+v", got)
+	}
+	if name := changes(t, got[4])["name"]; name.Before != "name2" || name.After != nil {
+		t.Errorf("unexpected name change %+v", name)
+	}
+}
+
+func TestPlugin_CreateMany(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t, 32766)
+	if err := db.Create([]Account{{Name: "name1"}, {Name: "name2"}}).Error; err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+
+	got := events(t, db)
+	if len(got) != 2 || got[0].EntityID != "1" || got[1].EntityID != "2" {
+		t.Fatalf("unexpected events %+v", got)
+	}
+	if got[0].Actor != "" || got[0].CorrelationID != "" {
+		t.Errorf("unexpected actor or correlation id in %+v", got[0])
+	}
+}
+
+func TestPlugin_CreateManyInBatches(t *testing.T) {
+	t.Parallel()
+
+	// two events per statement
+	db := newTestDB(t, 2*eventColumns)
+	statements := 0
+	if err := db.Callback().Create().After("gorm:create").Register("test:count", func(db *gorm.DB) {
+		if db.Statement.Table == TableName {
+			statements++
+		}
+	}); err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+
+	accounts := []Account{{Name: "name1"}, {Name: "name2"}, {Name: "name3"}, {Name: "name4"}, {Name: "name5"}}
+	if err := db.Create(accounts).Error; err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+
+	got := events(t, db)
+	if len(got) != 5 || got[0].EntityID != "1" || got[4].EntityID != "5" {
+		t.Fatalf("unexpected events %+v", got)
+	}
+	if statements != 3 {
+		t.Errorf("events written by %d statements, want 3", statements)
+	}
+}
+
+func TestPlugin_Rollback(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t, 32766)
+	errRollback := errors.New("rollback")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&Account{Name: "name1"}).Error; err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("Transaction() error = %v, want %v", err, errRollback)
+	}
+
+	if got := events(t, db); len(got) != 0 {
+		t.Errorf("got %d events after a rollback, want 0", len(got))
+	}
+}
+
+func TestPlugin_WithoutAudit(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t, 32766)
+	ctx := WithoutAudit(context.Background())
+	if err := db.WithContext(ctx).Create(&Account{Name: "name1"}).Error; err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+	if err := db.WithContext(ctx).Model(&Account{}).Where("id = ?", 1).Update("name", "name2").Error; err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	if got := events(t, db); len(got) != 0 {
+		t.Errorf("got %d events without audit, want 0", len(got))
+	}
+}
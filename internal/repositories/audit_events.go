@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/audit"
+)
+
+type auditEventTransformer struct{}
+
+func (t *auditEventTransformer) ToEntity(data *audit.Event) (*entities.AuditEvent, error) {
+	var changes map[string]entities.AuditChange
+	if data.Changes != "" {
+		if err := json.Unmarshal([]byte(data.Changes), &changes); err != nil {
+			return nil, fmt.Errorf("%w - failed to decode audit changes, err: %w", entities.ErrDatabase, err)
+		}
+	}
+	return &entities.AuditEvent{
+		ID:            data.ID,
+		CreatedAt:     data.CreatedAt,
+		TenantID:      data.TenantID,
+		EntityTable:   data.EntityTable,
+		EntityID:      data.EntityID,
+		Operation:     data.Operation,
+		Changes:       changes,
+		Actor:         data.Actor,
+		CorrelationID: data.CorrelationID,
+	}, nil
+}
+
+func (t *auditEventTransformer) FromEntity(entity *entities.AuditEvent) (*audit.Event, error) {
+	var changes []byte
+	if entity.Changes != nil {
+		var err error
+		if changes, err = json.Marshal(entity.Changes); err != nil {
+			return nil, fmt.Errorf("%w - failed to encode audit changes, err: %w", entities.ErrInvalid, err)
+		}
+	}
+	return &audit.Event{
+		ID:            entity.ID,
+		CreatedAt:     entity.CreatedAt,
+		TenantID:      entity.TenantID,
+		EntityTable:   entity.EntityTable,
+		EntityID:      entity.EntityID,
+		Operation:     entity.Operation,
+		Changes:       string(changes),
+		Actor:         entity.Actor,
+		CorrelationID: entity.CorrelationID,
+	}, nil
+}
+
+// AuditEventRepository reads the audit events written by the audit plugin of the database.
+type AuditEventRepository struct {
+	GenericRepository[audit.Event, entities.AuditEvent]
+}
+
+func NewAuditEventRepository(repository Database) *AuditEventRepository {
+	transformer := entities.NewExtendedDataTransformer(&auditEventTransformer{})
+	return &AuditEventRepository{
+		GenericRepository: NewGenericRepository(repository, transformer),
+	}
+}
+
+func (s *AuditEventRepository) Start(ctx context.Context) error {
+	log.Info("starting audit event store")
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	err := s.AutoMigrate(timeoutCtx)
+	if err != nil {
+		return err
+	}
+
+	return s.Ping(timeoutCtx)
+}
+
+func (s *AuditEventRepository) Stop(_ context.Context) error {
+	log.Info("stopping audit event store")
+	return nil
+}
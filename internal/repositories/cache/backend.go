@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// IBackend stores the encoded cache entries, it is the extension point for a shared cache.
+type IBackend interface {
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// LRUBackend is an in-process cache backend, evicting the least recently used entry when it is full.
+type LRUBackend struct {
+	mutex    sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+func NewLRUBackend(capacity int) *LRUBackend {
+	return &LRUBackend{
+		capacity: max(capacity, 1),
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (b *LRUBackend) Get(_ context.Context, key string) ([]byte, bool, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	element, ok := b.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !b.now().Before(entry.expiresAt) {
+		b.remove(element)
+		return nil, false, nil
+	}
+
+	b.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+func (b *LRUBackend) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	expiresAt := b.now().Add(ttl)
+	if element, ok := b.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		b.order.MoveToFront(element)
+		return nil
+	}
+
+	b.entries[key] = b.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for b.order.Len() > b.capacity {
+		b.remove(b.order.Back())
+	}
+
+	return nil
+}
+
+func (b *LRUBackend) Delete(_ context.Context, keys ...string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, key := range keys {
+		if element, ok := b.entries[key]; ok {
+			b.remove(element)
+		}
+	}
+
+	return nil
+}
+
+func (b *LRUBackend) Len() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.order.Len()
+}
+
+func (b *LRUBackend) remove(element *list.Element) {
+	b.order.Remove(element)
+	delete(b.entries, element.Value.(*lruEntry).key)
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUBackend(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	b := NewLRUBackend(2)
+	b.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_ = b.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = b.Set(ctx, "b", []byte("2"), time.Second)
+	if _, found, _ := b.Get(ctx, "a"); !found {
+		t.Errorf("b.Get(a) found = false, want true")
+	}
+
+	// b is the least recently used entry and gets evicted
+	_ = b.Set(ctx, "c", []byte("3"), time.Minute)
+	if _, found, _ := b.Get(ctx, "b"); found {
+		t.Errorf("b.Get(b) found = true, want false")
+	}
+	if b.Len() != 2 {
+		t.Errorf("b.Len() = %d, want 2", b.Len())
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, found, _ := b.Get(ctx, "a"); found {
+		t.Errorf("b.Get(a) found = true after expiry, want false")
+	}
+
+	_ = b.Set(ctx, "d", []byte("4"), time.Minute)
+	_ = b.Delete(ctx, "d", "unknown")
+	if _, found, _ := b.Get(ctx, "d"); found {
+		t.Errorf("b.Get(d) found = true after delete, want false")
+	}
+}
@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/tenancy"
+	"github.com/tuantran1810/go-di-template/libs/logger"
+)
+
+var log = logger.MustNamedLogger("cache")
+
+var cacheRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "repository_cache_requests_total",
+		Help: "Number of repository cache lookups, by cache and result (hit or miss).",
+	},
+	[]string{"cache", "result"},
+)
+
+const (
+	defaultTTL         = 30 * time.Second
+	defaultNegativeTTL = 5 * time.Second
+)
+
+type Config struct {
+	// TTL is the lifetime of a cached record
+	TTL time.Duration
+	// NegativeTTL is the lifetime of a cached NotFound, kept short since a concurrent creation may not be committed yet
+	NegativeTTL time.Duration
+}
+
+// negativeEntry is the encoded value of a record known not to exist.
+var negativeEntry = []byte("null")
+
+// readThrough serves key from the backend, or loads it and fills the backend.
+// A transaction in play bypasses the cache, so that uncommitted data is never cached.
+type readThrough struct {
+	name    string
+	backend IBackend
+	config  Config
+}
+
+func newReadThrough(name string, backend IBackend, config Config) readThrough {
+	if config.TTL <= 0 {
+		config.TTL = defaultTTL
+	}
+	if config.NegativeTTL <= 0 {
+		config.NegativeTTL = defaultNegativeTTL
+	}
+
+	return readThrough{
+		name:    name,
+		backend: backend,
+		config:  config,
+	}
+}
+
+func inTransaction(ctx context.Context, tx entities.Transaction) bool {
+	return tx != nil || entities.GetTransactionFromContext(ctx) != nil
+}
+
+// cacheable returns the tenant of the cache keys of a read, ok is false when the read bypasses the cache:
+// in a transaction, or when it is not scoped to a tenant.
+func cacheable(ctx context.Context, tx entities.Transaction) (tenantID uint, ok bool) {
+	if inTransaction(ctx, tx) {
+		return 0, false
+	}
+
+	tenantID, all := tenancy.Resolve(ctx)
+	return tenantID, !all
+}
+
+// tenantOf returns the tenant of an entity, the one of ctx when the entity does not carry it.
+func tenantOf(ctx context.Context, tenantID uint) uint {
+	if tenantID != 0 {
+		return tenantID
+	}
+
+	return entities.GetTenantIDFromContext(ctx)
+}
+
+// get decodes the cached value of key into out, the found result is false on a miss.
+// A cached NotFound is returned as an ErrNotFound error.
+func (r readThrough) get(ctx context.Context, key string, out any) (bool, error) {
+	value, found, err := r.backend.Get(ctx, key)
+	if err != nil {
+		log.Warnf("failed to get %s from cache %s: %v", key, r.name, err)
+		found = false
+	}
+	if !found {
+		cacheRequests.WithLabelValues(r.name, "miss").Inc()
+		return false, nil
+	}
+
+	cacheRequests.WithLabelValues(r.name, "hit").Inc()
+	if string(value) == string(negativeEntry) {
+		return true, fmt.Errorf("%w - cached, key: %s", entities.ErrNotFound, key)
+	}
+	if err := json.Unmarshal(value, out); err != nil {
+		log.Warnf("failed to decode %s from cache %s: %v", key, r.name, err)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// set caches the loaded value of key, or a negative entry when loading failed with ErrNotFound.
+func (r readThrough) set(ctx context.Context, key string, value any, loadErr error) {
+	var (
+		encoded []byte
+		ttl     = r.config.TTL
+	)
+
+	switch {
+	case loadErr == nil:
+		var err error
+		if encoded, err = json.Marshal(value); err != nil {
+			log.Warnf("failed to encode %s for cache %s: %v", key, r.name, err)
+			return
+		}
+	case isNotFound(loadErr):
+		encoded = negativeEntry
+		ttl = r.config.NegativeTTL
+	default:
+		return
+	}
+
+	if err := r.backend.Set(ctx, key, encoded, ttl); err != nil {
+		log.Warnf("failed to set %s to cache %s: %v", key, r.name, err)
+	}
+}
+
+// invalidate drops the keys written by tx, or by the transaction carried by ctx, once it is committed:
+// a reader outside of the transaction would otherwise cache the previous state again before the commit.
+func (r readThrough) invalidate(ctx context.Context, tx entities.Transaction, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	entities.AfterCommit(ctx, tx, func(ctx context.Context) {
+		if err := r.backend.Delete(ctx, keys...); err != nil {
+			log.Warnf("failed to invalidate %v from cache %s: %v", keys, r.name, err)
+		}
+	})
+}
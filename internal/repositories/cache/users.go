@@ -0,0 +1,250 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+func isNotFound(err error) bool {
+	return errors.Is(err, entities.ErrNotFound)
+}
+
+type IUserRepository interface {
+	Create(ctx context.Context, tx entities.Transaction, user *entities.User) (*entities.User, error)
+	FindByUsername(ctx context.Context, tx entities.Transaction, username string, opts ...entities.QueryOption) (*entities.User, error)
+	FindByUsernames(ctx context.Context, tx entities.Transaction, usernames []string, opts ...entities.QueryOption) ([]entities.User, error)
+	FindByUuids(ctx context.Context, tx entities.Transaction, uuids []string, opts ...entities.QueryOption) ([]entities.User, error)
+	Get(ctx context.Context, tx entities.Transaction, id uint, opts ...entities.QueryOption) (*entities.User, error)
+	Update(ctx context.Context, tx entities.Transaction, user *entities.User) error
+	Delete(ctx context.Context, tx entities.Transaction, permanent bool, id uint) error
+	Aggregate(ctx context.Context, tx entities.Transaction, query entities.AggregateQuery) ([]entities.AggregateRow, error)
+}
+
+type IUserAttributeRepository interface {
+	CreateMany(ctx context.Context, tx entities.Transaction, userAttributes []entities.UserAttribute) ([]entities.UserAttribute, error)
+	GetByUserID(ctx context.Context, tx entities.Transaction, userID uint) ([]entities.UserAttribute, error)
+	GetManyByUserName(ctx context.Context, tx entities.Transaction, userName string) ([]entities.UserAttribute, error)
+	Get(ctx context.Context, tx entities.Transaction, id uint, opts ...entities.QueryOption) (*entities.UserAttribute, error)
+	Update(ctx context.Context, tx entities.Transaction, userAttribute *entities.UserAttribute) error
+	Delete(ctx context.Context, tx entities.Transaction, permanent bool, id uint) error
+	Aggregate(ctx context.Context, tx entities.Transaction, query entities.AggregateQuery) ([]entities.AggregateRow, error)
+}
+
+// the keys are per tenant, since a username and the attributes visible for a user id depend on it
+
+func usernameKey(tenantID uint, username string) string {
+	return fmt.Sprintf("users:tenant:%d:username:%s", tenantID, username)
+}
+
+func userIDKey(tenantID, userID uint) string {
+	return fmt.Sprintf("user_attributes:tenant:%d:user_id:%d", tenantID, userID)
+}
+
+// UserRepository caches the users by username in front of a user repository.
+type UserRepository struct {
+	readThrough
+	IUserRepository
+}
+
+func NewUserRepository(repository IUserRepository, backend IBackend, config Config) *UserRepository {
+	return &UserRepository{
+		readThrough:     newReadThrough("users", backend, config),
+		IUserRepository: repository,
+	}
+}
+
+func (s *UserRepository) Create(
+	ctx context.Context,
+	tx entities.Transaction,
+	user *entities.User,
+) (*entities.User, error) {
+	out, err := s.IUserRepository.Create(ctx, tx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// drops a cached NotFound of the new username
+	s.invalidate(ctx, tx, usernameKey(tenantOf(ctx, out.TenantID), out.Username))
+	return out, nil
+}
+
+// FindByUsername reads through the cache the users without their associations,
+// a lookup with query options, e.g. a preload, goes to the repository.
+func (s *UserRepository) FindByUsername(
+	ctx context.Context,
+	tx entities.Transaction,
+	username string,
+	opts ...entities.QueryOption,
+) (*entities.User, error) {
+	tenantID, ok := cacheable(ctx, tx)
+	if !ok || len(opts) > 0 {
+		return s.IUserRepository.FindByUsername(ctx, tx, username, opts...)
+	}
+
+	key := usernameKey(tenantID, username)
+	var cached entities.User
+	if found, err := s.get(ctx, key, &cached); found {
+		if err != nil {
+			return nil, err
+		}
+		return &cached, nil
+	}
+
+	user, err := s.IUserRepository.FindByUsername(ctx, tx, username)
+	s.set(ctx, key, user, err)
+	return user, err
+}
+
+func (s *UserRepository) Update(
+	ctx context.Context,
+	tx entities.Transaction,
+	user *entities.User,
+) error {
+	if user == nil {
+		return s.IUserRepository.Update(ctx, tx, user)
+	}
+
+	keys := []string{usernameKey(tenantOf(ctx, user.TenantID), user.Username)}
+	if current, err := s.IUserRepository.Get(ctx, tx, user.ID); err == nil {
+		keys = append(keys, usernameKey(current.TenantID, user.Username), usernameKey(current.TenantID, current.Username))
+	}
+
+	if err := s.IUserRepository.Update(ctx, tx, user); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx, tx, keys...)
+	return nil
+}
+
+func (s *UserRepository) Delete(
+	ctx context.Context,
+	tx entities.Transaction,
+	permanent bool,
+	id uint,
+) error {
+	current, err := s.IUserRepository.Get(ctx, tx, id)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+
+	if err := s.IUserRepository.Delete(ctx, tx, permanent, id); err != nil {
+		return err
+	}
+
+	if current != nil {
+		s.invalidate(ctx, tx, usernameKey(tenantOf(ctx, current.TenantID), current.Username))
+	}
+	return nil
+}
+
+// UserAttributeRepository caches the attributes by user id in front of a user attribute repository.
+type UserAttributeRepository struct {
+	readThrough
+	IUserAttributeRepository
+}
+
+func NewUserAttributeRepository(repository IUserAttributeRepository, backend IBackend, config Config) *UserAttributeRepository {
+	return &UserAttributeRepository{
+		readThrough:              newReadThrough("user_attributes", backend, config),
+		IUserAttributeRepository: repository,
+	}
+}
+
+func (s *UserAttributeRepository) CreateMany(
+	ctx context.Context,
+	tx entities.Transaction,
+	userAttributes []entities.UserAttribute,
+) ([]entities.UserAttribute, error) {
+	out, err := s.IUserAttributeRepository.CreateMany(ctx, tx, userAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, 1)
+	seen := make(map[string]struct{})
+	for _, attribute := range out {
+		key := userIDKey(tenantOf(ctx, attribute.TenantID), attribute.UserID)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+	}
+
+	s.invalidate(ctx, tx, keys...)
+	return out, nil
+}
+
+func (s *UserAttributeRepository) GetByUserID(
+	ctx context.Context,
+	tx entities.Transaction,
+	userID uint,
+) ([]entities.UserAttribute, error) {
+	tenantID, ok := cacheable(ctx, tx)
+	if !ok {
+		return s.IUserAttributeRepository.GetByUserID(ctx, tx, userID)
+	}
+
+	key := userIDKey(tenantID, userID)
+	var cached []entities.UserAttribute
+	if found, err := s.get(ctx, key, &cached); found {
+		if err != nil {
+			return nil, err
+		}
+		return cached, nil
+	}
+
+	attributes, err := s.IUserAttributeRepository.GetByUserID(ctx, tx, userID)
+	s.set(ctx, key, attributes, err)
+	return attributes, err
+}
+
+func (s *UserAttributeRepository) Update(
+	ctx context.Context,
+	tx entities.Transaction,
+	userAttribute *entities.UserAttribute,
+) error {
+	if userAttribute == nil {
+		return s.IUserAttributeRepository.Update(ctx, tx, userAttribute)
+	}
+
+	keys := make([]string, 0, 2)
+	if userAttribute.UserID != 0 {
+		keys = append(keys, userIDKey(tenantOf(ctx, userAttribute.TenantID), userAttribute.UserID))
+	}
+	if current, err := s.IUserAttributeRepository.Get(ctx, tx, userAttribute.ID); err == nil {
+		keys = append(keys, userIDKey(current.TenantID, current.UserID))
+	}
+
+	if err := s.IUserAttributeRepository.Update(ctx, tx, userAttribute); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx, tx, keys...)
+	return nil
+}
+
+func (s *UserAttributeRepository) Delete(
+	ctx context.Context,
+	tx entities.Transaction,
+	permanent bool,
+	id uint,
+) error {
+	current, err := s.IUserAttributeRepository.Get(ctx, tx, id)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+
+	if err := s.IUserAttributeRepository.Delete(ctx, tx, permanent, id); err != nil {
+		return err
+	}
+
+	if current != nil {
+		s.invalidate(ctx, tx, userIDKey(tenantOf(ctx, current.TenantID), current.UserID))
+	}
+	return nil
+}
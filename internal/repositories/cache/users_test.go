@@ -0,0 +1,278 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories"
+	"github.com/tuantran1810/go-di-template/internal/repositories/memory"
+	sqlite "github.com/tuantran1810/go-di-template/internal/repositories/sqlite"
+	"github.com/tuantran1810/go-di-template/internal/usecases"
+)
+
+type countingUserRepository struct {
+	*memory.UserRepository
+	finds int
+}
+
+func (s *countingUserRepository) FindByUsername(
+	ctx context.Context,
+	tx entities.Transaction,
+	username string,
+	opts ...entities.QueryOption,
+) (*entities.User, error) {
+	s.finds++
+	return s.UserRepository.FindByUsername(ctx, tx, username, opts...)
+}
+
+type countingUserAttributeRepository struct {
+	*memory.UserAttributeRepository
+	gets int
+	// err fails the lookups when set
+	err error
+}
+
+func (s *countingUserAttributeRepository) GetByUserID(
+	ctx context.Context,
+	tx entities.Transaction,
+	userID uint,
+) ([]entities.UserAttribute, error) {
+	s.gets++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.UserAttributeRepository.GetByUserID(ctx, tx, userID)
+}
+
+func TestUserRepository(t *testing.T) {
+	ctx := context.Background()
+	r := memory.NewRepository()
+	inner := &countingUserRepository{UserRepository: memory.NewUserRepository(r)}
+	s := NewUserRepository(inner, NewLRUBackend(10), Config{})
+
+	hits := testutil.ToFloat64(cacheRequests.WithLabelValues("users", "hit"))
+	misses := testutil.ToFloat64(cacheRequests.WithLabelValues("users", "miss"))
+
+	// NotFound is cached as well
+	for range 2 {
+		if _, err := s.FindByUsername(ctx, nil, "user1"); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("s.FindByUsername() error = %v, want %v", err, entities.ErrNotFound)
+		}
+	}
+	if inner.finds != 1 {
+		t.Errorf("inner finds = %d, want 1", inner.finds)
+	}
+
+	// creating the user drops the cached NotFound
+	created, err := s.Create(ctx, nil, &entities.User{Username: "user1", Name: "name1"})
+	if err != nil {
+		t.Fatalf("s.Create() error = %v", err)
+	}
+	for range 2 {
+		got, err := s.FindByUsername(ctx, nil, "user1")
+		if err != nil || got.ID != created.ID || got.Name != "name1" {
+			t.Fatalf("s.FindByUsername() = %v, %v, want %v", got, err, created)
+		}
+	}
+	if inner.finds != 2 {
+		t.Errorf("inner finds = %d, want 2", inner.finds)
+	}
+
+	if err := s.Update(ctx, nil, &entities.User{ID: created.ID, Name: "name2"}); err != nil {
+		t.Fatalf("s.Update() error = %v", err)
+	}
+	if got, _ := s.FindByUsername(ctx, nil, "user1"); got == nil || got.Name != "name2" {
+		t.Errorf("s.FindByUsername() after update = %v, want name2", got)
+	}
+
+	// a lookup with query options bypasses the cache
+	memory.NewUserAttributeRepository(r, inner.UserRepository)
+	if got, err := s.FindByUsername(ctx, nil, "user1", entities.Preload("Attributes")); err != nil || got.Attributes == nil {
+		t.Errorf("s.FindByUsername() with preload = %v, %v, want the attributes", got, err)
+	}
+
+	if err := s.Delete(ctx, nil, false, created.ID); err != nil {
+		t.Fatalf("s.Delete() error = %v", err)
+	}
+	if _, err := s.FindByUsername(ctx, nil, "user1"); !errors.Is(err, entities.ErrNotFound) {
+		t.Errorf("s.FindByUsername() after delete error = %v, want %v", err, entities.ErrNotFound)
+	}
+
+	// a transaction bypasses the cache
+	if err := r.RunTx(ctx, func(ctx context.Context, tx entities.Transaction) error {
+		_, err := s.FindByUsername(ctx, tx, "user1")
+		return err
+	}); !errors.Is(err, entities.ErrNotFound) {
+		t.Errorf("s.FindByUsername() in transaction error = %v, want %v", err, entities.ErrNotFound)
+	}
+	if inner.finds != 6 {
+		t.Errorf("inner finds = %d, want 6", inner.finds)
+	}
+
+	if got := testutil.ToFloat64(cacheRequests.WithLabelValues("users", "hit")) - hits; got != 2 {
+		t.Errorf("cache hits = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(cacheRequests.WithLabelValues("users", "miss")) - misses; got != 4 {
+		t.Errorf("cache misses = %v, want 4", got)
+	}
+}
+
+func TestUserAttributeRepository(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := memory.NewRepository()
+	inner := &countingUserAttributeRepository{
+		UserAttributeRepository: memory.NewUserAttributeRepository(r, memory.NewUserRepository(r)),
+	}
+	s := NewUserAttributeRepository(inner, NewLRUBackend(10), Config{})
+
+	for range 2 {
+		got, err := s.GetByUserID(ctx, nil, 1)
+		if err != nil || len(got) != 0 {
+			t.Fatalf("s.GetByUserID() = %v, %v, want empty", got, err)
+		}
+	}
+	if inner.gets != 1 {
+		t.Errorf("inner gets = %d, want 1", inner.gets)
+	}
+
+	created, err := s.CreateMany(ctx, nil, []entities.UserAttribute{{UserID: 1, Key: "key1", Value: "value1"}})
+	if err != nil {
+		t.Fatalf("s.CreateMany() error = %v", err)
+	}
+	if got, _ := s.GetByUserID(ctx, nil, 1); len(got) != 1 || got[0].Value != "value1" {
+		t.Errorf("s.GetByUserID() after create = %v", got)
+	}
+
+	if err := s.Update(ctx, nil, &entities.UserAttribute{ID: created[0].ID, Value: "value2"}); err != nil {
+		t.Fatalf("s.Update() error = %v", err)
+	}
+	if got, _ := s.GetByUserID(ctx, nil, 1); len(got) != 1 || got[0].Value != "value2" {
+		t.Errorf("s.GetByUserID() after update = %v", got)
+	}
+
+	if err := s.Delete(ctx, nil, true, created[0].ID); err != nil {
+		t.Fatalf("s.Delete() error = %v", err)
+	}
+	if got, _ := s.GetByUserID(ctx, nil, 1); len(got) != 0 {
+		t.Errorf("s.GetByUserID() after delete = %v", got)
+	}
+	if inner.gets != 4 {
+		t.Errorf("inner gets = %d, want 4", inner.gets)
+	}
+}
+
+func TestUserAttributeRepository_NotFound(t *testing.T) {
+	t.Parallel()
+
+	r := memory.NewRepository()
+	inner := &countingUserAttributeRepository{
+		UserAttributeRepository: memory.NewUserAttributeRepository(r, memory.NewUserRepository(r)),
+		err:                     entities.ErrNotFound,
+	}
+	s := NewUserAttributeRepository(inner, NewLRUBackend(10), Config{})
+
+	for range 2 {
+		if _, err := s.GetByUserID(context.Background(), nil, 1); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("s.GetByUserID() error = %v, want %v", err, entities.ErrNotFound)
+		}
+	}
+	if inner.gets != 1 {
+		t.Errorf("inner gets = %d, want 1", inner.gets)
+	}
+}
+
+func TestUserRepository_Tenants(t *testing.T) {
+	t.Parallel()
+
+	r := memory.NewRepository()
+	inner := &countingUserRepository{UserRepository: memory.NewUserRepository(r)}
+	s := NewUserRepository(inner, NewLRUBackend(10), Config{})
+	tenant2 := entities.InjectTenantIDToContext(context.Background(), 2)
+
+	created, err := s.Create(tenant2, nil, &entities.User{Username: "user1"})
+	if err != nil {
+		t.Fatalf("s.Create() error = %v", err)
+	}
+	if got, err := s.FindByUsername(tenant2, nil, "user1"); err != nil || got.ID != created.ID {
+		t.Fatalf("s.FindByUsername() = %v, %v, want %v", got, err, created)
+	}
+
+	// the user cached for tenant 2 is not served to the default tenant
+	if _, err := s.FindByUsername(context.Background(), nil, "user1"); !errors.Is(err, entities.ErrNotFound) {
+		t.Errorf("s.FindByUsername() error = %v, want %v", err, entities.ErrNotFound)
+	}
+
+	// a read of all the tenants bypasses the cache
+	all := entities.WithAllTenants(context.Background())
+	for range 2 {
+		if _, err := s.FindByUsername(all, nil, "user1"); err != nil {
+			t.Fatalf("s.FindByUsername() error = %v", err)
+		}
+	}
+	if inner.finds != 4 {
+		t.Errorf("inner finds = %d, want 4", inner.finds)
+	}
+}
+
+// TestUserRepository_UncommittedTransaction interleaves a reader outside of a transaction with its writes:
+// the reader caches the state before the commit, which must be dropped once the transaction is committed.
+func TestUserRepository_UncommittedTransaction(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := sqlite.NewRepository(sqlite.RepositoryConfig{
+		DatabasePath: filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatalf("failed to open sqlite repository: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Stop(ctx) })
+
+	userRepository := repositories.NewUserRepository(database)
+	if err := userRepository.Start(ctx); err != nil {
+		t.Fatalf("failed to start repository: %v", err)
+	}
+	s := NewUserRepository(userRepository, NewLRUBackend(10), Config{})
+	txManager := usecases.NewTxManager(database)
+
+	var created *entities.User
+	if err := txManager.RunInTx(ctx, func(ctx context.Context) error {
+		if created, err = s.Create(ctx, nil, &entities.User{Username: "user1", Name: "name1"}); err != nil {
+			return err
+		}
+
+		// the user is not committed yet, the reader caches a NotFound
+		if _, err := s.FindByUsername(context.Background(), nil, "user1"); !errors.Is(err, entities.ErrNotFound) {
+			t.Errorf("s.FindByUsername() before commit error = %v, want %v", err, entities.ErrNotFound)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("txManager.RunInTx() error = %v", err)
+	}
+	if got, err := s.FindByUsername(ctx, nil, "user1"); err != nil || got.ID != created.ID {
+		t.Fatalf("s.FindByUsername() after commit = %v, %v, want %v", got, err, created)
+	}
+
+	if err := txManager.RunInTx(ctx, func(ctx context.Context) error {
+		if err := s.Update(ctx, nil, &entities.User{ID: created.ID, Name: "name2"}); err != nil {
+			return err
+		}
+
+		// the update is not committed yet, the reader caches the previous name
+		if got, _ := s.FindByUsername(context.Background(), nil, "user1"); got == nil || got.Name != "name1" {
+			t.Errorf("s.FindByUsername() before commit = %v, want name1", got)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("txManager.RunInTx() error = %v", err)
+	}
+	if got, err := s.FindByUsername(ctx, nil, "user1"); err != nil || got.Name != "name2" {
+		t.Errorf("s.FindByUsername() after commit = %v, %v, want name2", got, err)
+	}
+}
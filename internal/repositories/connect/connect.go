@@ -0,0 +1,96 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/logger"
+)
+
+var log = logger.MustNamedLogger("connect")
+
+const (
+	DefaultDeadline       = time.Minute
+	DefaultInitialBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff     = 10 * time.Second
+)
+
+// Config is the retry of the connection to a database when its repository starts,
+// so that the server waits for a database starting along with it.
+type Config struct {
+	// Deadline is the total time of the attempts, DefaultDeadline when 0, a single attempt when negative
+	Deadline time.Duration
+	// InitialBackoff is the wait after the first failed attempt, doubled after each one, DefaultInitialBackoff when 0
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between two attempts, DefaultMaxBackoff when 0
+	MaxBackoff time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.Deadline == 0 {
+		c.Deadline = DefaultDeadline
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = DefaultInitialBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = DefaultMaxBackoff
+	}
+	c.MaxBackoff = max(c.MaxBackoff, c.InitialBackoff)
+
+	return c
+}
+
+// backoff is the wait after the attempt-th failed attempt, from 1, with a jitter of ±20%
+// so that the servers started together do not retry together.
+func (c Config) backoff(attempt int) time.Duration {
+	wait := c.InitialBackoff
+	for i := 1; i < attempt && wait < c.MaxBackoff; i++ {
+		wait *= 2
+	}
+	wait = min(wait, c.MaxBackoff)
+
+	return wait + time.Duration((rand.Float64()*0.4-0.2)*float64(wait))
+}
+
+// Retry calls connect until it succeeds, with an exponential backoff between the attempts, until the deadline
+// of the config or ctx, each attempt being given a context canceled at the deadline.
+// It returns the error of the last attempt, wrapped with ErrDatabase.
+func Retry(ctx context.Context, config Config, database string, connect func(ctx context.Context) error) error {
+	config = config.withDefaults()
+	if config.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Deadline)
+		defer cancel()
+	}
+
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := connect(ctx)
+		if err == nil {
+			if attempt > 1 {
+				log.Infof("connected to %s after %d attempts in %v", database, attempt, time.Since(start).Round(time.Millisecond))
+			}
+			return nil
+		}
+		if config.Deadline < 0 {
+			return fmt.Errorf("%w - failed to connect to %s: %w", entities.ErrDatabase, database, err)
+		}
+
+		wait := config.backoff(attempt)
+		log.Warnf("failed to connect to %s, attempt %d, retrying in %v: %v", database, attempt, wait.Round(time.Millisecond), err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf(
+				"%w - failed to connect to %s after %d attempts in %v: %w",
+				entities.ErrDatabase, database, attempt, time.Since(start).Round(time.Millisecond), err,
+			)
+		case <-timer.C:
+		}
+	}
+}
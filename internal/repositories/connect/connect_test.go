@@ -0,0 +1,115 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+func TestConfig_backoff(t *testing.T) {
+	t.Parallel()
+
+	config := Config{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}.withDefaults()
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 100 * time.Millisecond},
+		{attempt: 2, want: 200 * time.Millisecond},
+		{attempt: 4, want: 800 * time.Millisecond},
+		{attempt: 5, want: time.Second},
+		{attempt: 100, want: time.Second},
+	}
+	for _, tt := range tests {
+		for range 10 {
+			got := config.backoff(tt.attempt)
+			if low, high := tt.want*8/10, tt.want*12/10; got < low || got > high {
+				t.Errorf("backoff(%d) = %v, want within [%v, %v]", tt.attempt, got, low, high)
+			}
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	errUnreachable := errors.New("unreachable")
+	tests := []struct {
+		name         string
+		config       Config
+		failures     int
+		wantErr      bool
+		wantAttempts int
+	}{
+		{
+			name:         "first attempt",
+			config:       Config{InitialBackoff: time.Millisecond},
+			failures:     0,
+			wantAttempts: 1,
+		},
+		{
+			name:         "after failures",
+			config:       Config{InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond},
+			failures:     3,
+			wantAttempts: 4,
+		},
+		{
+			name:         "single attempt",
+			config:       Config{Deadline: -1, InitialBackoff: time.Millisecond},
+			failures:     3,
+			wantErr:      true,
+			wantAttempts: 1,
+		},
+		{
+			name:         "deadline",
+			config:       Config{Deadline: 50 * time.Millisecond, InitialBackoff: 20 * time.Millisecond},
+			failures:     100,
+			wantErr:      true,
+			wantAttempts: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			attempts := 0
+			err := Retry(context.Background(), tt.config, "test", func(ctx context.Context) error {
+				attempts++
+				if attempts <= tt.failures {
+					return errUnreachable
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Retry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && (!errors.Is(err, entities.ErrDatabase) || !errors.Is(err, errUnreachable)) {
+				t.Errorf("Retry() error = %v, want %v wrapping %v", err, entities.ErrDatabase, errUnreachable)
+			}
+			// the deadline falls within the backoff after the second attempt, jitter included
+			if attempts != tt.wantAttempts {
+				t.Errorf("Retry() made %d attempts, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetry_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	err := Retry(ctx, Config{InitialBackoff: time.Hour}, "test", func(context.Context) error {
+		cancel()
+		return errors.New("unreachable")
+	})
+	if !errors.Is(err, entities.ErrDatabase) {
+		t.Errorf("Retry() error = %v, want %v", err, entities.ErrDatabase)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Retry() returned after %v, want right after the cancellation", elapsed)
+	}
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/mysql"
+	"github.com/tuantran1810/go-di-template/internal/repositories/postgres"
+	sqlite "github.com/tuantran1810/go-di-template/internal/repositories/sqlite"
+	"gorm.io/gorm"
+)
+
+// Database is the SQL database the repositories of this package run on,
+// implemented by the Repository of the mysql, postgres and sqlite packages.
+type Database interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+	Check(ctx context.Context) error
+	DB() *gorm.DB
+	GetTransaction(tx entities.Transaction) *gorm.DB
+	GetContextTransaction(ctx context.Context, tx entities.Transaction) *gorm.DB
+	RunTx(ctx context.Context, funcs ...entities.DBTxHandleFunc) error
+	// GenerateError wraps an error of the database into the entities error its driver error code stands for
+	GenerateError(errStr string, err error) error
+}
+
+// GenericRepository is the generic repository of the backend of a Database, over the model T of the entity E.
+type GenericRepository[T, E any] interface {
+	Database
+	Ping(ctx context.Context) error
+	AutoMigrate(ctx context.Context) error
+	Create(ctx context.Context, tx entities.Transaction, entity *E) (*E, error)
+	CreateMany(ctx context.Context, tx entities.Transaction, entityArray []E) ([]E, error)
+	Get(ctx context.Context, tx entities.Transaction, id uint, opts ...entities.QueryOption) (*E, error)
+	GetMany(ctx context.Context, tx entities.Transaction, ids []uint, opts ...entities.QueryOption) ([]E, error)
+	GetByCriterias(
+		ctx context.Context,
+		tx entities.Transaction,
+		fields []string,
+		criterias map[string]any,
+		orderBys []string,
+		opts ...entities.QueryOption,
+	) (*E, error)
+	GetManyByCriterias(
+		ctx context.Context,
+		tx entities.Transaction,
+		fields []string,
+		criterias map[string]any,
+		orderBys []string,
+		offset int,
+		limit int,
+		opts ...entities.QueryOption,
+	) ([]E, error)
+	FindInBatches(ctx context.Context, tx entities.Transaction, criterias map[string]any, batchSize int) iter.Seq2[[]E, error]
+	Count(ctx context.Context, tx entities.Transaction, criterias map[string]any, opts ...entities.QueryOption) (int64, error)
+	Aggregate(ctx context.Context, tx entities.Transaction, query entities.AggregateQuery) ([]entities.AggregateRow, error)
+	Update(ctx context.Context, tx entities.Transaction, entity *E) error
+	Delete(ctx context.Context, tx entities.Transaction, permanent bool, id uint) error
+	DeleteMany(ctx context.Context, tx entities.Transaction, permanent bool, ids []uint) (int64, error)
+}
+
+// NewGenericRepository returns the generic repository of the backend of the database,
+// the aggregateColumns being the columns Aggregate() accepts.
+func NewGenericRepository[T, E any](
+	database Database,
+	transformer *entities.ExtendedDataTransformer[T, E],
+	aggregateColumns ...string,
+) GenericRepository[T, E] {
+	switch db := database.(type) {
+	case *mysql.Repository:
+		return mysql.NewGenericRepository(db, transformer).WithAggregateColumns(aggregateColumns...)
+	case *postgres.Repository:
+		return postgres.NewGenericRepository(db, transformer).WithAggregateColumns(aggregateColumns...)
+	case *sqlite.Repository:
+		return sqlite.NewGenericRepository(db, transformer).WithAggregateColumns(aggregateColumns...)
+	default:
+		panic(fmt.Sprintf("unsupported database %T", database))
+	}
+}
@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/mysql"
+	"github.com/tuantran1810/go-di-template/internal/repositories/postgres"
+	sqlite "github.com/tuantran1810/go-di-template/internal/repositories/sqlite"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestNewGenericRepository(t *testing.T) {
+	t.Parallel()
+	transformer := entities.NewExtendedDataTransformer(&userTransformer{})
+
+	sqliteRepository, err := sqlite.NewRepository(sqlite.RepositoryConfig{
+		DatabasePath: filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatalf("failed to open sqlite repository: %v", err)
+	}
+	t.Cleanup(func() { _ = sqliteRepository.Stop(context.Background()) })
+
+	tests := []struct {
+		name     string
+		database Database
+		wantType func(GenericRepository[User, entities.User]) bool
+	}{
+		{
+			name:     "mysql",
+			database: mysql.MustNewRepository(mysql.RepositoryConfig{}),
+			wantType: func(got GenericRepository[User, entities.User]) bool {
+				_, ok := got.(*mysql.GenericRepository[User, entities.User])
+				return ok
+			},
+		},
+		{
+			name:     "postgres",
+			database: postgres.MustNewRepository(postgres.RepositoryConfig{}),
+			wantType: func(got GenericRepository[User, entities.User]) bool {
+				_, ok := got.(*postgres.GenericRepository[User, entities.User])
+				return ok
+			},
+		},
+		{
+			name:     "sqlite",
+			database: sqliteRepository,
+			wantType: func(got GenericRepository[User, entities.User]) bool {
+				_, ok := got.(*sqlite.GenericRepository[User, entities.User])
+				return ok
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := NewGenericRepository(tt.database, transformer); !tt.wantType(got) {
+				t.Errorf("NewGenericRepository() = %T, want the %s generic repository", got, tt.name)
+			}
+		})
+	}
+}
+
+// TestRepositories_Sqlite runs the repositories of the server on a SQLite database.
+func TestRepositories_Sqlite(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	database, err := sqlite.NewRepository(sqlite.RepositoryConfig{
+		DatabasePath: filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatalf("failed to open sqlite repository: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Stop(ctx) })
+
+	tenantRepository := NewTenantRepository(database)
+	userRepository := NewUserRepository(database)
+	userAttributeRepository := NewUserAttributeRepository(database)
+	for _, start := range []func(context.Context) error{
+		tenantRepository.Start,
+		userRepository.Start,
+		userAttributeRepository.Start,
+	} {
+		if err := start(ctx); err != nil {
+			t.Fatalf("failed to start repository: %v", err)
+		}
+	}
+
+	user, err := userRepository.Create(ctx, nil, &entities.User{Username: "user1", Name: "name1"})
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if _, err := userRepository.Create(ctx, nil, &entities.User{Username: "user1"}); !errors.Is(err, entities.ErrConflicted) {
+		t.Errorf("userRepository.Create() error = %v, want %v", err, entities.ErrConflicted)
+	}
+
+	got, err := userRepository.FindByUsername(ctx, nil, "user1")
+	if err != nil {
+		t.Fatalf("failed to find user: %v", err)
+	}
+	if got.ID != user.ID || got.Name != "name1" {
+		t.Errorf("userRepository.FindByUsername() = %+v, want %+v", got, user)
+	}
+
+	if _, err := userAttributeRepository.CreateMany(ctx, nil, []entities.UserAttribute{
+		{UserID: user.ID, Key: "key1", Value: "value1"},
+		{UserID: user.ID, Key: "key2", Value: "value2"},
+	}); err != nil {
+		t.Fatalf("failed to create user attributes: %v", err)
+	}
+	count, err := userAttributeRepository.CountByUserName(ctx, nil, "user1")
+	if err != nil {
+		t.Fatalf("failed to count user attributes: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("userAttributeRepository.CountByUserName() = %d, want 2", count)
+	}
+}
+
+// queryCounter counts the statements run through a GORM logger.
+type queryCounter struct {
+	gormlogger.Interface
+	queries atomic.Int64
+}
+
+func (c *queryCounter) Trace(_ context.Context, _ time.Time, _ func() (string, int64), _ error) {
+	c.queries.Add(1)
+}
+
+// TestUserRepository_Preload checks that the users are read with their attributes by two queries,
+// whatever the number of users.
+func TestUserRepository_Preload(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	counter := &queryCounter{Interface: gormlogger.Discard}
+	database, err := sqlite.NewRepository(sqlite.RepositoryConfig{
+		DatabasePath: filepath.Join(t.TempDir(), "test.db"),
+		Logger:       counter,
+	})
+	if err != nil {
+		t.Fatalf("failed to open sqlite repository: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Stop(ctx) })
+
+	userRepository := NewUserRepository(database)
+	userAttributeRepository := NewUserAttributeRepository(database)
+	for _, start := range []func(context.Context) error{userRepository.Start, userAttributeRepository.Start} {
+		if err := start(ctx); err != nil {
+			t.Fatalf("failed to start repository: %v", err)
+		}
+	}
+
+	usernames := []string{"user1", "user2", "user3"}
+	attributes := make([]entities.UserAttribute, 0)
+	for i, username := range usernames {
+		user, err := userRepository.Create(ctx, nil, &entities.User{Username: username, Uuid: "uuid-" + username})
+		if err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		// user3 has no attribute
+		for j := range 2 - i/2*2 {
+			attributes = append(attributes, entities.UserAttribute{UserID: user.ID, Key: fmt.Sprintf("key%d", j), Value: username})
+		}
+	}
+	created, err := userAttributeRepository.CreateMany(ctx, nil, attributes)
+	if err != nil {
+		t.Fatalf("failed to create user attributes: %v", err)
+	}
+	if err := userAttributeRepository.Delete(ctx, nil, false, created[3].ID); err != nil {
+		t.Fatalf("failed to delete user attribute: %v", err)
+	}
+
+	start := counter.queries.Load()
+	users, err := userRepository.FindByUsernames(ctx, nil, append(usernames, "unknown"), entities.Preload("Attributes"))
+	if err != nil {
+		t.Fatalf("userRepository.FindByUsernames() error = %v", err)
+	}
+	if got := counter.queries.Load() - start; got != 2 {
+		t.Errorf("userRepository.FindByUsernames() ran %d queries, want 2", got)
+	}
+
+	if len(users) != 3 {
+		t.Fatalf("userRepository.FindByUsernames() = %d users, want 3", len(users))
+	}
+	wantKeys := [][]string{{"key0", "key1"}, {"key0"}, {}}
+	for i, user := range users {
+		if user.Username != usernames[i] || len(user.Attributes) != len(wantKeys[i]) {
+			t.Errorf("user %d = %s with %d attributes, want %s with %d", i, user.Username, len(user.Attributes), usernames[i], len(wantKeys[i]))
+			continue
+		}
+		for j, attribute := range user.Attributes {
+			if attribute.UserID != user.ID || attribute.Key != wantKeys[i][j] || attribute.Value != user.Username {
+				t.Errorf("user %s attribute %d = %+v, want %s", user.Username, j, attribute, wantKeys[i][j])
+			}
+		}
+	}
+
+	start = counter.queries.Load()
+	users, err = userRepository.FindByUuids(ctx, nil, []string{"uuid-user3", "uuid-user1", "unknown"}, entities.Preload("Attributes"))
+	if err != nil || len(users) != 2 || users[0].Username != "user1" || len(users[0].Attributes) != 2 || users[1].Username != "user3" {
+		t.Errorf("userRepository.FindByUuids() = %+v, %v, want user1 and user3", users, err)
+	}
+	if got := counter.queries.Load() - start; got != 2 {
+		t.Errorf("userRepository.FindByUuids() ran %d queries, want 2", got)
+	}
+
+	user, err := userRepository.FindByUsername(ctx, nil, "user1")
+	if err != nil || user.Attributes != nil {
+		t.Errorf("userRepository.FindByUsername() without preload = %+v, %v, want no attributes", user, err)
+	}
+	if _, err := userRepository.FindByUsername(ctx, nil, "user1", entities.Preload("Unknown")); err == nil {
+		t.Errorf("userRepository.FindByUsername() with an unknown association succeeded, want an error")
+	}
+
+	// a soft deleted user is read with its soft deleted attributes, in its tenant only
+	if err := userRepository.Delete(ctx, nil, false, users[0].ID); err != nil {
+		t.Fatalf("failed to delete user: %v", err)
+	}
+	if _, err := userRepository.FindByUsername(ctx, nil, "user1"); !errors.Is(err, entities.ErrNotFound) {
+		t.Errorf("userRepository.FindByUsername() of a deleted user error = %v, want %v", err, entities.ErrNotFound)
+	}
+	user, err = userRepository.FindByUsername(ctx, nil, "user2", entities.WithDeleted(), entities.Preload("Attributes"))
+	if err != nil || user.DeletedAt != nil || len(user.Attributes) != 2 {
+		t.Errorf("userRepository.FindByUsername() with deleted = %+v, %v, want user2 with its 2 attributes", user, err)
+	}
+	user, err = userRepository.FindByUsername(ctx, nil, "user1", entities.OnlyDeleted(), entities.Preload("Attributes"))
+	if err != nil || user.DeletedAt == nil || len(user.Attributes) != 2 {
+		t.Errorf("userRepository.FindByUsername() of deleted only = %+v, %v, want deleted user1", user, err)
+	}
+	otherTenant := entities.InjectTenantIDToContext(ctx, 2)
+	if _, err := userRepository.FindByUsername(otherTenant, nil, "user1", entities.WithDeleted()); !errors.Is(err, entities.ErrNotFound) {
+		t.Errorf("userRepository.FindByUsername() of another tenant error = %v, want %v", err, entities.ErrNotFound)
+	}
+}
@@ -0,0 +1,285 @@
+package dbcopy
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"reflect"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/audit"
+	"github.com/tuantran1810/go-di-template/libs/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var log = logger.MustNamedLogger("dbcopy")
+
+const (
+	DefaultBatchSize = 500
+
+	// Precision is the precision of the copied timestamps, the one of the MySQL datetime(3) columns,
+	// the coarsest of the supported databases, so that a copy has the values of its source whatever the databases
+	Precision = time.Millisecond
+)
+
+// TableResult is the number of rows copied to a table and their checksum, see Checksum.
+type TableResult struct {
+	Table    string
+	Rows     int64
+	Checksum string
+}
+
+// Copy copies the tables of the models, in their order, from a database to another one, by batches of batchSize rows
+// read in the order of their primary key, each one inserted by a statement. The rows of all the tenants are copied,
+// the soft deleted ones included, with their IDs and timestamps, and without writing audit events, the audit events
+// being a table to copy. The tables are migrated in the target database and must be empty there.
+// The PostgreSQL sequences of the primary keys are reset after the largest copied ID.
+//
+// The rows go through the models, so that their values are converted between the databases and the encrypted
+// columns are encrypted again by the target database. Once all the tables are copied, their row counts
+// and checksums in the target database are compared to the copied rows.
+func Copy(ctx context.Context, from, to *gorm.DB, batchSize int, models ...any) ([]TableResult, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	ctx = audit.WithoutAudit(entities.WithAllTenants(ctx))
+	results := make([]TableResult, 0, len(models))
+	for _, model := range models {
+		result, err := copyModel(ctx, from.WithContext(ctx), to.WithContext(ctx), batchSize, model)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+
+	for i, model := range models {
+		rows, checksum, err := Checksum(ctx, to, batchSize, model)
+		if err != nil {
+			return results, err
+		}
+		if rows != results[i].Rows || checksum != results[i].Checksum {
+			return results, fmt.Errorf(
+				"%w - copied %d rows of %s with checksum %s, the target has %d rows with checksum %s",
+				entities.ErrDatabase, results[i].Rows, results[i].Table, results[i].Checksum, rows, checksum,
+			)
+		}
+		log.Infof("verified %d %s rows", rows, results[i].Table)
+	}
+
+	return results, nil
+}
+
+func parse(db *gorm.DB, model any) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, fmt.Errorf("%w - failed to parse model: %w", entities.ErrInternal, err)
+	}
+	if stmt.Schema.PrioritizedPrimaryField == nil {
+		return nil, fmt.Errorf("%w - %s has no primary key", entities.ErrInternal, stmt.Schema.Name)
+	}
+
+	return stmt.Schema, nil
+}
+
+func copyModel(ctx context.Context, from, to *gorm.DB, batchSize int, model any) (TableResult, error) {
+	s, err := parse(from, model)
+	if err != nil {
+		return TableResult{}, err
+	}
+
+	result := TableResult{Table: s.Table}
+	if err := to.AutoMigrate(model); err != nil {
+		return result, fmt.Errorf("%w - failed to migrate %s: %w", entities.ErrDatabase, s.Table, err)
+	}
+	var count int64
+	if err := to.Unscoped().Model(model).Count(&count).Error; err != nil {
+		return result, fmt.Errorf("%w - failed to count %s rows: %w", entities.ErrDatabase, s.Table, err)
+	}
+	if count > 0 {
+		return result, fmt.Errorf("%w - %s has %d rows in the target database", entities.ErrConflicted, s.Table, count)
+	}
+
+	h := sha256.New()
+	if from.Migrator().HasTable(model) {
+		err = scan(ctx, from, s, model, batchSize, func(rows reflect.Value) error {
+			batch := rows.Elem()
+			for i := range batch.Len() {
+				if err := truncateTimes(ctx, s, batch.Index(i)); err != nil {
+					return fmt.Errorf("%w - failed to truncate %s timestamps: %w", entities.ErrInternal, s.Table, err)
+				}
+				hashRow(ctx, h, s, batch.Index(i))
+			}
+
+			if err := to.Omit(clause.Associations).Create(rows.Interface()).Error; err != nil {
+				return fmt.Errorf("%w - failed to insert %s rows: %w", entities.ErrDatabase, s.Table, err)
+			}
+
+			result.Rows += int64(batch.Len())
+			log.Infof("copied %d %s rows", result.Rows, s.Table)
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+	} else {
+		log.Warnf("no %s table in the source database", s.Table)
+	}
+	result.Checksum = hex.EncodeToString(h.Sum(nil))
+
+	if err := resetSequence(to, s); err != nil {
+		return result, fmt.Errorf("%w - failed to reset the sequence of %s: %w", entities.ErrDatabase, s.Table, err)
+	}
+
+	return result, nil
+}
+
+// Checksum returns the number of rows of the table of the model, all the tenants and the soft deleted rows included,
+// and the SHA-256 of their values in the order of their primary key, read by batches of batchSize rows.
+// The encrypted columns are hashed decrypted and the timestamps in UTC at the Precision of a copy,
+// so that a table and its copy have the same checksum whatever their databases.
+func Checksum(ctx context.Context, db *gorm.DB, batchSize int, model any) (int64, string, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	ctx = entities.WithAllTenants(ctx)
+	db = db.WithContext(ctx)
+	s, err := parse(db, model)
+	if err != nil {
+		return 0, "", err
+	}
+
+	var rows int64
+	h := sha256.New()
+	err = scan(ctx, db, s, model, batchSize, func(batch reflect.Value) error {
+		batch = batch.Elem()
+		for i := range batch.Len() {
+			hashRow(ctx, h, s, batch.Index(i))
+		}
+		rows += int64(batch.Len())
+		return nil
+	})
+	if err != nil {
+		return rows, "", err
+	}
+
+	return rows, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// scan calls f with the pointers to the batches of rows of the table of the model, in the order of the primary key.
+func scan(ctx context.Context, db *gorm.DB, s *schema.Schema, model any, batchSize int, f func(rows reflect.Value) error) error {
+	primary := s.PrioritizedPrimaryField
+	var last any
+	for {
+		query := db.Unscoped().Model(model).
+			Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: primary.DBName}}).
+			Limit(batchSize)
+		if last != nil {
+			query = query.Where(clause.Gt{Column: clause.Column{Table: clause.CurrentTable, Name: primary.DBName}, Value: last})
+		}
+
+		rows := reflect.New(reflect.SliceOf(s.ModelType))
+		if err := query.Find(rows.Interface()).Error; err != nil {
+			return fmt.Errorf("%w - failed to load %s rows: %w", entities.ErrDatabase, s.Table, err)
+		}
+		batch := rows.Elem()
+		if batch.Len() == 0 {
+			return nil
+		}
+
+		last = primary.ReflectValueOf(ctx, batch.Index(batch.Len()-1)).Interface()
+		if err := f(rows); err != nil {
+			return err
+		}
+	}
+}
+
+// truncateTimes truncates the timestamps of a row to the Precision.
+func truncateTimes(ctx context.Context, s *schema.Schema, row reflect.Value) error {
+	for _, field := range s.Fields {
+		if field.DBName == "" {
+			continue
+		}
+
+		value, zero := field.ValueOf(ctx, row)
+		if zero {
+			continue
+		}
+
+		var truncated any
+		switch v := value.(type) {
+		case time.Time:
+			truncated = v.Truncate(Precision)
+		case *time.Time:
+			t := v.Truncate(Precision)
+			truncated = &t
+		case sql.NullTime:
+			truncated = sql.NullTime{Time: v.Time.Truncate(Precision), Valid: v.Valid}
+		case gorm.DeletedAt:
+			truncated = gorm.DeletedAt{Time: v.Time.Truncate(Precision), Valid: v.Valid}
+		default:
+			continue
+		}
+		if err := field.Set(ctx, row, truncated); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hashRow(ctx context.Context, h hash.Hash, s *schema.Schema, row reflect.Value) {
+	for _, field := range s.Fields {
+		if field.DBName == "" {
+			continue
+		}
+
+		value, _ := field.ValueOf(ctx, row)
+		fmt.Fprintf(h, "%s=%v;", field.DBName, normalize(value))
+	}
+	fmt.Fprintln(h)
+}
+
+// normalize returns the value a column holds whatever the database, the timestamps in UTC at the Precision.
+func normalize(value any) any {
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		value = v.Elem().Interface()
+	}
+
+	if valuer, ok := value.(driver.Valuer); ok {
+		if v, err := valuer.Value(); err == nil {
+			value = v
+		}
+	}
+
+	if t, ok := value.(time.Time); ok {
+		return t.UTC().Truncate(Precision).Format(time.RFC3339Nano)
+	}
+
+	return value
+}
+
+// resetSequence sets the PostgreSQL sequence of the primary key after its largest value,
+// the other databases moving their auto increments along the inserted IDs.
+func resetSequence(db *gorm.DB, s *schema.Schema) error {
+	primary := s.PrioritizedPrimaryField
+	if db.Dialector.Name() != "postgres" || !primary.AutoIncrement {
+		return nil
+	}
+
+	return db.Exec(
+		"SELECT setval(pg_get_serial_sequence(?, ?), COALESCE(MAX(?), 0) + 1, false) FROM ?",
+		s.Table, primary.DBName, clause.Column{Name: primary.DBName}, clause.Table{Name: s.Table},
+	).Error
+}
@@ -0,0 +1,194 @@
+package dbcopy
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/audit"
+	"github.com/tuantran1810/go-di-template/internal/repositories/encryption"
+	"github.com/tuantran1810/go-di-template/internal/repositories/tenancy"
+	gormPostgres "gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type Item struct {
+	gorm.Model
+	TenantID uint
+	Name     string `gorm:"serializer:encrypted"`
+}
+
+func newTestKeyring(t *testing.T) *encryption.Keyring {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if _, err := encryption.AddMasterKey(path); err != nil {
+		t.Fatalf("failed to add master key: %v", err)
+	}
+	keyring, err := encryption.LoadKeyring(path)
+	if err != nil {
+		t.Fatalf("failed to load keyring: %v", err)
+	}
+
+	return keyring
+}
+
+func newTestDB(t *testing.T, keyring *encryption.Keyring) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(
+		sqlite.Open(filepath.Join(t.TempDir(), "copy.db")),
+		&gorm.Config{Logger: gormlogger.Discard},
+	)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	for _, plugin := range []gorm.Plugin{encryption.NewPlugin(keyring), tenancy.NewPlugin(), audit.NewPlugin(32766)} {
+		if err := db.Use(plugin); err != nil {
+			t.Fatalf("failed to install %s plugin: %v", plugin.Name(), err)
+		}
+	}
+
+	return db
+}
+
+func TestCopy(t *testing.T) {
+	t.Parallel()
+
+	keyring := newTestKeyring(t)
+	from := newTestDB(t, keyring)
+	to := newTestDB(t, keyring)
+	ctx := entities.WithAllTenants(context.Background())
+	if err := from.AutoMigrate(&Item{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	items := []Item{
+		{Model: gorm.Model{ID: 3, CreatedAt: createdAt, UpdatedAt: createdAt}, TenantID: 1, Name: "name3"},
+		{Model: gorm.Model{ID: 5, CreatedAt: createdAt, UpdatedAt: createdAt}, TenantID: 2, Name: "name5"},
+		{Model: gorm.Model{ID: 8, CreatedAt: createdAt, UpdatedAt: createdAt}, TenantID: 2, Name: "name8"},
+	}
+	if err := from.WithContext(ctx).Create(&items).Error; err != nil {
+		t.Fatalf("failed to create items: %v", err)
+	}
+	if err := from.WithContext(ctx).Delete(&Item{}, 5).Error; err != nil {
+		t.Fatalf("failed to delete item: %v", err)
+	}
+
+	// two rows per batch, so that the copy takes several
+	results, err := Copy(context.Background(), from, to, 2, &Item{}, &audit.Event{})
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Table != "items" || results[0].Rows != 3 ||
+		results[1].Table != audit.TableName || results[1].Rows != 4 {
+		t.Fatalf("Copy() = %+v, want 3 items and 4 audit events", results)
+	}
+
+	var got []Item
+	if err := to.WithContext(ctx).Unscoped().Order("id").Find(&got).Error; err != nil {
+		t.Fatalf("failed to find items: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d items, want 3", len(got))
+	}
+	for i, item := range got {
+		want := items[i]
+		if item.ID != want.ID || item.TenantID != want.TenantID || item.Name != want.Name ||
+			!item.CreatedAt.Equal(createdAt.Truncate(Precision)) {
+			t.Errorf("item %d = %+v, want %+v", i, item, want)
+		}
+		if item.DeletedAt.Valid != (want.ID == 5) {
+			t.Errorf("item %d deleted = %v", item.ID, item.DeletedAt.Valid)
+		}
+	}
+
+	var events int64
+	if err := to.WithContext(ctx).Model(&audit.Event{}).Count(&events).Error; err != nil {
+		t.Fatalf("failed to count audit events: %v", err)
+	}
+	if events != 4 {
+		t.Errorf("got %d audit events, want the 4 copied ones", events)
+	}
+
+	created := Item{TenantID: 1, Name: "name9"}
+	if err := to.WithContext(ctx).Create(&created).Error; err != nil || created.ID != 9 {
+		t.Errorf("created item %d, %v, want 9", created.ID, err)
+	}
+
+	if _, err := Copy(context.Background(), from, to, 2, &Item{}); !errors.Is(err, entities.ErrConflicted) {
+		t.Errorf("Copy() to a table with rows error = %v, want %v", err, entities.ErrConflicted)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	t.Parallel()
+
+	keyring := newTestKeyring(t)
+	db := newTestDB(t, keyring)
+	if err := db.AutoMigrate(&Item{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if err := db.Create([]Item{{Name: "name1"}, {Name: "name2"}}).Error; err != nil {
+		t.Fatalf("failed to create items: %v", err)
+	}
+
+	rows, checksum, err := Checksum(context.Background(), db, 1, &Item{})
+	if err != nil || rows != 2 {
+		t.Fatalf("Checksum() = %d, %v, want 2 rows", rows, err)
+	}
+
+	// encrypted again, with another nonce
+	if err := db.Model(&Item{}).Where("id = ?", 1).UpdateColumn("name", "name1").Error; err != nil {
+		t.Fatalf("failed to update item: %v", err)
+	}
+	if _, got, _ := Checksum(context.Background(), db, 1, &Item{}); got != checksum {
+		t.Errorf("Checksum() = %s after encrypting the same value, want %s", got, checksum)
+	}
+
+	if err := db.Model(&Item{}).Where("id = ?", 2).UpdateColumn("name", "other").Error; err != nil {
+		t.Fatalf("failed to update item: %v", err)
+	}
+	if _, got, _ := Checksum(context.Background(), db, 1, &Item{}); got == checksum {
+		t.Errorf("Checksum() = %s after an update, want another one", got)
+	}
+}
+
+func Test_resetSequence(t *testing.T) {
+	t.Parallel()
+
+	db, err := gorm.Open(gormPostgres.Open("host=127.0.0.1 user=postgres dbname=test sslmode=disable"), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	s, err := parse(db, &Item{})
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+
+	var sql string
+	if err := db.Callback().Raw().After("gorm:raw").Register("test:sql", func(db *gorm.DB) {
+		sql = db.Statement.SQL.String()
+	}); err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+	if err := resetSequence(db, s); err != nil {
+		t.Fatalf("resetSequence() error = %v", err)
+	}
+
+	want := `SELECT setval(pg_get_serial_sequence($1, $2), COALESCE(MAX("id"), 0) + 1, false) FROM "items"`
+	if !strings.Contains(sql, want) {
+		t.Errorf("resetSequence() = %s, want %s", sql, want)
+	}
+}
@@ -0,0 +1,159 @@
+package dbmetrics
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/logger"
+	"gorm.io/gorm"
+)
+
+var log = logger.MustNamedLogger("dbmetrics")
+
+const (
+	pluginName   = "dbmetrics"
+	startTimeKey = "dbmetrics:start_time"
+	unknownTable = "unknown"
+)
+
+var (
+	queryDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "Latency of the database statements, by database, table and operation.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		},
+		[]string{"database", "table", "operation"},
+	)
+	queryErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "db_query_errors_total",
+			Help: "Number of failed database statements, by database, table, operation and entities error class.",
+		},
+		[]string{"database", "table", "operation", "class"},
+	)
+)
+
+// errorClasses are the entities errors reported as the class label, in matching order.
+var errorClasses = []struct {
+	err   error
+	class string
+}{
+	{entities.ErrCanceled, "canceled"},
+	{entities.ErrNotFound, "not_found"},
+	{entities.ErrConflicted, "conflicted"},
+	{entities.ErrLocked, "locked"},
+	{entities.ErrTooManyRequests, "too_many_requests"},
+	{entities.ErrMalformed, "malformed"},
+	{entities.ErrInvalid, "invalid"},
+	{entities.ErrDatabase, "database"},
+}
+
+func errorClass(err error) string {
+	for _, c := range errorClasses {
+		if errors.Is(err, c.err) {
+			return c.class
+		}
+	}
+
+	return "unknown"
+}
+
+// Plugin is a GORM plugin observing the latency and the errors of every statement.
+type Plugin struct {
+	database string
+	classify func(err error) error
+}
+
+// NewPlugin creates the plugin for the database label, classify maps a driver error to its entities error.
+func NewPlugin(database string, classify func(err error) error) *Plugin {
+	return &Plugin{
+		database: database,
+		classify: classify,
+	}
+}
+
+func (p *Plugin) Name() string {
+	return pluginName
+}
+
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	type registerFunc func(name string, fn func(*gorm.DB)) error
+
+	callback := db.Callback()
+	operations := []struct {
+		operation string
+		before    registerFunc
+		after     registerFunc
+	}{
+		{"create", callback.Create().Before("gorm:create").Register, callback.Create().After("gorm:create").Register},
+		{"query", callback.Query().Before("gorm:query").Register, callback.Query().After("gorm:query").Register},
+		{"update", callback.Update().Before("gorm:update").Register, callback.Update().After("gorm:update").Register},
+		{"delete", callback.Delete().Before("gorm:delete").Register, callback.Delete().After("gorm:delete").Register},
+		{"row", callback.Row().Before("gorm:row").Register, callback.Row().After("gorm:row").Register},
+		{"raw", callback.Raw().Before("gorm:raw").Register, callback.Raw().After("gorm:raw").Register},
+	}
+
+	for _, op := range operations {
+		if err := op.before(fmt.Sprintf("%s:before_%s", pluginName, op.operation), p.before); err != nil {
+			return err
+		}
+		if err := op.after(fmt.Sprintf("%s:after_%s", pluginName, op.operation), p.after(op.operation)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Plugin) before(db *gorm.DB) {
+	db.InstanceSet(startTimeKey, time.Now())
+}
+
+func (p *Plugin) after(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startTimeKey)
+		if !ok {
+			return
+		}
+		startTime, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = unknownTable
+		}
+
+		queryDuration.WithLabelValues(p.database, table, operation).Observe(time.Since(startTime).Seconds())
+		if db.Error != nil {
+			queryErrors.WithLabelValues(p.database, table, operation, errorClass(p.classify(db.Error))).Inc()
+		}
+	}
+}
+
+// Instrument installs the plugin on db and registers the connection pool stats of sqlDB with Prometheus.
+// The returned function unregisters the pool stats, it is to be called when the database is closed.
+func Instrument(db *gorm.DB, sqlDB *sql.DB, database string, classify func(err error) error) (func(), error) {
+	if err := db.Use(NewPlugin(database, classify)); err != nil {
+		return nil, fmt.Errorf("%w - failed to install metrics plugin: %w", entities.ErrInternal, err)
+	}
+
+	collector := collectors.NewDBStatsCollector(sqlDB, database)
+	if err := prometheus.Register(collector); err != nil {
+		// another repository of the same database already reports the pool stats
+		log.Warnf("failed to register database stats of %s: %v", database, err)
+		return func() {}, nil
+	}
+
+	return func() {
+		prometheus.Unregister(collector)
+	}, nil
+}
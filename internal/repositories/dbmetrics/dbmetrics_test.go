@@ -0,0 +1,133 @@
+package dbmetrics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type Data struct {
+	ID    uint
+	Value string
+}
+
+func classify(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.ErrNotFound
+	}
+
+	return entities.ErrDatabase
+}
+
+func hasDBStats(t *testing.T, database string) bool {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != "go_sql_open_connections" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "db_name" && label.GetValue() == database {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func TestInstrument(t *testing.T) {
+	t.Parallel()
+
+	database := "sqlite:test_instrument"
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormlogger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database instance: %v", err)
+	}
+
+	unregister, err := Instrument(db, sqlDB, database, classify)
+	if err != nil {
+		t.Fatalf("Instrument() error = %v", err)
+	}
+	if !hasDBStats(t, database) {
+		t.Errorf("database stats of %s are not registered", database)
+	}
+
+	if err := db.AutoMigrate(&Data{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if err := db.Create(&Data{ID: 1, Value: "value1"}).Error; err != nil {
+		t.Fatalf("failed to create data: %v", err)
+	}
+	if err := db.Create(&Data{ID: 1, Value: "value1"}).Error; err == nil {
+		t.Fatalf("expected a conflict when creating the same id")
+	}
+	var data Data
+	if err := db.First(&data, 10).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if err := db.Model(&Data{}).Where("id = ?", 1).Update("value", "value2").Error; err != nil {
+		t.Fatalf("failed to update data: %v", err)
+	}
+	if err := db.Delete(&Data{}, 1).Error; err != nil {
+		t.Fatalf("failed to delete data: %v", err)
+	}
+
+	for _, operation := range []string{"create", "query", "update", "delete"} {
+		if !queryDuration.DeleteLabelValues(database, "data", operation) {
+			t.Errorf("no latency observed for operation %s", operation)
+		}
+	}
+	for class, want := range map[string]float64{"database": 1, "not_found": 1} {
+		operation := map[string]string{"database": "create", "not_found": "query"}[class]
+		got := testutil.ToFloat64(queryErrors.WithLabelValues(database, "data", operation, class))
+		if got != want {
+			t.Errorf("errors of class %s = %v, want %v", class, got, want)
+		}
+	}
+
+	unregister()
+	if hasDBStats(t, database) {
+		t.Errorf("database stats of %s are still registered", database)
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("%w - test", entities.ErrNotFound), "not_found"},
+		{fmt.Errorf("%w - test", entities.ErrCanceled), "canceled"},
+		{fmt.Errorf("%w - test", entities.ErrInvalid), "invalid"},
+		{fmt.Errorf("%w - test", entities.ErrConflicted), "conflicted"},
+		{fmt.Errorf("%w - test", entities.ErrLocked), "locked"},
+		{fmt.Errorf("%w - test", entities.ErrTooManyRequests), "too_many_requests"},
+		{fmt.Errorf("%w - test", entities.ErrDatabase), "database"},
+		{errors.New("test"), "unknown"},
+	}
+	for _, tt := range tests {
+		if got := errorClass(tt.err); got != tt.want {
+			t.Errorf("errorClass(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package dbtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/logger"
+)
+
+var log = logger.MustNamedLogger("dbtls")
+
+// Config is the TLS of the connections to a database.
+type Config struct {
+	// Enabled requires TLS, the connections are in plain text otherwise
+	Enabled bool
+	// CAFile is the PEM bundle of the CAs the server certificate is verified with, the system ones when empty
+	CAFile string
+	// CertFile and KeyFile are the PEM client certificate and key authenticating to the server, none when empty
+	CertFile string
+	KeyFile  string
+	// ServerName is the name the server certificate is verified for, the host of the database when empty
+	ServerName string
+	// SkipServerNameVerification verifies the server certificate with the CAs only, whatever its names
+	SkipServerNameVerification bool
+}
+
+// stamp identifies the version of a file, a file written again has another one.
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func stampOf(path string) (stamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return stamp{}, err
+	}
+
+	return stamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// files holds the CAs and the client certificate loaded from the files of a Config,
+// loaded again on the next handshake once one of the files changed.
+type files struct {
+	config Config
+
+	mu     sync.Mutex
+	stamps [3]stamp
+	roots  *x509.CertPool
+	cert   *tls.Certificate
+}
+
+func (f *files) paths() [3]string {
+	return [3]string{f.config.CAFile, f.config.CertFile, f.config.KeyFile}
+}
+
+// current returns the CAs and the client certificate, loaded again when their files changed.
+// A failed reload keeps the previous ones, e.g. while the files are being replaced.
+func (f *files) current() (*x509.CertPool, *tls.Certificate, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var stamps [3]stamp
+	for i, path := range f.paths() {
+		if path == "" {
+			continue
+		}
+		s, err := stampOf(path)
+		if err != nil {
+			return f.keep(fmt.Errorf("failed to stat %s: %w", path, err))
+		}
+		stamps[i] = s
+	}
+	if f.cert != nil && stamps == f.stamps {
+		return f.roots, f.cert, nil
+	}
+
+	roots, cert, err := f.load()
+	if err != nil {
+		return f.keep(err)
+	}
+	if f.cert != nil {
+		log.Infow("reloaded database certificates", "ca", f.config.CAFile, "cert", f.config.CertFile)
+	}
+	f.stamps, f.roots, f.cert = stamps, roots, cert
+
+	return f.roots, f.cert, nil
+}
+
+// keep returns the previous CAs and client certificate after a failed reload, the error when none was loaded.
+func (f *files) keep(err error) (*x509.CertPool, *tls.Certificate, error) {
+	if f.cert == nil {
+		return nil, nil, err
+	}
+	log.Warnw("failed to reload database certificates, keeping the previous ones", "error", err)
+
+	return f.roots, f.cert, nil
+}
+
+func (f *files) load() (*x509.CertPool, *tls.Certificate, error) {
+	var roots *x509.CertPool
+	if f.config.CAFile != "" {
+		bundle, err := os.ReadFile(f.config.CAFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", f.config.CAFile, err)
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(bundle) {
+			return nil, nil, fmt.Errorf("no PEM certificate in %s", f.config.CAFile)
+		}
+	}
+
+	// an empty certificate sends none to the server
+	cert := &tls.Certificate{}
+	if f.config.CertFile != "" {
+		pair, err := tls.LoadX509KeyPair(f.config.CertFile, f.config.KeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load %s: %w", f.config.CertFile, err)
+		}
+		cert = &pair
+	}
+
+	return roots, cert, nil
+}
+
+// Load loads the files of the config and returns the tls.Config of the connections to the database at host.
+// The files are checked on each handshake and loaded again once changed, so that the rotated certificates
+// are used by the next connections of the pool without restarting.
+// The server certificate is verified by the tls.Config itself, against the CAs loaded last.
+func Load(config Config, host string) (*tls.Config, error) {
+	if config.CertFile == "" && config.KeyFile != "" || config.CertFile != "" && config.KeyFile == "" {
+		return nil, fmt.Errorf("%w - a client certificate needs both its cert and key files", entities.ErrInvalid)
+	}
+	if config.ServerName == "" {
+		config.ServerName = host
+	}
+
+	f := &files{config: config}
+	if _, _, err := f.current(); err != nil {
+		return nil, fmt.Errorf("%w - failed to load database certificates: %w", entities.ErrInvalid, err)
+	}
+
+	return &tls.Config{
+		ServerName: config.ServerName,
+		MinVersion: tls.VersionTLS12,
+		// the default verification would use the CAs loaded first, VerifyConnection verifies with the current ones
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			roots, _, err := f.current()
+			if err != nil {
+				return err
+			}
+
+			return verify(state, roots, config)
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			_, cert, err := f.current()
+			return cert, err
+		},
+	}, nil
+}
+
+// verify verifies the certificate chain of the server with the roots, the system ones when nil,
+// and its name unless the config skips it.
+func verify(state tls.ConnectionState, roots *x509.CertPool, config Config) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("no server certificate")
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if !config.SkipServerNameVerification {
+		opts.DNSName = config.ServerName
+	}
+	for _, cert := range state.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if _, err := state.PeerCertificates[0].Verify(opts); err != nil {
+		return fmt.Errorf("failed to verify the database certificate: %w", err)
+	}
+
+	return nil
+}
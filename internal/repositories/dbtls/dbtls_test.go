@@ -0,0 +1,347 @@
+package dbtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+var serial atomic.Int64
+
+// newTestCert issues a certificate signed by the CA, self-signed when ca is nil.
+func newTestCert(t *testing.T, ca *testCert, name string, usage x509.ExtKeyUsage) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial.Add(1)),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	parent, signer := template, key
+	if ca == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+		template.DNSNames = []string{name}
+		parent, signer = ca.cert, ca.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func (c *testCert) pair(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	pair, err := tls.X509KeyPair(c.certPEM, c.keyPEM)
+	if err != nil {
+		t.Fatalf("failed to load key pair: %v", err)
+	}
+
+	return pair
+}
+
+var modTime atomic.Int64
+
+// writeFile writes a file with a later modification time than the previous writes, as a rotation would.
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+	at := time.Now().Add(time.Duration(modTime.Add(1)) * time.Second)
+	if err := os.Chtimes(path, at, at); err != nil {
+		t.Fatalf("failed to touch %s: %v", path, err)
+	}
+}
+
+// testServer is a TLS server requiring a client certificate, its certificates are replaced by set.
+type testServer struct {
+	addr   string
+	config atomic.Pointer[tls.Config]
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	s := &testServer{addr: listener.Addr().String()}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = tls.Server(conn, s.config.Load()).Handshake()
+			}()
+		}
+	}()
+
+	return s
+}
+
+func (s *testServer) set(t *testing.T, ca, cert *testCert) {
+	t.Helper()
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	s.config.Store(&tls.Config{
+		Certificates: []tls.Certificate{cert.pair(t)},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	})
+}
+
+func (s *testServer) dial(config *tls.Config) error {
+	conn, err := tls.Dial("tcp", s.addr, config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// the server verifies the client certificate after the client handshake, its alert comes with the first read
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err != nil && !errors.Is(err, os.ErrDeadlineExceeded) && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	return nil
+}
+
+type testFiles struct {
+	config Config
+}
+
+func newTestFiles(t *testing.T) *testFiles {
+	t.Helper()
+
+	dir := t.TempDir()
+	return &testFiles{config: Config{
+		Enabled:  true,
+		CAFile:   filepath.Join(dir, "ca.pem"),
+		CertFile: filepath.Join(dir, "client.pem"),
+		KeyFile:  filepath.Join(dir, "client.key"),
+	}}
+}
+
+func (f *testFiles) write(t *testing.T, ca, client *testCert) {
+	t.Helper()
+
+	writeFile(t, f.config.CAFile, ca.certPEM)
+	writeFile(t, f.config.CertFile, client.certPEM)
+	writeFile(t, f.config.KeyFile, client.keyPEM)
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCert(t, nil, "ca", 0)
+	otherCA := newTestCert(t, nil, "other-ca", 0)
+	server := newTestCert(t, ca, "db.internal", x509.ExtKeyUsageServerAuth)
+	client := newTestCert(t, ca, "app", x509.ExtKeyUsageClientAuth)
+	otherClient := newTestCert(t, otherCA, "app", x509.ExtKeyUsageClientAuth)
+
+	s := newTestServer(t)
+	s.set(t, ca, server)
+
+	tests := []struct {
+		name    string
+		ca      *testCert
+		client  *testCert
+		config  func(Config) Config
+		host    string
+		wantErr bool
+	}{
+		{
+			name:   "verified server and client",
+			ca:     ca,
+			client: client,
+			host:   "db.internal",
+		},
+		{
+			name:   "server name of the config",
+			ca:     ca,
+			client: client,
+			config: func(c Config) Config {
+				c.ServerName = "db.internal"
+				return c
+			},
+			host: "127.0.0.1",
+		},
+		{
+			name:    "other server name",
+			ca:      ca,
+			client:  client,
+			host:    "127.0.0.1",
+			wantErr: true,
+		},
+		{
+			name:   "server name verification skipped",
+			ca:     ca,
+			client: client,
+			config: func(c Config) Config {
+				c.SkipServerNameVerification = true
+				return c
+			},
+			host: "127.0.0.1",
+		},
+		{
+			name:    "server of another CA",
+			ca:      otherCA,
+			client:  client,
+			host:    "db.internal",
+			wantErr: true,
+		},
+		{
+			name:    "client of another CA",
+			ca:      ca,
+			client:  otherClient,
+			host:    "db.internal",
+			wantErr: true,
+		},
+		{
+			name:   "no client certificate",
+			ca:     ca,
+			client: client,
+			config: func(c Config) Config {
+				c.CertFile, c.KeyFile = "", ""
+				return c
+			},
+			host:    "db.internal",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			files := newTestFiles(t)
+			files.write(t, tt.ca, tt.client)
+			config := files.config
+			if tt.config != nil {
+				config = tt.config(config)
+			}
+
+			tlsConfig, err := Load(config, tt.host)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if err := s.dial(tlsConfig); (err != nil) != tt.wantErr {
+				t.Errorf("dial() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	files := newTestFiles(t)
+	if _, err := Load(files.config, "db.internal"); !errors.Is(err, entities.ErrInvalid) {
+		t.Errorf("Load() without files error = %v, want %v", err, entities.ErrInvalid)
+	}
+
+	config := files.config
+	config.KeyFile = ""
+	if _, err := Load(config, "db.internal"); !errors.Is(err, entities.ErrInvalid) {
+		t.Errorf("Load() without key file error = %v, want %v", err, entities.ErrInvalid)
+	}
+
+	writeFile(t, files.config.CAFile, []byte("not a certificate"))
+	config.CertFile = ""
+	if _, err := Load(config, "db.internal"); !errors.Is(err, entities.ErrInvalid) {
+		t.Errorf("Load() with an invalid CA error = %v, want %v", err, entities.ErrInvalid)
+	}
+}
+
+func TestLoad_Reload(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCert(t, nil, "ca", 0)
+	s := newTestServer(t)
+	s.set(t, ca, newTestCert(t, ca, "db.internal", x509.ExtKeyUsageServerAuth))
+	files := newTestFiles(t)
+	files.write(t, ca, newTestCert(t, ca, "app", x509.ExtKeyUsageClientAuth))
+
+	tlsConfig, err := Load(files.config, "db.internal")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := s.dial(tlsConfig); err != nil {
+		t.Fatalf("dial() error = %v", err)
+	}
+
+	// the server moves to certificates of another CA
+	rotated := newTestCert(t, nil, "rotated-ca", 0)
+	s.set(t, rotated, newTestCert(t, rotated, "db.internal", x509.ExtKeyUsageServerAuth))
+	if err := s.dial(tlsConfig); err == nil {
+		t.Fatalf("dial() with the previous CA succeeded, want an error")
+	}
+
+	files.write(t, rotated, newTestCert(t, rotated, "app", x509.ExtKeyUsageClientAuth))
+	if err := s.dial(tlsConfig); err != nil {
+		t.Errorf("dial() after the rotation error = %v", err)
+	}
+
+	// a file being replaced keeps the loaded certificates
+	writeFile(t, files.config.CAFile, nil)
+	if err := s.dial(tlsConfig); err != nil {
+		t.Errorf("dial() during a rotation error = %v", err)
+	}
+}
@@ -0,0 +1,257 @@
+// Package encryption encrypts the tagged model fields at rest, with the envelope encryption of a Keyring.
+//
+// A field tagged `gorm:"serializer:encrypted"` is stored encrypted, the additional data of its values is
+// "<table>.<column>". A field tagged `blindindex:"<column>"` is set to the blind index of the column
+// whenever a row is created or updated, to look the rows up by the value of an encrypted column.
+// The fields are strings, string pointers or sql.NullString.
+package encryption
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/tuantran1810/go-di-template/libs/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var log = logger.MustNamedLogger("encryption")
+
+const (
+	pluginName = "encryption"
+
+	// SerializerName is the GORM serializer of the encrypted fields
+	SerializerName = "encrypted"
+	// tagName is the struct tag of the blind index fields, naming the column they index
+	tagName = "blindindex"
+)
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+type keyringContextKey struct{}
+
+// keyringFromContext returns the keyring of the statement, nil when the encryption is disabled.
+func keyringFromContext(ctx context.Context) *Keyring {
+	keyring, _ := ctx.Value(keyringContextKey{}).(*Keyring)
+	return keyring
+}
+
+// stringOf returns the value of a string, *string or sql.NullString field, valid is false for NULL.
+func stringOf(value reflect.Value) (s string, valid bool, err error) {
+	switch v := reflect.Indirect(value); {
+	case value.Kind() == reflect.Pointer && value.IsNil():
+		return "", false, nil
+	case v.Kind() == reflect.String:
+		return v.String(), true, nil
+	case v.Type() == reflect.TypeOf(sql.NullString{}):
+		ns := v.Interface().(sql.NullString)
+		return ns.String, ns.Valid, nil
+	default:
+		return "", false, fmt.Errorf("unsupported type %s", value.Type())
+	}
+}
+
+// setString sets a string, *string or sql.NullString field.
+func setString(field reflect.Value, s string, valid bool) error {
+	switch {
+	case field.Kind() == reflect.String:
+		field.SetString(s)
+	case field.Kind() == reflect.Pointer && field.Type().Elem().Kind() == reflect.String:
+		if !valid {
+			field.SetZero()
+			return nil
+		}
+		ptr := reflect.New(field.Type().Elem())
+		ptr.Elem().SetString(s)
+		field.Set(ptr)
+	case field.Type() == reflect.TypeOf(sql.NullString{}):
+		field.Set(reflect.ValueOf(sql.NullString{String: s, Valid: valid}))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+
+	return nil
+}
+
+func additionalData(field *schema.Field) []byte {
+	return []byte(field.Schema.Table + "." + field.DBName)
+}
+
+// Serializer encrypts and decrypts the values of a field with the keyring of the statement.
+// Without keyring the values are stored as they are. The values stored before the encryption are read as they are.
+type Serializer struct{}
+
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue any) error {
+	var (
+		value string
+		valid = dbValue != nil
+	)
+	switch v := dbValue.(type) {
+	case nil:
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	default:
+		return fmt.Errorf("failed to decrypt %s: unsupported value %T", field.Name, dbValue)
+	}
+
+	if valid && IsEncrypted(value) {
+		keyring := keyringFromContext(ctx)
+		if keyring == nil {
+			return fmt.Errorf("failed to decrypt %s: no keyring", field.Name)
+		}
+		plaintext, err := keyring.Decrypt(value, additionalData(field))
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", field.Name, err)
+		}
+		value = string(plaintext)
+	}
+
+	return setString(field.ReflectValueOf(ctx, dst), value, valid)
+}
+
+func (Serializer) Value(ctx context.Context, field *schema.Field, _ reflect.Value, fieldValue any) (any, error) {
+	value, valid, err := stringOf(reflect.ValueOf(fieldValue))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt %s: %w", field.Name, err)
+	}
+	if !valid {
+		return nil, nil
+	}
+
+	keyring := keyringFromContext(ctx)
+	if keyring == nil {
+		return value, nil
+	}
+
+	return keyring.Encrypt([]byte(value), additionalData(field))
+}
+
+// Plugin is a GORM plugin giving its keyring to the encrypted fields of every statement and setting the blind indexes.
+// A nil keyring disables the encryption.
+type Plugin struct {
+	keyring *Keyring
+}
+
+func NewPlugin(keyring *Keyring) *Plugin {
+	return &Plugin{keyring: keyring}
+}
+
+func (p *Plugin) Name() string {
+	return pluginName
+}
+
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	registers := []func() error{
+		func() error {
+			return callback.Create().Before("*").Register("encryption:keyring", p.withKeyring)
+		},
+		func() error {
+			return callback.Create().Before("gorm:create").Register("encryption:blind_index", p.setBlindIndexes)
+		},
+		func() error {
+			return callback.Query().Before("*").Register("encryption:keyring", p.withKeyring)
+		},
+		func() error {
+			return callback.Update().Before("*").Register("encryption:keyring", p.withKeyring)
+		},
+		func() error {
+			return callback.Update().Before("gorm:update").Register("encryption:blind_index", p.setBlindIndexes)
+		},
+		func() error {
+			return callback.Delete().Before("*").Register("encryption:keyring", p.withKeyring)
+		},
+		func() error {
+			return callback.Row().Before("*").Register("encryption:keyring", p.withKeyring)
+		},
+		func() error {
+			return callback.Raw().Before("*").Register("encryption:keyring", p.withKeyring)
+		},
+	}
+	for _, register := range registers {
+		if err := register(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Plugin) withKeyring(db *gorm.DB) {
+	if p.keyring == nil {
+		return
+	}
+
+	db.Statement.Context = context.WithValue(db.Statement.Context, keyringContextKey{}, p.keyring)
+}
+
+// setBlindIndexes sets the blind index fields of the created or updated rows from the values they index.
+func (p *Plugin) setBlindIndexes(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || p.keyring == nil {
+		return
+	}
+
+	ctx := db.Statement.Context
+	set := func(row reflect.Value) {
+		if !row.CanAddr() || row.Kind() != reflect.Struct || row.Type() != db.Statement.Schema.ModelType {
+			return
+		}
+		for _, field := range db.Statement.Schema.Fields {
+			column, ok := field.Tag.Lookup(tagName)
+			if !ok {
+				continue
+			}
+			source := db.Statement.Schema.LookUpField(column)
+			if source == nil {
+				_ = db.AddError(fmt.Errorf("unknown column %s indexed by %s", column, field.Name))
+				return
+			}
+
+			value, valid, err := stringOf(source.ReflectValueOf(ctx, row))
+			if err == nil && valid {
+				err = setString(field.ReflectValueOf(ctx, row), p.keyring.BlindIndex(value), true)
+			}
+			if err != nil {
+				_ = db.AddError(fmt.Errorf("failed to set blind index %s: %w", field.Name, err))
+				return
+			}
+		}
+	}
+
+	value := reflect.Indirect(db.Statement.ReflectValue)
+	switch value.Kind() {
+	case reflect.Struct:
+		set(value)
+	case reflect.Slice, reflect.Array:
+		for i := range value.Len() {
+			set(reflect.Indirect(value.Index(i)))
+		}
+	}
+}
+
+// keyringOf returns the keyring of the plugin installed on db, nil if there is none.
+func keyringOf(db *gorm.DB) *Keyring {
+	plugin, ok := db.Config.Plugins[pluginName].(*Plugin)
+	if !ok {
+		return nil
+	}
+
+	return plugin.keyring
+}
+
+// BlindIndex returns the blind index of value with the keyring installed on db,
+// ok is false when the encryption is disabled and the values are stored as they are.
+func BlindIndex(db *gorm.DB, value string) (index string, ok bool) {
+	keyring := keyringOf(db)
+	if keyring == nil {
+		return "", false
+	}
+
+	return keyring.BlindIndex(value), true
+}
@@ -0,0 +1,211 @@
+package encryption
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/tenancy"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type Person struct {
+	gorm.Model
+	TenantID   uint
+	Name       string         `gorm:"serializer:encrypted"`
+	Nickname   *string        `gorm:"serializer:encrypted"`
+	Email      sql.NullString `gorm:"serializer:encrypted"`
+	EmailIndex sql.NullString `blindindex:"email"`
+}
+
+type storedPerson struct {
+	ID         uint
+	Name       string
+	Nickname   sql.NullString
+	Email      sql.NullString
+	EmailIndex sql.NullString
+}
+
+func newTestDB(t *testing.T, path string, keyring *Keyring) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(
+		sqlite.Open(path),
+		&gorm.Config{Logger: gormlogger.Discard},
+	)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.Use(NewPlugin(keyring)); err != nil {
+		t.Fatalf("failed to install encryption plugin: %v", err)
+	}
+	if err := db.Use(tenancy.NewPlugin()); err != nil {
+		t.Fatalf("failed to install tenancy plugin: %v", err)
+	}
+	if err := db.AutoMigrate(&Person{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	return db
+}
+
+// stored returns the row as it is stored in the database.
+func stored(t *testing.T, db *gorm.DB, id uint) storedPerson {
+	t.Helper()
+
+	var row storedPerson
+	if err := db.Table("people").Where("id = ?", id).Take(&row).Error; err != nil {
+		t.Fatalf("failed to read row: %v", err)
+	}
+
+	return row
+}
+
+func TestPlugin(t *testing.T) {
+	t.Parallel()
+
+	keyring := newTestKeyring(t, filepath.Join(t.TempDir(), "keys.json"))
+	db := newTestDB(t, filepath.Join(t.TempDir(), "encryption.db"), keyring)
+	ctx := context.Background()
+
+	nickname := "nick"
+	person := Person{Name: "Alice", Nickname: &nickname, Email: sql.NullString{String: "Alice@Example.com", Valid: true}}
+	if err := db.WithContext(ctx).Create(&person).Error; err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+
+	row := stored(t, db, person.ID)
+	for _, value := range []string{row.Name, row.Nickname.String, row.Email.String} {
+		if !IsEncrypted(value) {
+			t.Errorf("stored value %q is not encrypted", value)
+		}
+	}
+	if row.EmailIndex.String != keyring.BlindIndex("alice@example.com") {
+		t.Errorf("stored blind index = %v, want %v", row.EmailIndex.String, keyring.BlindIndex("alice@example.com"))
+	}
+
+	var found Person
+	index, ok := BlindIndex(db, " alice@example.com")
+	if !ok {
+		t.Fatalf("BlindIndex() ok = false")
+	}
+	if err := db.WithContext(ctx).Where("email_index = ?", index).Take(&found).Error; err != nil {
+		t.Fatalf("failed to find by blind index: %v", err)
+	}
+	if found.Name != "Alice" || *found.Nickname != "nick" || found.Email.String != "Alice@Example.com" {
+		t.Errorf("found = %+v", found)
+	}
+
+	// updates set the blind index of the new email
+	if err := db.WithContext(ctx).Updates(&Person{Model: gorm.Model{ID: person.ID}, Email: sql.NullString{String: "bob@example.com", Valid: true}}).Error; err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if row := stored(t, db, person.ID); row.EmailIndex.String != keyring.BlindIndex("bob@example.com") {
+		t.Errorf("stored blind index = %v, want %v", row.EmailIndex.String, keyring.BlindIndex("bob@example.com"))
+	}
+
+	// NULL stays NULL
+	empty := Person{Name: "Carol"}
+	if err := db.WithContext(ctx).Create(&empty).Error; err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+	if row := stored(t, db, empty.ID); row.Email.Valid || row.Nickname.Valid || row.EmailIndex.Valid {
+		t.Errorf("stored = %+v, want NULL email, nickname and blind index", row)
+	}
+}
+
+func TestPlugin_WithoutKeyring(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t, filepath.Join(t.TempDir(), "encryption.db"), nil)
+	person := Person{Name: "Alice", Email: sql.NullString{String: "alice@example.com", Valid: true}}
+	if err := db.Create(&person).Error; err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+
+	if row := stored(t, db, person.ID); row.Name != "Alice" || row.Email.String != "alice@example.com" || row.EmailIndex.Valid {
+		t.Errorf("stored = %+v, want plaintext without blind index", row)
+	}
+	if _, ok := BlindIndex(db, "alice@example.com"); ok {
+		t.Errorf("BlindIndex() ok = true without keyring")
+	}
+
+	// the encrypted values cannot be read without keyring
+	if err := db.Exec("UPDATE people SET name = ? WHERE id = ?", "enc:v1:key:AAAA:AAAA", person.ID).Error; err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	var found Person
+	if err := db.Take(&found, person.ID).Error; err == nil || !strings.Contains(err.Error(), "no keyring") {
+		t.Errorf("Take() error = %v, want no keyring", err)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keys.json")
+	previous := newTestKeyring(t, path)
+	db := newTestDB(t, filepath.Join(dir, "encryption.db"), previous)
+	ctx := context.Background()
+	tenant2 := entities.InjectTenantIDToContext(ctx, 2)
+
+	people := []struct {
+		ctx    context.Context
+		person Person
+	}{
+		{ctx, Person{Name: "Alice", Email: sql.NullString{String: "alice@example.com", Valid: true}}},
+		{tenant2, Person{Name: "Bob"}},
+		{ctx, Person{Name: "Carol"}},
+	}
+	for i := range people {
+		if err := db.WithContext(people[i].ctx).Create(&people[i].person).Error; err != nil {
+			t.Fatalf("failed to create: %v", err)
+		}
+	}
+	if err := db.Delete(&people[2].person).Error; err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	// a row stored before the encryption
+	if err := db.Exec(
+		"INSERT INTO people (tenant_id, name, email) VALUES (1, 'Dave', 'dave@example.com')",
+	).Error; err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	keyring := newTestKeyring(t, path)
+	rotatedDB := newTestDB(t, filepath.Join(dir, "encryption.db"), keyring)
+
+	rotated, err := Rotate(ctx, rotatedDB, 2, &Person{})
+	if err != nil || rotated != 4 {
+		t.Fatalf("Rotate() = %v, %v, want 4", rotated, err)
+	}
+	if rotated, err := Rotate(ctx, rotatedDB, 2, &Person{}); err != nil || rotated != 0 {
+		t.Errorf("Rotate() again = %v, %v, want 0", rotated, err)
+	}
+
+	var rows []storedPerson
+	if err := rotatedDB.Table("people").Order("id").Find(&rows).Error; err != nil {
+		t.Fatalf("failed to read rows: %v", err)
+	}
+	for _, row := range rows {
+		for _, value := range []string{row.Name, row.Email.String} {
+			if id, _ := KeyID(value); value != "" && id != keyring.ActiveKeyID() {
+				t.Errorf("row %d value %q is not encrypted with %v", row.ID, value, keyring.ActiveKeyID())
+			}
+		}
+	}
+	if rows[3].EmailIndex.String != keyring.BlindIndex("dave@example.com") {
+		t.Errorf("blind index of the legacy row = %v, want %v", rows[3].EmailIndex.String, keyring.BlindIndex("dave@example.com"))
+	}
+
+	var found Person
+	if err := rotatedDB.WithContext(tenant2).Take(&found, people[1].person.ID).Error; err != nil || found.Name != "Bob" {
+		t.Errorf("Take() = %+v, %v, want Bob", found, err)
+	}
+}
@@ -0,0 +1,392 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	keySize = 32
+
+	// prefix starts the stored values, followed by the id of the master key wrapping the data key:
+	// enc:v1:<key id>:<wrapped data key>:<ciphertext>
+	prefix = "enc:v1:"
+)
+
+var (
+	ErrUnknownKey = errors.New("unknown master key")
+	ErrMalformed  = errors.New("malformed ciphertext")
+)
+
+// Keyfile is the JSON content of the local keyfile. The keys are base64 encoded 256 bits keys.
+// The master keys are never removed, to decrypt the values not rotated yet, and the blind index key never changes,
+// since the blind indexes could not be looked up anymore.
+type Keyfile struct {
+	ActiveKeyID   string            `json:"active_key_id"`
+	MasterKeys    map[string]string `json:"master_keys"`
+	BlindIndexKey string            `json:"blind_index_key"`
+}
+
+// ReloadInterval is how often a keyring loaded from a keyfile checks whether the file changed.
+const ReloadInterval = time.Second
+
+// keySet holds the keys of a version of the keyfile.
+type keySet struct {
+	activeKeyID   string
+	masterKeys    map[string]cipher.AEAD
+	blindIndexKey []byte
+}
+
+// Keyring encrypts values with AES-GCM data keys, one per value, wrapped by the active master key.
+// A keyring loaded from a keyfile loads it again once changed, so that the servers running while the keys
+// are rotated encrypt with the new active key, and decrypt the values already rotated, without restarting.
+type Keyring struct {
+	// path is the keyfile, empty for a keyring which is not reloaded
+	path           string
+	reloadInterval time.Duration
+
+	mu        sync.Mutex
+	stamp     stamp
+	checkedAt time.Time
+	keys      *keySet
+}
+
+// stamp identifies the version of a file, a file written again has another one.
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func stampOf(path string) (stamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return stamp{}, err
+	}
+
+	return stamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+func decodeKey(name, encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("%s has %d bytes, want %d", name, len(key), keySize)
+	}
+
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func newKeySet(keyfile Keyfile) (*keySet, error) {
+	if _, ok := keyfile.MasterKeys[keyfile.ActiveKeyID]; !ok {
+		return nil, fmt.Errorf("%w - active key %q", ErrUnknownKey, keyfile.ActiveKeyID)
+	}
+
+	masterKeys := make(map[string]cipher.AEAD, len(keyfile.MasterKeys))
+	for id, encoded := range keyfile.MasterKeys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid master key id %q", id)
+		}
+		key, err := decodeKey("master key "+id, encoded)
+		if err != nil {
+			return nil, err
+		}
+		if masterKeys[id], err = newAEAD(key); err != nil {
+			return nil, err
+		}
+	}
+
+	blindIndexKey, err := decodeKey("blind index key", keyfile.BlindIndexKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keySet{
+		activeKeyID:   keyfile.ActiveKeyID,
+		masterKeys:    masterKeys,
+		blindIndexKey: blindIndexKey,
+	}, nil
+}
+
+// NewKeyring returns the keyring of the keys of keyfile, which never changes.
+func NewKeyring(keyfile Keyfile) (*Keyring, error) {
+	k, err := newKeySet(keyfile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Keyring{
+		keys: k,
+	}, nil
+}
+
+func readKeyfile(path string) (Keyfile, error) {
+	var keyfile Keyfile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return keyfile, fmt.Errorf("failed to read keyfile: %w", err)
+	}
+	if err := json.Unmarshal(data, &keyfile); err != nil {
+		return keyfile, fmt.Errorf("failed to decode keyfile: %w", err)
+	}
+
+	return keyfile, nil
+}
+
+func loadKeySet(path string) (stamp, *keySet, error) {
+	s, err := stampOf(path)
+	if err != nil {
+		return stamp{}, nil, fmt.Errorf("failed to stat keyfile: %w", err)
+	}
+	keyfile, err := readKeyfile(path)
+	if err != nil {
+		return stamp{}, nil, err
+	}
+	k, err := newKeySet(keyfile)
+	if err != nil {
+		return stamp{}, nil, err
+	}
+
+	return s, k, nil
+}
+
+// LoadKeyring loads the keyring from the keyfile at path. The keyfile is checked every ReloadInterval,
+// or when a value of an unknown master key is decrypted, and loaded again once changed.
+func LoadKeyring(path string) (*Keyring, error) {
+	s, k, err := loadKeySet(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Keyring{
+		path:           path,
+		reloadInterval: ReloadInterval,
+		stamp:          s,
+		checkedAt:      time.Now(),
+		keys:           k,
+	}, nil
+}
+
+// current returns the keys, loaded again when the keyfile changed since it was last checked,
+// at most every reloadInterval unless force. A failed reload keeps the previous keys,
+// e.g. while the keyfile is being replaced.
+func (k *Keyring) current(force bool) *keySet {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.path == "" || !force && time.Since(k.checkedAt) < k.reloadInterval {
+		return k.keys
+	}
+	k.checkedAt = time.Now()
+
+	if s, err := stampOf(k.path); err == nil && s == k.stamp {
+		return k.keys
+	}
+	s, reloaded, err := loadKeySet(k.path)
+	if err != nil {
+		log.Warnw("failed to reload the encryption keyfile, keeping the previous keys", "error", err)
+		return k.keys
+	}
+	if !hmac.Equal(reloaded.blindIndexKey, k.keys.blindIndexKey) {
+		log.Errorw("the blind index key of the encryption keyfile changed, keeping the previous keys")
+		return k.keys
+	}
+	log.Infow("reloaded the encryption keyfile", "active_key_id", reloaded.activeKeyID)
+	k.stamp, k.keys = s, reloaded
+
+	return k.keys
+}
+
+func randomBytes(n int) []byte {
+	out := make([]byte, n)
+	// crypto/rand.Read never fails
+	_, _ = rand.Read(out)
+	return out
+}
+
+// AddMasterKey generates a master key and makes it the active one in the keyfile at path,
+// which is created with a blind index key if it does not exist. It returns the id of the new key.
+func AddMasterKey(path string) (string, error) {
+	keyfile, err := readKeyfile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		keyfile = Keyfile{
+			MasterKeys:    make(map[string]string),
+			BlindIndexKey: base64.StdEncoding.EncodeToString(randomBytes(keySize)),
+		}
+	case err != nil:
+		return "", err
+	}
+
+	id := hex.EncodeToString(randomBytes(4))
+	for _, ok := keyfile.MasterKeys[id]; ok; _, ok = keyfile.MasterKeys[id] {
+		id = hex.EncodeToString(randomBytes(4))
+	}
+	keyfile.MasterKeys[id] = base64.StdEncoding.EncodeToString(randomBytes(keySize))
+	keyfile.ActiveKeyID = id
+
+	if _, err := newKeySet(keyfile); err != nil {
+		return "", err
+	}
+	if err := writeKeyfile(path, keyfile); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// writeKeyfile replaces the keyfile atomically, readable by its owner only.
+func writeKeyfile(path string, keyfile Keyfile) error {
+	data, err := json.MarshalIndent(keyfile, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode keyfile: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create keyfile: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to create keyfile: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write keyfile: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write keyfile: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace keyfile: %w", err)
+	}
+
+	return nil
+}
+
+func (k *Keyring) ActiveKeyID() string {
+	return k.current(false).activeKeyID
+}
+
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) []byte {
+	nonce := randomBytes(aead.NonceSize())
+	return aead.Seal(nonce, nonce, plaintext, additionalData)
+}
+
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+// Encrypt encrypts plaintext with a new data key wrapped by the active master key.
+// The additional data, e.g. the table and the column of the value, is authenticated:
+// the value cannot be decrypted with other additional data.
+func (k *Keyring) Encrypt(plaintext, additionalData []byte) (string, error) {
+	dataKey := randomBytes(keySize)
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	keys := k.current(false)
+	wrapped := seal(keys.masterKeys[keys.activeKeyID], dataKey, []byte(keys.activeKeyID))
+	ciphertext := seal(aead, plaintext, additionalData)
+
+	return prefix + keys.activeKeyID + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// IsEncrypted tells whether a stored value was encrypted by a keyring, values stored before the encryption are not.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// KeyID returns the id of the master key which encrypted the value.
+func KeyID(value string) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if !IsEncrypted(value) || len(parts) != 3 {
+		return "", ErrMalformed
+	}
+
+	return parts[0], nil
+}
+
+func (k *Keyring) Decrypt(value string, additionalData []byte) ([]byte, error) {
+	id, err := KeyID(value)
+	if err != nil {
+		return nil, err
+	}
+	masterKey, ok := k.current(false).masterKeys[id]
+	if !ok {
+		// the key may have been added by a rotation since the keyfile was last checked
+		if masterKey, ok = k.current(true).masterKeys[id]; !ok {
+			return nil, fmt.Errorf("%w - %q", ErrUnknownKey, id)
+		}
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	dataKey, err := open(masterKey, wrapped, []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := open(aead, ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// BlindIndex returns a deterministic keyed hash of the value, case and surrounding spaces ignored,
+// to look the encrypted values up by equality.
+func (k *Keyring) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, k.current(false).blindIndexKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
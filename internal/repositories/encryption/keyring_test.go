@@ -0,0 +1,152 @@
+package encryption
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestKeyring(t *testing.T, path string) *Keyring {
+	t.Helper()
+
+	if _, err := AddMasterKey(path); err != nil {
+		t.Fatalf("failed to add master key: %v", err)
+	}
+	keyring, err := LoadKeyring(path)
+	if err != nil {
+		t.Fatalf("failed to load keyring: %v", err)
+	}
+
+	return keyring
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	keyring := newTestKeyring(t, filepath.Join(t.TempDir(), "keys.json"))
+	value, err := keyring.Encrypt([]byte("user@example.com"), []byte("users.email"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !IsEncrypted(value) {
+		t.Errorf("IsEncrypted(%q) = false", value)
+	}
+	if id, err := KeyID(value); err != nil || id != keyring.ActiveKeyID() {
+		t.Errorf("KeyID() = %v, %v, want %v", id, err, keyring.ActiveKeyID())
+	}
+	if other, _ := keyring.Encrypt([]byte("user@example.com"), []byte("users.email")); other == value {
+		t.Errorf("Encrypt() is deterministic")
+	}
+
+	plaintext, err := keyring.Decrypt(value, []byte("users.email"))
+	if err != nil || string(plaintext) != "user@example.com" {
+		t.Errorf("Decrypt() = %q, %v", plaintext, err)
+	}
+	if _, err := keyring.Decrypt(value, []byte("users.name")); err == nil {
+		t.Errorf("Decrypt() with other additional data succeeded")
+	}
+	if _, err := keyring.Decrypt("enc:v1:unknown:AAAA:AAAA", nil); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Decrypt() error = %v, want %v", err, ErrUnknownKey)
+	}
+	if _, err := keyring.Decrypt("user@example.com", nil); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Decrypt() error = %v, want %v", err, ErrMalformed)
+	}
+}
+
+func TestAddMasterKey(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+	before := newTestKeyring(t, path)
+	// compares the keys before and after the addition, which before would load once checked again
+	before.reloadInterval = time.Hour
+	value, err := before.Encrypt([]byte("name"), nil)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	after := newTestKeyring(t, path)
+	if after.ActiveKeyID() == before.ActiveKeyID() {
+		t.Errorf("AddMasterKey() kept the active key %v", before.ActiveKeyID())
+	}
+	if plaintext, err := after.Decrypt(value, nil); err != nil || string(plaintext) != "name" {
+		t.Errorf("Decrypt() with the previous key = %q, %v", plaintext, err)
+	}
+	if after.BlindIndex("name") != before.BlindIndex("name") {
+		t.Errorf("AddMasterKey() changed the blind index key")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("keyfile mode = %v, %v, want 0600", info.Mode().Perm(), err)
+	}
+}
+
+func TestKeyring_Reload(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+	keyring := newTestKeyring(t, path)
+	keyring.reloadInterval = time.Hour
+	index := keyring.BlindIndex("name")
+
+	// a rotation adds a key and re-encrypts with it while the keyring is used
+	rotated := newTestKeyring(t, path)
+	value, err := rotated.Encrypt([]byte("name"), nil)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if plaintext, err := keyring.Decrypt(value, nil); err != nil || string(plaintext) != "name" {
+		t.Errorf("Decrypt() with the added key = %q, %v", plaintext, err)
+	}
+	if keyring.ActiveKeyID() != rotated.ActiveKeyID() {
+		t.Errorf("ActiveKeyID() = %v, want the added key %v", keyring.ActiveKeyID(), rotated.ActiveKeyID())
+	}
+
+	keyring.reloadInterval = 0
+	id, err := AddMasterKey(path)
+	if err != nil {
+		t.Fatalf("failed to add master key: %v", err)
+	}
+	if keyring.ActiveKeyID() != id {
+		t.Errorf("ActiveKeyID() = %v, want the added key %v", keyring.ActiveKeyID(), id)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatalf("failed to write keyfile: %v", err)
+	}
+	if keyring.ActiveKeyID() != id {
+		t.Errorf("ActiveKeyID() after a failed reload = %v, want %v", keyring.ActiveKeyID(), id)
+	}
+
+	// a keyfile with another blind index key is refused, the values could not be looked up anymore
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("failed to remove keyfile: %v", err)
+	}
+	if _, err := AddMasterKey(path); err != nil {
+		t.Fatalf("failed to add master key: %v", err)
+	}
+	if keyring.ActiveKeyID() != id || keyring.BlindIndex("name") != index {
+		t.Errorf("keyring reloaded a keyfile with another blind index key")
+	}
+}
+
+func TestKeyring_BlindIndex(t *testing.T) {
+	t.Parallel()
+
+	keyring := newTestKeyring(t, filepath.Join(t.TempDir(), "keys.json"))
+	other := newTestKeyring(t, filepath.Join(t.TempDir(), "keys.json"))
+
+	index := keyring.BlindIndex("user@example.com")
+	if got := keyring.BlindIndex(" User@Example.com "); got != index {
+		t.Errorf("BlindIndex() = %v, want %v", got, index)
+	}
+	if got := keyring.BlindIndex("other@example.com"); got == index {
+		t.Errorf("BlindIndex() of another value = %v", got)
+	}
+	if got := other.BlindIndex("user@example.com"); got == index {
+		t.Errorf("BlindIndex() with another key = %v", got)
+	}
+}
@@ -0,0 +1,115 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const DefaultBatchSize = 500
+
+// Rotate re-encrypts with the active master key the values of the models encrypted with the other keys,
+// or stored before the encryption, and sets their blind indexes. The rows of all the tenants are rotated,
+// including the soft deleted ones, by batches of batchSize rows, each one in a transaction.
+// It returns the number of rotated rows.
+func Rotate(ctx context.Context, db *gorm.DB, batchSize int, models ...any) (int, error) {
+	keyring := keyringOf(db)
+	if keyring == nil {
+		return 0, fmt.Errorf("%w - encryption plugin is not installed", entities.ErrInternal)
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	ctx = entities.WithAllTenants(ctx)
+	rotated := 0
+	for _, model := range models {
+		n, err := rotateModel(db.WithContext(ctx), keyring, batchSize, model)
+		rotated += n
+		if err != nil {
+			return rotated, err
+		}
+	}
+
+	return rotated, nil
+}
+
+func rotateModel(db *gorm.DB, keyring *Keyring, batchSize int, model any) (int, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return 0, fmt.Errorf("%w - failed to parse model: %w", entities.ErrInternal, err)
+	}
+	primary := stmt.Schema.PrioritizedPrimaryField
+	if primary == nil {
+		return 0, fmt.Errorf("%w - %s has no primary key", entities.ErrInternal, stmt.Schema.Name)
+	}
+
+	var (
+		columns    []string
+		conditions []clause.Expression
+		active     = prefix + keyring.ActiveKeyID() + ":%"
+	)
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		if _, ok := field.Tag.Lookup(tagName); ok {
+			columns = append(columns, field.DBName)
+		}
+		if field.TagSettings["SERIALIZER"] != SerializerName {
+			continue
+		}
+		columns = append(columns, field.DBName)
+		column := clause.Column{Table: clause.CurrentTable, Name: field.DBName}
+		conditions = append(conditions, clause.And(
+			clause.Expr{SQL: "? IS NOT NULL", Vars: []any{column}},
+			clause.Not(clause.Like{Column: column, Value: active}),
+		))
+	}
+	if len(conditions) == 0 {
+		return 0, nil
+	}
+
+	var (
+		rotated = 0
+		last    any
+	)
+	for {
+		query := db.Unscoped().Model(model).Where(clause.Or(conditions...)).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: primary.DBName}}).
+			Limit(batchSize)
+		if last != nil {
+			query = query.Where(clause.Gt{Column: clause.Column{Table: clause.CurrentTable, Name: primary.DBName}, Value: last})
+		}
+
+		rows := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+		if err := query.Find(rows.Interface()).Error; err != nil {
+			return rotated, fmt.Errorf("%w - failed to load %s rows: %w", entities.ErrDatabase, stmt.Schema.Table, err)
+		}
+		batch := rows.Elem()
+		if batch.Len() == 0 {
+			return rotated, nil
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for i := range batch.Len() {
+				row := batch.Index(i).Addr().Interface()
+				if err := tx.Unscoped().Model(row).Select(columns).UpdateColumns(row).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return rotated, fmt.Errorf("%w - failed to rotate %s rows: %w", entities.ErrDatabase, stmt.Schema.Table, err)
+		}
+
+		rotated += batch.Len()
+		last = primary.ReflectValueOf(db.Statement.Context, batch.Index(batch.Len()-1)).Interface()
+		log.Infof("rotated %d %s rows", rotated, stmt.Schema.Table)
+	}
+}
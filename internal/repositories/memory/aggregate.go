@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+// WithAggregateColumns allows Aggregate to group by and aggregate the columns, like the GORM generic repositories.
+func (s *GenericRepository[E]) WithAggregateColumns(columns ...string) *GenericRepository[E] {
+	s.aggregateColumns = append(s.aggregateColumns, columns...)
+	return s
+}
+
+func (s *GenericRepository[E]) checkAggregateQuery(query entities.AggregateQuery) error {
+	if len(query.Aggregates) == 0 {
+		return fmt.Errorf("%w - no aggregate", entities.ErrInvalid)
+	}
+
+	check := func(column string) error {
+		if !slices.Contains(s.aggregateColumns, column) {
+			return fmt.Errorf("%w - column %q cannot be aggregated", entities.ErrInvalid, column)
+		}
+		_, ok := s.columns[column]
+		if !ok {
+			return fmt.Errorf("%w - unknown column %s", entities.ErrInvalid, column)
+		}
+		return nil
+	}
+
+	for _, groupBy := range query.GroupBys {
+		if err := check(groupBy.Column); err != nil {
+			return err
+		}
+		if groupBy.Bucket != "" && !groupBy.Bucket.Valid() {
+			return fmt.Errorf("%w - unknown time bucket %q", entities.ErrInvalid, groupBy.Bucket)
+		}
+	}
+	for _, aggregate := range query.Aggregates {
+		switch aggregate.Func {
+		case entities.AggregateCount, entities.AggregateSum, entities.AggregateMin, entities.AggregateMax:
+		default:
+			return fmt.Errorf("%w - unknown aggregate %q", entities.ErrInvalid, aggregate.Func)
+		}
+		if aggregate.Column == "" && aggregate.Func == entities.AggregateCount {
+			continue
+		}
+		if err := check(aggregate.Column); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// groupValue returns the value of the column of a record to group it by, nil for NULL.
+func (s *GenericRepository[E]) groupValue(value reflect.Value, groupBy entities.GroupBy) any {
+	field := value.Field(s.columns[groupBy.Column])
+	if isNull(field) {
+		return nil
+	}
+
+	v := reflect.Indirect(field).Interface()
+	if t, ok := v.(time.Time); ok && groupBy.Bucket != "" {
+		return groupBy.Bucket.Truncate(t)
+	}
+
+	return v
+}
+
+// aggregateValue aggregates the column of the records, ignoring NULL like SQL does, no value gives 0.
+func (s *GenericRepository[E]) aggregateValue(values []reflect.Value, aggregate entities.Aggregate) float64 {
+	if aggregate.Column == "" {
+		return float64(len(values))
+	}
+
+	var (
+		out   float64
+		count int
+	)
+	for _, value := range values {
+		field := value.Field(s.columns[aggregate.Column])
+		if isNull(field) {
+			continue
+		}
+		f, ok := toFloat(reflect.Indirect(field))
+		switch {
+		case aggregate.Func == entities.AggregateCount:
+			out++
+		case !ok:
+			continue
+		case aggregate.Func == entities.AggregateSum:
+			out += f
+		case count == 0,
+			aggregate.Func == entities.AggregateMin && f < out,
+			aggregate.Func == entities.AggregateMax && f > out:
+			out = f
+		}
+		count++
+	}
+
+	return out
+}
+
+// compareGroups orders the groups of two rows, NULL comes first.
+func compareGroups(a, b []any) int {
+	for i := range a {
+		switch {
+		case a[i] == nil && b[i] == nil:
+			continue
+		case a[i] == nil:
+			return -1
+		case b[i] == nil:
+			return 1
+		}
+		if cmp, _ := compare(reflect.ValueOf(a[i]), b[i]); cmp != 0 {
+			return cmp
+		}
+	}
+
+	return 0
+}
+
+// Aggregate groups the live records matching the criterias of the query and aggregates each group,
+// with the semantics of the GORM generic repositories. The grouped times are truncated to their bucket in UTC.
+func (s *GenericRepository[E]) Aggregate(
+	ctx context.Context,
+	tx entities.Transaction,
+	query entities.AggregateQuery,
+) ([]entities.AggregateRow, error) {
+	if err := s.checkAggregateQuery(query); err != nil {
+		return nil, err
+	}
+
+	unlock := s.acquire(ctx, tx, false)
+	defer unlock()
+
+	values, err := s.selectLive(ctx, query.Criterias, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	type group struct {
+		keys   []any
+		values []reflect.Value
+	}
+	groups := make(map[string]*group)
+	for _, value := range values {
+		keys := make([]any, 0, len(query.GroupBys))
+		for _, groupBy := range query.GroupBys {
+			keys = append(keys, s.groupValue(value, groupBy))
+		}
+		id := fmt.Sprintf("%#v", keys)
+		if _, ok := groups[id]; !ok {
+			groups[id] = &group{keys: keys}
+		}
+		groups[id].values = append(groups[id].values, value)
+	}
+	// SQL aggregates all the records in a single row without GROUP BY, even when there are none
+	if len(query.GroupBys) == 0 && len(groups) == 0 {
+		groups[""] = &group{}
+	}
+
+	out := make([]entities.AggregateRow, 0, len(groups))
+	for _, g := range groups {
+		row := entities.AggregateRow{Groups: g.keys, Values: make([]float64, 0, len(query.Aggregates))}
+		if row.Groups == nil {
+			row.Groups = []any{}
+		}
+		for _, aggregate := range query.Aggregates {
+			row.Values = append(row.Values, s.aggregateValue(g.values, aggregate))
+		}
+		out = append(out, row)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if query.OrderByValue && out[i].Values[0] != out[j].Values[0] {
+			return out[i].Values[0] > out[j].Values[0]
+		}
+		return compareGroups(out[i].Groups, out[j].Groups) < 0
+	})
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	return out[:min(limit, len(out))], nil
+}
@@ -0,0 +1,272 @@
+package memory
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	columnPattern     = regexp.MustCompile(`^(?:\w+\.)?(\w+)$`)
+	comparePattern    = regexp.MustCompile(`^(?:\w+\.)?(\w+)\s*(=|!=|<>|>=|<=|>|<)\s*\?$`)
+	inPattern         = regexp.MustCompile(`(?i)^(?:\w+\.)?(\w+)\s+(NOT\s+)?IN\s*\(?\s*\?\s*\)?$`)
+	nullPattern       = regexp.MustCompile(`(?i)^(?:\w+\.)?(\w+)\s+IS\s+(NOT\s+)?NULL$`)
+	orderPattern      = regexp.MustCompile(`(?i)^(?:\w+\.)?(\w+)(?:\s+(ASC|DESC))?$`)
+	timeType          = reflect.TypeOf(time.Time{})
+	defaultNamingRule = schema.NamingStrategy{}
+)
+
+// columns maps the column names of an entity, named like GORM would, to its struct fields.
+type columns map[string]int
+
+func newColumns(t reflect.Type) columns {
+	cols := make(columns, t.NumField())
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		cols[defaultNamingRule.ColumnName("", field.Name)] = i
+	}
+
+	return cols
+}
+
+func (c columns) field(value reflect.Value, column string) (reflect.Value, error) {
+	idx, ok := c[column]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%w - unknown column %s", entities.ErrInvalid, column)
+	}
+
+	return value.Field(idx), nil
+}
+
+// predicate tells whether an entity, given as a struct value, matches a criteria.
+type predicate func(value reflect.Value) bool
+
+// newPredicate parses a criteria in the forms the GORM repositories accept:
+// "column", "column <op> ?", "column [NOT] IN ?" and "column IS [NOT] NULL".
+func (c columns) newPredicate(criteria string, arg any) (predicate, error) {
+	criteria = strings.TrimSpace(criteria)
+
+	if m := nullPattern.FindStringSubmatch(criteria); m != nil {
+		idx, ok := c[m[1]]
+		if !ok {
+			return nil, fmt.Errorf("%w - unknown column %s", entities.ErrInvalid, m[1])
+		}
+		wantNull := m[2] == ""
+		return func(value reflect.Value) bool {
+			return isNull(value.Field(idx)) == wantNull
+		}, nil
+	}
+
+	if m := inPattern.FindStringSubmatch(criteria); m != nil {
+		idx, ok := c[m[1]]
+		if !ok {
+			return nil, fmt.Errorf("%w - unknown column %s", entities.ErrInvalid, m[1])
+		}
+		args := reflect.ValueOf(arg)
+		if args.Kind() != reflect.Slice && args.Kind() != reflect.Array {
+			return nil, fmt.Errorf("%w - criteria %s expects a list argument", entities.ErrInvalid, criteria)
+		}
+		negate := m[2] != ""
+		return func(value reflect.Value) bool {
+			field := value.Field(idx)
+			if isNull(field) {
+				return false
+			}
+			for i := range args.Len() {
+				if cmp, ok := compare(field, args.Index(i).Interface()); ok && cmp == 0 {
+					return !negate
+				}
+			}
+			return negate
+		}, nil
+	}
+
+	column, operator := "", "="
+	if m := columnPattern.FindStringSubmatch(criteria); m != nil {
+		column = m[1]
+	} else if m := comparePattern.FindStringSubmatch(criteria); m != nil {
+		column, operator = m[1], m[2]
+	} else {
+		return nil, fmt.Errorf("%w - unsupported criteria %s", entities.ErrInvalid, criteria)
+	}
+
+	idx, ok := c[column]
+	if !ok {
+		return nil, fmt.Errorf("%w - unknown column %s", entities.ErrInvalid, column)
+	}
+
+	// a bare column with a list argument is expanded to IN, like GORM does
+	if args := reflect.ValueOf(arg); operator == "=" && args.Kind() == reflect.Slice {
+		return c.newPredicate(column+" IN ?", arg)
+	}
+
+	return func(value reflect.Value) bool {
+		cmp, ok := compare(value.Field(idx), arg)
+		if !ok {
+			return false
+		}
+		switch operator {
+		case "=":
+			return cmp == 0
+		case "!=", "<>":
+			return cmp != 0
+		case ">":
+			return cmp > 0
+		case ">=":
+			return cmp >= 0
+		case "<":
+			return cmp < 0
+		default:
+			return cmp <= 0
+		}
+	}, nil
+}
+
+func (c columns) newPredicates(criterias map[string]any) ([]predicate, error) {
+	predicates := make([]predicate, 0, len(criterias))
+	for k, v := range criterias {
+		p, err := c.newPredicate(k, v)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+
+	return predicates, nil
+}
+
+// sortValues orders the values by the order clauses, then by id as the database would for a primary key scan.
+func (c columns) sortValues(values []reflect.Value, orderBys []string) error {
+	type orderKey struct {
+		idx  int
+		desc bool
+	}
+
+	keys := make([]orderKey, 0, len(orderBys)+1)
+	for _, order := range orderBys {
+		m := orderPattern.FindStringSubmatch(strings.TrimSpace(order))
+		if m == nil {
+			return fmt.Errorf("%w - unsupported order %s", entities.ErrInvalid, order)
+		}
+		idx, ok := c[m[1]]
+		if !ok {
+			return fmt.Errorf("%w - unknown column %s", entities.ErrInvalid, m[1])
+		}
+		keys = append(keys, orderKey{idx: idx, desc: strings.EqualFold(m[2], "DESC")})
+	}
+	keys = append(keys, orderKey{idx: c["id"]})
+
+	sort.SliceStable(values, func(i, j int) bool {
+		for _, k := range keys {
+			cmp := compareFields(values[i].Field(k.idx), values[j].Field(k.idx))
+			if cmp == 0 {
+				continue
+			}
+			if k.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+
+	return nil
+}
+
+func isNull(field reflect.Value) bool {
+	return field.Kind() == reflect.Pointer && field.IsNil()
+}
+
+// compareFields orders two fields of the same column, NULL comes first like in MySQL.
+func compareFields(a, b reflect.Value) int {
+	switch {
+	case isNull(a) && isNull(b):
+		return 0
+	case isNull(a):
+		return -1
+	case isNull(b):
+		return 1
+	}
+
+	cmp, _ := compare(a, b.Interface())
+	return cmp
+}
+
+// compare compares a field with an argument, ok is false when they are not comparable or the field is NULL.
+func compare(field reflect.Value, arg any) (int, bool) {
+	if isNull(field) {
+		return 0, false
+	}
+	field = reflect.Indirect(field)
+
+	argValue := reflect.ValueOf(arg)
+	if argValue.Kind() == reflect.Pointer {
+		if argValue.IsNil() {
+			return 0, false
+		}
+		argValue = argValue.Elem()
+	}
+	if !argValue.IsValid() {
+		return 0, false
+	}
+
+	switch {
+	case field.Type() == timeType && argValue.Type() == timeType:
+		return field.Interface().(time.Time).Compare(argValue.Interface().(time.Time)), true
+	case field.Kind() == reflect.String && argValue.Kind() == reflect.String:
+		return strings.Compare(field.String(), argValue.String()), true
+	case field.Kind() == reflect.Bool && argValue.Kind() == reflect.Bool:
+		return compareNumbers(boolToFloat(field.Bool()), boolToFloat(argValue.Bool())), true
+	}
+
+	a, ok := toFloat(field)
+	if !ok {
+		return 0, false
+	}
+	b, ok := toFloat(argValue)
+	if !ok {
+		return 0, false
+	}
+
+	return compareNumbers(a, b), true
+}
+
+func toFloat(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func compareNumbers(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
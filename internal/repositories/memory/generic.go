@@ -0,0 +1,669 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/tenancy"
+)
+
+const DefaultLimit = 100
+
+type record[E any] struct {
+	entity    E
+	deletedAt *time.Time
+}
+
+// GenericRepository is an in-memory table of E, with the same semantics as the GORM generic repositories:
+// auto increment ids, timestamps, soft delete, unique indexes and the tenant scope of the entities with a TenantID.
+type GenericRepository[E any] struct {
+	*Repository
+	columns          columns
+	uniqueIndexes    [][]string
+	aggregateColumns []string
+	preloaders       map[string]func(ctx context.Context, entities []*E) error
+	records          map[uint]record[E]
+	lastID           uint
+}
+
+func NewGenericRepository[E any](repository *Repository) *GenericRepository[E] {
+	s := &GenericRepository[E]{
+		Repository: repository,
+		columns:    newColumns(reflect.TypeFor[E]()),
+		records:    make(map[uint]record[E]),
+	}
+	repository.register(s)
+
+	return s
+}
+
+// WithUniqueIndex adds a unique index over the columns, soft deleted records are still part of it.
+func (s *GenericRepository[E]) WithUniqueIndex(columns ...string) *GenericRepository[E] {
+	s.uniqueIndexes = append(s.uniqueIndexes, columns)
+	return s
+}
+
+// WithPreload registers the preloader of an association of E, see entities.Preload.
+// It sets the association of the entities and runs under the lock of the read, it must not lock again.
+func (s *GenericRepository[E]) WithPreload(
+	association string,
+	preloader func(ctx context.Context, entities []*E) error,
+) *GenericRepository[E] {
+	if s.preloaders == nil {
+		s.preloaders = make(map[string]func(ctx context.Context, entities []*E) error)
+	}
+	s.preloaders[association] = preloader
+	return s
+}
+
+// preload loads the associations of the options into the entities.
+func (s *GenericRepository[E]) preload(ctx context.Context, out []*E, opts []entities.QueryOption) error {
+	for _, association := range entities.NewQueryOptions(opts...).Preloads {
+		preloader, ok := s.preloaders[association]
+		if !ok {
+			return fmt.Errorf("%w - unsupported association %s", entities.ErrInvalid, association)
+		}
+		if err := preloader(ctx, out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// preloadAll is preload over a slice of entities.
+func (s *GenericRepository[E]) preloadAll(ctx context.Context, out []E, opts []entities.QueryOption) error {
+	ptrs := make([]*E, 0, len(out))
+	for i := range out {
+		ptrs = append(ptrs, &out[i])
+	}
+
+	return s.preload(ctx, ptrs, opts)
+}
+
+func (s *GenericRepository[E]) snapshot() func() {
+	records := maps.Clone(s.records)
+	lastID := s.lastID
+
+	return func() {
+		s.records = records
+		s.lastID = lastID
+	}
+}
+
+func (s *GenericRepository[E]) Ping(_ context.Context) error {
+	return nil
+}
+
+func (s *GenericRepository[E]) AutoMigrate(_ context.Context) error {
+	return nil
+}
+
+func (s *GenericRepository[E]) value(entity *E) reflect.Value {
+	return reflect.ValueOf(entity).Elem()
+}
+
+func (s *GenericRepository[E]) id(entity *E) uint {
+	return uint(s.value(entity).Field(s.columns["id"]).Uint())
+}
+
+// visible tells whether the entity belongs to the tenant the calls made with ctx are scoped to.
+func (s *GenericRepository[E]) visible(ctx context.Context, entity *E) bool {
+	idx, ok := s.columns[tenancy.Column]
+	if !ok {
+		return true
+	}
+
+	tenantID, all := tenancy.Resolve(ctx)
+	return all || uint(s.value(entity).Field(idx).Uint()) == tenantID
+}
+
+// setTenant sets the tenant of a new entity, like the tenancy plugin of the GORM repositories does.
+func (s *GenericRepository[E]) setTenant(ctx context.Context, entity *E) {
+	idx, ok := s.columns[tenancy.Column]
+	if !ok {
+		return
+	}
+
+	field := s.value(entity).Field(idx)
+	tenantID, all := tenancy.Resolve(ctx)
+	if all {
+		if !field.IsZero() {
+			return
+		}
+		tenantID = entities.DefaultTenantID
+	}
+	field.SetUint(uint64(tenantID))
+}
+
+// clone copies the entity, including the values its pointer fields refer to.
+func (s *GenericRepository[E]) clone(entity E) E {
+	v := s.value(&entity)
+	for i := range v.NumField() {
+		field := v.Field(i)
+		if field.Kind() != reflect.Pointer || field.IsNil() || !field.CanSet() {
+			continue
+		}
+		copied := reflect.New(field.Type().Elem())
+		copied.Elem().Set(field.Elem())
+		field.Set(copied)
+	}
+
+	return entity
+}
+
+func (s *GenericRepository[E]) checkUniqueIndexes(entity *E) error {
+	v := s.value(entity)
+	id := s.id(entity)
+	for _, index := range s.uniqueIndexes {
+		for otherID, r := range s.records {
+			if otherID == id {
+				continue
+			}
+			other := s.value(&r.entity)
+			duplicated := true
+			for _, column := range index {
+				idx := s.columns[column]
+				// NULL never collides, like in a database unique index
+				if isNull(v.Field(idx)) || compareFields(v.Field(idx), other.Field(idx)) != 0 {
+					duplicated = false
+					break
+				}
+			}
+			if duplicated {
+				return fmt.Errorf(
+					"%w - unique constraint failed on %s",
+					entities.ErrConflicted, strings.Join(index, ", "),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *GenericRepository[E]) insert(ctx context.Context, entity E) (E, error) {
+	entity = s.clone(entity)
+	s.setTenant(ctx, &entity)
+	v := s.value(&entity)
+	now := time.Now()
+
+	id := s.id(&entity)
+	if id == 0 {
+		id = s.lastID + 1
+		v.Field(s.columns["id"]).SetUint(uint64(id))
+	}
+	if _, ok := s.records[id]; ok {
+		return entity, fmt.Errorf("%w - unique constraint failed on id %d", entities.ErrConflicted, id)
+	}
+	for _, column := range []string{"created_at", "updated_at"} {
+		if idx, ok := s.columns[column]; ok && v.Field(idx).IsZero() {
+			v.Field(idx).Set(reflect.ValueOf(now))
+		}
+	}
+	if err := s.checkUniqueIndexes(&entity); err != nil {
+		return entity, err
+	}
+
+	s.records[id] = record[E]{entity: entity}
+	s.lastID = max(s.lastID, id)
+
+	return s.clone(entity), nil
+}
+
+func (s *GenericRepository[E]) Create(
+	ctx context.Context,
+	tx entities.Transaction,
+	entity *E,
+) (*E, error) {
+	if entity == nil {
+		return nil, fmt.Errorf("%w - input entity is nil", entities.ErrInvalid)
+	}
+
+	unlock := s.acquire(ctx, tx, true)
+	defer unlock()
+
+	out, err := s.insert(ctx, *entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (s *GenericRepository[E]) CreateMany(
+	ctx context.Context,
+	tx entities.Transaction,
+	entityArray []E,
+) ([]E, error) {
+	if len(entityArray) == 0 {
+		return nil, fmt.Errorf("%w - input entities is empty", entities.ErrInvalid)
+	}
+
+	unlock := s.acquire(ctx, tx, true)
+	defer unlock()
+
+	// the batch is a single statement, nothing is inserted if one of the records fails
+	restore := s.snapshot()
+	out := make([]E, 0, len(entityArray))
+	for _, entity := range entityArray {
+		created, err := s.insert(ctx, entity)
+		if err != nil {
+			restore()
+			return nil, err
+		}
+		out = append(out, created)
+	}
+
+	return out, nil
+}
+
+// inScope tells whether a record is read by a query with the deleted scope, see entities.QueryOptions.
+func (s *GenericRepository[E]) inScope(r record[E], scope entities.DeletedScope) bool {
+	switch scope {
+	case entities.DeletedScopeInclude:
+		return true
+	case entities.DeletedScopeOnly:
+		return r.deletedAt != nil
+	default:
+		return r.deletedAt == nil
+	}
+}
+
+// entity returns the entity of a record, its DeletedAt set when it has one, like the GORM repositories read it.
+func (s *GenericRepository[E]) entity(r record[E]) E {
+	entity := r.entity
+	if idx, ok := s.columns["deleted_at"]; ok {
+		field := s.value(&entity).Field(idx)
+		field.Set(reflect.Zero(field.Type()))
+		if r.deletedAt != nil && field.Type() == reflect.TypeFor[*time.Time]() {
+			field.Set(reflect.ValueOf(r.deletedAt))
+		}
+	}
+
+	return entity
+}
+
+// selectLive returns the records of the tenant which are not soft deleted and match all the criterias, ordered.
+func (s *GenericRepository[E]) selectLive(
+	ctx context.Context,
+	criterias map[string]any,
+	orderBys []string,
+) ([]reflect.Value, error) {
+	return s.selectScoped(ctx, entities.DeletedScopeExclude, criterias, orderBys)
+}
+
+// selectScoped returns the records of the tenant in the deleted scope which match all the criterias, ordered.
+func (s *GenericRepository[E]) selectScoped(
+	ctx context.Context,
+	scope entities.DeletedScope,
+	criterias map[string]any,
+	orderBys []string,
+) ([]reflect.Value, error) {
+	predicates, err := s.columns.newPredicates(criterias)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]reflect.Value, 0)
+	for _, r := range s.records {
+		if !s.inScope(r, scope) || !s.visible(ctx, &r.entity) {
+			continue
+		}
+		entity := s.entity(r)
+		v := s.value(&entity)
+		matched := true
+		for _, p := range predicates {
+			if !p(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			values = append(values, v)
+		}
+	}
+
+	if err := s.columns.sortValues(values, orderBys); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// project returns a copy of the entity holding only the selected fields, or all of them when fields is empty.
+func (s *GenericRepository[E]) project(v reflect.Value, fields []string) (E, error) {
+	entity := s.clone(v.Interface().(E))
+	if len(fields) == 0 {
+		return entity, nil
+	}
+
+	var out E
+	outValue := s.value(&out)
+	inValue := s.value(&entity)
+	for _, field := range fields {
+		m := columnPattern.FindStringSubmatch(strings.TrimSpace(field))
+		if m == nil {
+			return out, fmt.Errorf("%w - unsupported field %s", entities.ErrInvalid, field)
+		}
+		f, err := s.columns.field(inValue, m[1])
+		if err != nil {
+			return out, err
+		}
+		outValue.Field(s.columns[m[1]]).Set(f)
+	}
+
+	return out, nil
+}
+
+func (s *GenericRepository[E]) Get(
+	ctx context.Context,
+	tx entities.Transaction,
+	id uint,
+	opts ...entities.QueryOption,
+) (*E, error) {
+	unlock := s.acquire(ctx, tx, false)
+	defer unlock()
+
+	r, ok := s.records[id]
+	if !ok || !s.inScope(r, entities.NewQueryOptions(opts...).Deleted) || !s.visible(ctx, &r.entity) {
+		return nil, fmt.Errorf("%w - failed to get data, id: %d", entities.ErrNotFound, id)
+	}
+
+	out := s.clone(s.entity(r))
+	if err := s.preload(ctx, []*E{&out}, opts); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (s *GenericRepository[E]) GetMany(
+	ctx context.Context,
+	tx entities.Transaction,
+	ids []uint,
+	opts ...entities.QueryOption,
+) ([]E, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w - input ids is empty", entities.ErrInvalid)
+	}
+
+	unlock := s.acquire(ctx, tx, false)
+	defer unlock()
+
+	values, err := s.selectScoped(ctx, entities.NewQueryOptions(opts...).Deleted, map[string]any{"id IN ?": ids}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]E, 0, len(values))
+	for _, v := range values {
+		out = append(out, s.clone(v.Interface().(E)))
+	}
+	if err := s.preloadAll(ctx, out, opts); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *GenericRepository[E]) GetByCriterias(
+	ctx context.Context,
+	tx entities.Transaction,
+	fields []string,
+	criterias map[string]any,
+	orderBys []string,
+	opts ...entities.QueryOption,
+) (*E, error) {
+	unlock := s.acquire(ctx, tx, false)
+	defer unlock()
+
+	values, err := s.selectScoped(ctx, entities.NewQueryOptions(opts...).Deleted, criterias, orderBys)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w - failed to get data", entities.ErrNotFound)
+	}
+
+	out, err := s.project(values[0], fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.preload(ctx, []*E{&out}, opts); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (s *GenericRepository[E]) GetManyByCriterias(
+	ctx context.Context,
+	tx entities.Transaction,
+	fields []string,
+	criterias map[string]any,
+	orderBys []string,
+	offset int,
+	limit int,
+	opts ...entities.QueryOption,
+) ([]E, error) {
+	unlock := s.acquire(ctx, tx, false)
+	defer unlock()
+
+	values, err := s.selectScoped(ctx, entities.NewQueryOptions(opts...).Deleted, criterias, orderBys)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	offset = min(max(offset, 0), len(values))
+	values = values[offset:min(offset+limit, len(values))]
+
+	out := make([]E, 0, len(values))
+	for _, v := range values {
+		entity, err := s.project(v, fields)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, entity)
+	}
+	if err := s.preloadAll(ctx, out, opts); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// FindInBatches walks the records matching the criterias by batches of batchSize, DefaultLimit when not positive,
+// ordered by id, like the GORM generic repositories do. The iteration stops at the first error, which is yielded,
+// e.g. when ctx is canceled.
+func (s *GenericRepository[E]) FindInBatches(
+	ctx context.Context,
+	tx entities.Transaction,
+	criterias map[string]any,
+	batchSize int,
+) iter.Seq2[[]E, error] {
+	if batchSize <= 0 {
+		batchSize = DefaultLimit
+	}
+
+	return func(yield func([]E, error) bool) {
+		var cursor uint
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, fmt.Errorf("%w - failed to find data in batches: %w", entities.ErrCanceled, err))
+				return
+			}
+
+			batch, err := s.findBatch(ctx, tx, criterias, cursor, batchSize)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(batch) == 0 {
+				return
+			}
+			if !yield(batch, nil) || len(batch) < batchSize {
+				return
+			}
+			cursor = s.id(&batch[len(batch)-1])
+		}
+	}
+}
+
+// findBatch returns the batch of records with an id greater than cursor.
+func (s *GenericRepository[E]) findBatch(
+	ctx context.Context,
+	tx entities.Transaction,
+	criterias map[string]any,
+	cursor uint,
+	batchSize int,
+) ([]E, error) {
+	unlock := s.acquire(ctx, tx, false)
+	defer unlock()
+
+	criterias = maps.Clone(criterias)
+	if criterias == nil {
+		criterias = make(map[string]any, 1)
+	}
+	criterias["id > ?"] = cursor
+
+	values, err := s.selectLive(ctx, criterias, []string{"id"})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]E, 0, min(len(values), batchSize))
+	for _, v := range values[:min(len(values), batchSize)] {
+		out = append(out, s.clone(v.Interface().(E)))
+	}
+
+	return out, nil
+}
+
+func (s *GenericRepository[E]) Count(
+	ctx context.Context,
+	tx entities.Transaction,
+	criterias map[string]any,
+	opts ...entities.QueryOption,
+) (int64, error) {
+	unlock := s.acquire(ctx, tx, false)
+	defer unlock()
+
+	values, err := s.selectScoped(ctx, entities.NewQueryOptions(opts...).Deleted, criterias, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(values)), nil
+}
+
+// Update writes the non-zero fields of the entity to the live record with the same id, like GORM Updates does.
+func (s *GenericRepository[E]) Update(
+	ctx context.Context,
+	tx entities.Transaction,
+	entity *E,
+) error {
+	if entity == nil {
+		return fmt.Errorf("%w - input data is nil", entities.ErrInvalid)
+	}
+
+	id := s.id(entity)
+	if id == 0 {
+		return fmt.Errorf("%w - input data has no id", entities.ErrInvalid)
+	}
+
+	unlock := s.acquire(ctx, tx, true)
+	defer unlock()
+
+	r, ok := s.records[id]
+	if !ok || r.deletedAt != nil || !s.visible(ctx, &r.entity) {
+		return fmt.Errorf("%w - no rows affected", entities.ErrNotFound)
+	}
+
+	updated := s.clone(r.entity)
+	updatedValue := s.value(&updated)
+	inValue := s.value(entity)
+	for column, idx := range s.columns {
+		// an entity stays in its tenant
+		if column == "id" || column == "created_at" || column == tenancy.Column || inValue.Field(idx).IsZero() {
+			continue
+		}
+		updatedValue.Field(idx).Set(inValue.Field(idx))
+	}
+	updated = s.clone(updated)
+	if idx, ok := s.columns["updated_at"]; ok && inValue.Field(idx).IsZero() {
+		updatedValue.Field(idx).Set(reflect.ValueOf(time.Now()))
+	}
+
+	if err := s.checkUniqueIndexes(&updated); err != nil {
+		return err
+	}
+
+	s.records[id] = record[E]{entity: updated}
+	return nil
+}
+
+func (s *GenericRepository[E]) delete(ctx context.Context, permanent bool, ids []uint) int64 {
+	now := time.Now()
+
+	var affected int64
+	for _, id := range slices.Compact(slices.Sorted(slices.Values(ids))) {
+		r, ok := s.records[id]
+		if !ok || !s.visible(ctx, &r.entity) {
+			continue
+		}
+
+		if permanent {
+			delete(s.records, id)
+			affected++
+			continue
+		}
+
+		if r.deletedAt == nil {
+			r.deletedAt = &now
+			s.records[id] = r
+			affected++
+		}
+	}
+
+	return affected
+}
+
+func (s *GenericRepository[E]) Delete(
+	ctx context.Context,
+	tx entities.Transaction,
+	permanent bool,
+	id uint,
+) error {
+	unlock := s.acquire(ctx, tx, true)
+	defer unlock()
+
+	s.delete(ctx, permanent, []uint{id})
+	return nil
+}
+
+func (s *GenericRepository[E]) DeleteMany(
+	ctx context.Context,
+	tx entities.Transaction,
+	permanent bool,
+	ids []uint,
+) (int64, error) {
+	if len(ids) == 0 {
+		return 0, fmt.Errorf("%w - input ids is empty", entities.ErrInvalid)
+	}
+
+	unlock := s.acquire(ctx, tx, true)
+	defer unlock()
+
+	return s.delete(ctx, permanent, ids), nil
+}
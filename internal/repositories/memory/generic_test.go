@@ -0,0 +1,681 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+type DataEntity struct {
+	ID        uint
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+	UniqueID  string
+	Key       string
+	Value     string
+	Note      *string
+}
+
+type DataStore = GenericRepository[DataEntity]
+
+func getTestData(t *testing.T) []DataEntity {
+	t.Helper()
+	now := time.Now().UTC().Truncate(time.Second)
+	return []DataEntity{
+		{
+			CreatedAt: now,
+			UpdatedAt: now,
+			UniqueID:  "unique-id-1",
+			Key:       "key1",
+			Value:     "value1",
+		},
+		{
+			CreatedAt: now,
+			UpdatedAt: now,
+			UniqueID:  "unique-id-2",
+			Key:       "key2",
+			Value:     "value2",
+		},
+		{
+			CreatedAt: now,
+			UpdatedAt: now,
+			UniqueID:  "unique-id-3",
+			Key:       "key3",
+			Value:     "value3",
+		},
+	}
+}
+
+type GenericDataTestSuite struct {
+	suite.Suite
+	initData []DataEntity
+	store    *DataStore
+}
+
+func (s *GenericDataTestSuite) SetupTest() {
+	t := s.T()
+	t.Helper()
+	s.store = NewGenericRepository[DataEntity](NewRepository()).WithUniqueIndex("unique_id")
+
+	data, err := s.store.CreateMany(context.Background(), nil, getTestData(t))
+	s.Require().NoError(err)
+	s.initData = data
+}
+
+func (s *GenericDataTestSuite) TestGenericRepository_Create() {
+	t := s.T()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name    string
+		input   *DataEntity
+		want    *DataEntity
+		wantErr error
+	}{
+		{
+			name: "auto increment id",
+			input: &DataEntity{
+				CreatedAt: now,
+				UpdatedAt: now,
+				UniqueID:  "unique-id-4",
+				Key:       "key4",
+				Value:     "value4",
+			},
+			want: &DataEntity{
+				ID:        4,
+				CreatedAt: now,
+				UpdatedAt: now,
+				UniqueID:  "unique-id-4",
+				Key:       "key4",
+				Value:     "value4",
+			},
+		},
+		{
+			name: "duplicated unique index",
+			input: &DataEntity{
+				UniqueID: "unique-id-1",
+			},
+			wantErr: entities.ErrConflicted,
+		},
+		{
+			name: "duplicated id",
+			input: &DataEntity{
+				ID:       1,
+				UniqueID: "unique-id-10",
+			},
+			wantErr: entities.ErrConflicted,
+		},
+		{
+			name:    "nil input",
+			input:   nil,
+			wantErr: entities.ErrInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.store.Create(context.Background(), nil, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("store.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("store.Create() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func (s *GenericDataTestSuite) TestGenericRepository_CreateManyIsAtomic() {
+	_, err := s.store.CreateMany(context.Background(), nil, []DataEntity{
+		{UniqueID: "unique-id-4"},
+		{UniqueID: "unique-id-4"},
+	})
+	s.Require().ErrorIs(err, entities.ErrConflicted)
+
+	cnt, err := s.store.Count(context.Background(), nil, nil)
+	s.Require().NoError(err)
+	s.Equal(int64(3), cnt)
+}
+
+func (s *GenericDataTestSuite) TestGenericRepository_GetManyByCriterias() {
+	t := s.T()
+
+	tests := []struct {
+		name      string
+		fields    []string
+		criterias map[string]any
+		orderBys  []string
+		offset    int
+		limit     int
+		want      []DataEntity
+		wantErr   bool
+	}{
+		{
+			name:      "equal",
+			criterias: map[string]any{"key": "key2"},
+			want:      s.initData[1:2],
+		},
+		{
+			name:      "in list",
+			criterias: map[string]any{"unique_id IN ?": []string{"unique-id-1", "unique-id-3"}},
+			want:      []DataEntity{s.initData[0], s.initData[2]},
+		},
+		{
+			name:      "comparison and order",
+			criterias: map[string]any{"id >= ?": 2},
+			orderBys:  []string{"id DESC"},
+			want:      []DataEntity{s.initData[2], s.initData[1]},
+		},
+		{
+			name:     "offset and limit",
+			orderBys: []string{"unique_id desc"},
+			offset:   1,
+			limit:    1,
+			want:     s.initData[1:2],
+		},
+		{
+			name:      "null",
+			fields:    []string{"id"},
+			criterias: map[string]any{"note IS NULL": nil, "value <> ?": "value1"},
+			want:      []DataEntity{{ID: 2}, {ID: 3}},
+		},
+		{
+			name:      "unsupported criteria",
+			criterias: map[string]any{"key LIKE ?": "key%"},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown column",
+			criterias: map[string]any{"unknown": 1},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.store.GetManyByCriterias(
+				context.Background(), nil,
+				tt.fields, tt.criterias, tt.orderBys,
+				tt.offset, tt.limit,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("store.GetManyByCriterias() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("store.GetManyByCriterias() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func (s *GenericDataTestSuite) TestGenericRepository_Update() {
+	note := "note"
+	s.Require().NoError(s.store.Update(context.Background(), nil, &DataEntity{ID: 1, Value: "updated", Note: &note}))
+
+	got, err := s.store.Get(context.Background(), nil, 1)
+	s.Require().NoError(err)
+	s.Equal("updated", got.Value)
+	s.Equal("key1", got.Key)
+	s.Equal("note", *got.Note)
+
+	note = "changed outside"
+	got, err = s.store.Get(context.Background(), nil, 1)
+	s.Require().NoError(err)
+	s.Equal("note", *got.Note)
+
+	s.Require().ErrorIs(
+		s.store.Update(context.Background(), nil, &DataEntity{ID: 2, UniqueID: "unique-id-1"}),
+		entities.ErrConflicted,
+	)
+	s.Require().ErrorIs(
+		s.store.Update(context.Background(), nil, &DataEntity{ID: 10, Value: "updated"}),
+		entities.ErrNotFound,
+	)
+}
+
+func (s *GenericDataTestSuite) TestGenericRepository_SoftDelete() {
+	s.Require().NoError(s.store.Delete(context.Background(), nil, false, 1))
+
+	_, err := s.store.Get(context.Background(), nil, 1)
+	s.Require().ErrorIs(err, entities.ErrNotFound)
+
+	cnt, err := s.store.Count(context.Background(), nil, nil)
+	s.Require().NoError(err)
+	s.Equal(int64(2), cnt)
+
+	s.Require().ErrorIs(
+		s.store.Update(context.Background(), nil, &DataEntity{ID: 1, Value: "updated"}),
+		entities.ErrNotFound,
+	)
+
+	// the soft deleted record still holds its unique index
+	_, err = s.store.Create(context.Background(), nil, &DataEntity{UniqueID: "unique-id-1"})
+	s.Require().ErrorIs(err, entities.ErrConflicted)
+
+	affected, err := s.store.DeleteMany(context.Background(), nil, true, []uint{1, 2})
+	s.Require().NoError(err)
+	s.Equal(int64(2), affected)
+
+	_, err = s.store.Create(context.Background(), nil, &DataEntity{UniqueID: "unique-id-1"})
+	s.Require().NoError(err)
+}
+
+func (s *GenericDataTestSuite) TestGenericRepository_DeletedScope() {
+	ctx := context.Background()
+	s.Require().NoError(s.store.Delete(ctx, nil, false, 1))
+
+	tests := []struct {
+		name    string
+		opts    []entities.QueryOption
+		wantIDs []uint
+	}{
+		{name: "active only", wantIDs: []uint{2, 3}},
+		{name: "include deleted", opts: []entities.QueryOption{entities.WithDeleted()}, wantIDs: []uint{1, 2, 3}},
+		{name: "only deleted", opts: []entities.QueryOption{entities.OnlyDeleted()}, wantIDs: []uint{1}},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			wantDeleted := tt.wantIDs[0] == 1
+
+			got, err := s.store.Get(ctx, nil, 1, tt.opts...)
+			s.Equal(wantDeleted, err == nil, "Get() error = %v", err)
+			if err == nil {
+				s.NotNil(got.DeletedAt)
+			}
+			_, err = s.store.GetByCriterias(ctx, nil, nil, map[string]any{"unique_id": "unique-id-1"}, nil, tt.opts...)
+			s.Equal(wantDeleted, err == nil, "GetByCriterias() error = %v", err)
+
+			many, err := s.store.GetMany(ctx, nil, []uint{1, 2, 3}, tt.opts...)
+			s.Require().NoError(err)
+			all, err := s.store.GetManyByCriterias(ctx, nil, nil, nil, []string{"id"}, 0, 0, tt.opts...)
+			s.Require().NoError(err)
+			for _, records := range [][]DataEntity{many, all} {
+				ids := make([]uint, 0, len(records))
+				for _, record := range records {
+					ids = append(ids, record.ID)
+					s.Equal(record.ID == 1, record.DeletedAt != nil)
+				}
+				s.ElementsMatch(tt.wantIDs, ids)
+			}
+
+			cnt, err := s.store.Count(ctx, nil, nil, tt.opts...)
+			s.Require().NoError(err)
+			s.Equal(int64(len(tt.wantIDs)), cnt)
+		})
+	}
+}
+
+func (s *GenericDataTestSuite) TestGenericRepository_Transaction() {
+	t := s.T()
+
+	create := func(uniqueID string) entities.DBTxHandleFunc {
+		return func(ctx context.Context, txKeeper entities.Transaction) error {
+			_, err := s.store.Create(ctx, txKeeper, &DataEntity{UniqueID: uniqueID})
+			return err
+		}
+	}
+
+	tests := []struct {
+		name      string
+		funcs     []entities.DBTxHandleFunc
+		wantCount int64
+		wantErr   bool
+	}{
+		{
+			name:      "no error",
+			funcs:     []entities.DBTxHandleFunc{create("unique-id-4"), create("unique-id-5")},
+			wantCount: 5,
+			wantErr:   false,
+		},
+		{
+			name: "with error",
+			funcs: []entities.DBTxHandleFunc{
+				create("unique-id-6"),
+				func(ctx context.Context, txKeeper entities.Transaction) error {
+					return s.store.Delete(ctx, txKeeper, true, 1)
+				},
+				func(_ context.Context, _ entities.Transaction) error {
+					return fmt.Errorf("fake error")
+				},
+			},
+			wantCount: 5,
+			wantErr:   true,
+		},
+		{
+			name: "nested transaction rolled back alone",
+			funcs: []entities.DBTxHandleFunc{
+				create("unique-id-7"),
+				func(ctx context.Context, _ entities.Transaction) error {
+					err := s.store.RunTx(ctx, create("unique-id-8"), create("unique-id-1"))
+					if !errors.Is(err, entities.ErrConflicted) {
+						return fmt.Errorf("unexpected nested error: %w", err)
+					}
+					return nil
+				},
+			},
+			wantCount: 6,
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.store.RunTx(context.Background(), tt.funcs...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("store.RunTx() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			cnt, err := s.store.Count(context.Background(), nil, nil)
+			if err != nil {
+				t.Errorf("failed to count data: %v", err)
+				return
+			}
+			if cnt != tt.wantCount {
+				t.Errorf("store.RunTx() count = %v, want %v", cnt, tt.wantCount)
+			}
+		})
+	}
+
+	_, err := s.store.Get(context.Background(), nil, 1)
+	s.Require().NoError(err)
+	_, err = s.store.GetByCriterias(context.Background(), nil, nil, map[string]any{"unique_id": "unique-id-8"}, nil)
+	s.Require().ErrorIs(err, entities.ErrNotFound)
+}
+
+func (s *GenericDataTestSuite) TestGenericRepository_TransactionErrors() {
+	t := s.T()
+
+	// the errors classified by the repositories are returned as is, like by the SQL backends
+	for _, want := range []error{
+		entities.ErrCanceled,
+		entities.ErrInvalid,
+		entities.ErrConflicted,
+		entities.ErrMalformed,
+		entities.ErrTooManyRequests,
+		entities.ErrLocked,
+		entities.ErrNotFound,
+	} {
+		t.Run(want.Error(), func(t *testing.T) {
+			err := s.store.RunTx(context.Background(), func(_ context.Context, _ entities.Transaction) error {
+				return fmt.Errorf("%w - fake error", want)
+			})
+			if !errors.Is(err, want) || errors.Is(err, entities.ErrDatabase) {
+				t.Errorf("store.RunTx() error = %v, want %v", err, want)
+			}
+		})
+	}
+
+	err := s.store.RunTx(context.Background(), func(_ context.Context, _ entities.Transaction) error {
+		return fmt.Errorf("fake error")
+	})
+	s.Require().ErrorIs(err, entities.ErrDatabase)
+}
+
+func (s *GenericDataTestSuite) TestGenericRepository_FindInBatches() {
+	t := s.T()
+
+	tests := []struct {
+		name      string
+		criterias map[string]any
+		batchSize int
+		want      [][]uint
+	}{
+		{
+			name:      "batches of 2",
+			criterias: nil,
+			batchSize: 2,
+			want:      [][]uint{{1, 2}, {3}},
+		},
+		{
+			name:      "exact batches",
+			criterias: nil,
+			batchSize: 3,
+			want:      [][]uint{{1, 2, 3}},
+		},
+		{
+			name:      "default batch size",
+			criterias: nil,
+			batchSize: 0,
+			want:      [][]uint{{1, 2, 3}},
+		},
+		{
+			name: "criterias",
+			criterias: map[string]any{
+				"key IN ?": []string{"key1", "key3"},
+			},
+			batchSize: 1,
+			want:      [][]uint{{1}, {3}},
+		},
+		{
+			name: "no records",
+			criterias: map[string]any{
+				"key": "key1000",
+			},
+			batchSize: 1,
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]uint
+			for batch, err := range s.store.FindInBatches(context.Background(), nil, tt.criterias, tt.batchSize) {
+				if err != nil {
+					t.Errorf("store.FindInBatches() error = %v", err)
+					return
+				}
+				ids := make([]uint, 0, len(batch))
+				for _, entity := range batch {
+					ids = append(ids, entity.ID)
+				}
+				got = append(got, ids)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("store.FindInBatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// the records can be written while iterating, and the iteration stops on break
+	batches := 0
+	for batch, err := range s.store.FindInBatches(context.Background(), nil, nil, 1) {
+		s.Require().NoError(err)
+		batch[0].Value = "updated"
+		s.Require().NoError(s.store.Update(context.Background(), nil, &batch[0]))
+		batches++
+		if batches == 2 {
+			break
+		}
+	}
+	s.Require().Equal(2, batches)
+	updated, err := s.store.Count(context.Background(), nil, map[string]any{"value": "updated"})
+	s.Require().NoError(err)
+	s.Require().Equal(int64(2), updated)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	batches = 0
+	for _, err := range s.store.FindInBatches(ctx, nil, nil, 1) {
+		if err != nil {
+			s.Require().ErrorIs(err, entities.ErrCanceled)
+			break
+		}
+		batches++
+		cancel()
+	}
+	s.Require().Equal(1, batches)
+}
+
+func (s *GenericDataTestSuite) TestGenericRepository_Aggregate() {
+	t := s.T()
+	s.store.WithAggregateColumns("id", "key", "created_at")
+
+	day := func(d int) time.Time {
+		return time.Date(2025, time.January, d, 0, 0, 0, 0, time.UTC)
+	}
+	if _, err := s.store.CreateMany(context.Background(), nil, []DataEntity{
+		// Monday, Tuesday and Sunday of the same week, then Monday of the next one
+		{CreatedAt: day(6).Add(10 * time.Hour), UniqueID: "unique-id-4", Key: "key1"},
+		{CreatedAt: day(7).Add(23 * time.Hour), UniqueID: "unique-id-5", Key: "key1"},
+		{CreatedAt: day(12).Add(time.Hour), UniqueID: "unique-id-6", Key: "key2"},
+		{CreatedAt: day(13), UniqueID: "unique-id-7", Key: "key1"},
+	}); err != nil {
+		t.Errorf("failed to create data: %v", err)
+		return
+	}
+	january := map[string]any{
+		"created_at >= ?": day(1),
+		"created_at < ?":  day(1).AddDate(0, 1, 0),
+	}
+	count := []entities.Aggregate{{Func: entities.AggregateCount}}
+
+	tests := []struct {
+		name    string
+		query   entities.AggregateQuery
+		want    []entities.AggregateRow
+		wantErr error
+	}{
+		{
+			name: "count by key",
+			query: entities.AggregateQuery{
+				GroupBys:   []entities.GroupBy{{Column: "key"}},
+				Aggregates: count,
+			},
+			want: []entities.AggregateRow{
+				{Groups: []any{"key1"}, Values: []float64{4}},
+				{Groups: []any{"key2"}, Values: []float64{2}},
+				{Groups: []any{"key3"}, Values: []float64{1}},
+			},
+		},
+		{
+			name: "order by value",
+			query: entities.AggregateQuery{
+				GroupBys:     []entities.GroupBy{{Column: "key"}},
+				Aggregates:   count,
+				Criterias:    map[string]any{"key != ?": "key1"},
+				OrderByValue: true,
+				Limit:        1,
+			},
+			want: []entities.AggregateRow{
+				{Groups: []any{"key2"}, Values: []float64{2}},
+			},
+		},
+		{
+			name: "sum min max",
+			query: entities.AggregateQuery{
+				Aggregates: []entities.Aggregate{
+					{Func: entities.AggregateSum, Column: "id"},
+					{Func: entities.AggregateMin, Column: "id"},
+					{Func: entities.AggregateMax, Column: "id"},
+					{Func: entities.AggregateCount, Column: "id"},
+				},
+			},
+			want: []entities.AggregateRow{
+				{Groups: []any{}, Values: []float64{28, 1, 7, 7}},
+			},
+		},
+		{
+			name: "no records",
+			query: entities.AggregateQuery{
+				Aggregates: []entities.Aggregate{{Func: entities.AggregateSum, Column: "id"}},
+				Criterias:  map[string]any{"key": "key1000"},
+			},
+			want: []entities.AggregateRow{
+				{Groups: []any{}, Values: []float64{0}},
+			},
+		},
+		{
+			name: "per day",
+			query: entities.AggregateQuery{
+				GroupBys:   []entities.GroupBy{{Column: "created_at", Bucket: entities.TimeBucketDay}},
+				Aggregates: count,
+				Criterias:  january,
+			},
+			want: []entities.AggregateRow{
+				{Groups: []any{day(6)}, Values: []float64{1}},
+				{Groups: []any{day(7)}, Values: []float64{1}},
+				{Groups: []any{day(12)}, Values: []float64{1}},
+				{Groups: []any{day(13)}, Values: []float64{1}},
+			},
+		},
+		{
+			name: "per week and key",
+			query: entities.AggregateQuery{
+				GroupBys: []entities.GroupBy{
+					{Column: "created_at", Bucket: entities.TimeBucketWeek},
+					{Column: "key"},
+				},
+				Aggregates: count,
+				Criterias:  january,
+			},
+			want: []entities.AggregateRow{
+				{Groups: []any{day(6), "key1"}, Values: []float64{2}},
+				{Groups: []any{day(6), "key2"}, Values: []float64{1}},
+				{Groups: []any{day(13), "key1"}, Values: []float64{1}},
+			},
+		},
+		{
+			name: "per month",
+			query: entities.AggregateQuery{
+				GroupBys:   []entities.GroupBy{{Column: "created_at", Bucket: entities.TimeBucketMonth}},
+				Aggregates: count,
+				Criterias:  january,
+			},
+			want: []entities.AggregateRow{
+				{Groups: []any{day(1)}, Values: []float64{4}},
+			},
+		},
+		{
+			name:    "no aggregate",
+			query:   entities.AggregateQuery{GroupBys: []entities.GroupBy{{Column: "key"}}},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name: "column not allowed",
+			query: entities.AggregateQuery{
+				GroupBys:   []entities.GroupBy{{Column: "value"}},
+				Aggregates: count,
+			},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name: "unknown bucket",
+			query: entities.AggregateQuery{
+				GroupBys:   []entities.GroupBy{{Column: "created_at", Bucket: "year"}},
+				Aggregates: count,
+			},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name: "unknown aggregate",
+			query: entities.AggregateQuery{
+				Aggregates: []entities.Aggregate{{Func: "avg", Column: "id"}},
+			},
+			wantErr: entities.ErrInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.store.Aggregate(context.Background(), nil, tt.query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("store.Aggregate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("store.Aggregate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenericDataTestSuite(t *testing.T) {
+	suite.Run(t, new(GenericDataTestSuite))
+}
@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/logger"
+)
+
+var log = logger.MustNamedLogger("memory")
+
+func GenerateError(errStr string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%w - %s, err: %w", entities.ErrDatabase, errStr, err)
+}
+
+func handleTransactionError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	eligibleErr := errors.Is(err, entities.ErrCanceled) ||
+		errors.Is(err, entities.ErrInvalid) ||
+		errors.Is(err, entities.ErrConflicted) ||
+		errors.Is(err, entities.ErrMalformed) ||
+		errors.Is(err, entities.ErrTooManyRequests) ||
+		errors.Is(err, entities.ErrLocked) ||
+		errors.Is(err, entities.ErrNotFound) ||
+		errors.Is(err, entities.ErrDatabase)
+	if eligibleErr {
+		return err
+	}
+
+	return GenerateError("transaction error", err)
+}
+
+// table is the part of an in-memory table the repository needs to roll a transaction back.
+type table interface {
+	// snapshot captures the current content and returns the function restoring it
+	snapshot() func()
+}
+
+type MemoryTransaction struct {
+	repository *Repository
+}
+
+func (t *MemoryTransaction) GetTransaction() any {
+	return t
+}
+
+// Repository is an in-memory database made of the tables of its generic repositories.
+// Transactions are serialized: RunTx holds the write lock until it returns,
+// and the calls made within the transaction do not lock again.
+type Repository struct {
+	mutex  sync.RWMutex
+	tables []table
+}
+
+func NewRepository() *Repository {
+	return &Repository{}
+}
+
+func (r *Repository) Start(_ context.Context) error {
+	log.Info("starting memory repository")
+	return nil
+}
+
+func (r *Repository) Stop(_ context.Context) error {
+	log.Info("stopping memory repository")
+	return nil
+}
+
+func (r *Repository) register(t table) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.tables = append(r.tables, t)
+}
+
+// inTransaction reports whether the call is part of a transaction of this repository,
+// either given explicitly or carried by ctx.
+func (r *Repository) inTransaction(ctx context.Context, tx entities.Transaction) bool {
+	if tx == nil {
+		tx = entities.GetTransactionFromContext(ctx)
+	}
+	if tx == nil {
+		return false
+	}
+
+	memTx, ok := tx.GetTransaction().(*MemoryTransaction)
+	return ok && memTx.repository == r
+}
+
+// acquire locks the repository for a call outside of a transaction and returns the unlock function.
+func (r *Repository) acquire(ctx context.Context, tx entities.Transaction, write bool) func() {
+	if r.inTransaction(ctx, tx) {
+		return func() {}
+	}
+
+	if write {
+		r.mutex.Lock()
+		return r.mutex.Unlock
+	}
+
+	r.mutex.RLock()
+	return r.mutex.RUnlock
+}
+
+func (r *Repository) RunTx(ctx context.Context, funcs ...entities.DBTxHandleFunc) (err error) {
+	if len(funcs) == 0 {
+		return fmt.Errorf("%w - input no handler function", entities.ErrInternal)
+	}
+
+	// the commit hooks run once the lock is released
+	ctx, runCommitHooks := entities.WithCommitHooks(ctx)
+	defer func() { runCommitHooks(err) }()
+
+	// a transaction already carried by ctx is continued, the snapshot below acts as a savepoint
+	if !r.inTransaction(ctx, nil) {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+	}
+
+	restores := make([]func(), 0, len(r.tables))
+	for _, t := range r.tables {
+		restores = append(restores, t.snapshot())
+	}
+
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		for _, restore := range restores {
+			restore()
+		}
+	}()
+
+	txKeeper := &MemoryTransaction{repository: r}
+	txCtx := entities.InjectTransactionToContext(ctx, txKeeper)
+	for _, f := range funcs {
+		if f == nil {
+			continue
+		}
+		if ferr := f(txCtx, txKeeper); ferr != nil {
+			return handleTransactionError(ferr)
+		}
+	}
+
+	committed = true
+	return nil
+}
@@ -0,0 +1,384 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+type UserRepository struct {
+	*GenericRepository[entities.User]
+}
+
+func NewUserRepository(repository *Repository) *UserRepository {
+	return &UserRepository{
+		GenericRepository: NewGenericRepository[entities.User](repository).
+			WithUniqueIndex("tenant_id", "username").
+			WithAggregateColumns("created_at"),
+	}
+}
+
+func (s *UserRepository) Start(_ context.Context) error {
+	log.Info("starting user store")
+	return nil
+}
+
+func (s *UserRepository) Stop(_ context.Context) error {
+	log.Info("stopping user store")
+	return nil
+}
+
+func (s *UserRepository) FindByUsername(
+	ctx context.Context,
+	tx entities.Transaction,
+	username string,
+	opts ...entities.QueryOption,
+) (*entities.User, error) {
+	if username == "" {
+		return nil, fmt.Errorf("%w - input username is empty", entities.ErrInvalid)
+	}
+
+	return s.GetByCriterias(
+		ctx, tx,
+		nil,
+		map[string]any{"username": username},
+		[]string{"id"},
+		opts...,
+	)
+}
+
+// FindByUsernames finds the users of the usernames, in the order of their ids, leaving out the unknown usernames.
+func (s *UserRepository) FindByUsernames(
+	ctx context.Context,
+	tx entities.Transaction,
+	usernames []string,
+	opts ...entities.QueryOption,
+) ([]entities.User, error) {
+	if len(usernames) == 0 {
+		return nil, fmt.Errorf("%w - input usernames is empty", entities.ErrInvalid)
+	}
+
+	return s.GetManyByCriterias(
+		ctx, tx,
+		nil,
+		map[string]any{"username IN ?": usernames},
+		[]string{"id"},
+		0, len(usernames),
+		opts...,
+	)
+}
+
+// FindByUuids finds the users of the uuids, in the order of their ids, leaving out the unknown uuids.
+func (s *UserRepository) FindByUuids(
+	ctx context.Context,
+	tx entities.Transaction,
+	uuids []string,
+	opts ...entities.QueryOption,
+) ([]entities.User, error) {
+	if len(uuids) == 0 {
+		return nil, fmt.Errorf("%w - input uuids is empty", entities.ErrInvalid)
+	}
+
+	return s.GetManyByCriterias(
+		ctx, tx,
+		nil,
+		map[string]any{"uuid IN ?": uuids},
+		[]string{"id"},
+		0, len(uuids),
+		opts...,
+	)
+}
+
+// FindByEmail finds a user by email, the emails are not encrypted in memory.
+func (s *UserRepository) FindByEmail(
+	ctx context.Context,
+	tx entities.Transaction,
+	email string,
+) (*entities.User, error) {
+	if email == "" {
+		return nil, fmt.Errorf("%w - input email is empty", entities.ErrInvalid)
+	}
+
+	return s.GetByCriterias(
+		ctx, tx,
+		nil,
+		map[string]any{"email": email},
+		[]string{"id"},
+	)
+}
+
+type UserAttributeRepository struct {
+	*GenericRepository[entities.UserAttribute]
+	userRepository *UserRepository
+}
+
+func NewUserAttributeRepository(repository *Repository, userRepository *UserRepository) *UserAttributeRepository {
+	s := &UserAttributeRepository{
+		GenericRepository: NewGenericRepository[entities.UserAttribute](repository).WithAggregateColumns("key", "value"),
+		userRepository:    userRepository,
+	}
+	userRepository.WithPreload("Attributes", s.preloadAttributes)
+
+	return s
+}
+
+// preloadAttributes sets the attributes of the users, in the order of their ids, like the GORM preload.
+func (s *UserAttributeRepository) preloadAttributes(ctx context.Context, users []*entities.User) error {
+	ids := make([]uint, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+
+	values, err := s.selectLive(ctx, map[string]any{"user_id IN ?": ids}, []string{"id"})
+	if err != nil {
+		return err
+	}
+
+	attributes := make(map[uint][]entities.UserAttribute, len(users))
+	for _, v := range values {
+		attribute := s.clone(v.Interface().(entities.UserAttribute))
+		attributes[attribute.UserID] = append(attributes[attribute.UserID], attribute)
+	}
+	for _, user := range users {
+		user.Attributes = append(make([]entities.UserAttribute, 0), attributes[user.ID]...)
+	}
+
+	return nil
+}
+
+func (s *UserAttributeRepository) Start(_ context.Context) error {
+	log.Info("starting user attribute store")
+	return nil
+}
+
+func (s *UserAttributeRepository) Stop(_ context.Context) error {
+	log.Info("stopping user attribute store")
+	return nil
+}
+
+func (s *UserAttributeRepository) GetByUserID(
+	ctx context.Context,
+	tx entities.Transaction,
+	userID uint,
+) ([]entities.UserAttribute, error) {
+	return s.GetManyByCriterias(
+		ctx, tx,
+		nil,
+		map[string]any{"user_id": userID},
+		[]string{"id"},
+		0, 0,
+	)
+}
+
+// userIDsByName returns the ids of the users of the tenant named userName,
+// soft deleted ones included like the SQL join.
+func (s *UserAttributeRepository) userIDsByName(ctx context.Context, userName string) []uint {
+	ids := make([]uint, 0, 1)
+	for id, r := range s.userRepository.records {
+		if r.entity.Username == userName && s.userRepository.visible(ctx, &r.entity) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+func (s *UserAttributeRepository) CountByUserName(
+	ctx context.Context,
+	tx entities.Transaction,
+	userName string,
+) (int64, error) {
+	unlock := s.acquire(ctx, tx, false)
+	defer unlock()
+
+	values, err := s.selectLive(ctx, map[string]any{"user_id IN ?": s.userIDsByName(ctx, userName)}, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(values)), nil
+}
+
+func (s *UserAttributeRepository) GetManyByUserName(
+	ctx context.Context,
+	tx entities.Transaction,
+	userName string,
+) ([]entities.UserAttribute, error) {
+	unlock := s.acquire(ctx, tx, false)
+	defer unlock()
+
+	values, err := s.selectLive(ctx, map[string]any{"user_id IN ?": s.userIDsByName(ctx, userName)}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]entities.UserAttribute, 0, len(values))
+	for _, v := range values {
+		out = append(out, s.clone(v.Interface().(entities.UserAttribute)))
+	}
+
+	return out, nil
+}
+
+type MessageRepository struct {
+	*GenericRepository[entities.Message]
+}
+
+func NewMessageRepository(repository *Repository) *MessageRepository {
+	return &MessageRepository{
+		GenericRepository: NewGenericRepository[entities.Message](repository),
+	}
+}
+
+func (s *MessageRepository) Start(_ context.Context) error {
+	log.Info("starting message store")
+	return nil
+}
+
+func (s *MessageRepository) Stop(_ context.Context) error {
+	log.Info("stopping message store")
+	return nil
+}
+
+type OutboxRepository struct {
+	*GenericRepository[entities.OutboxMessage]
+}
+
+func NewOutboxRepository(repository *Repository) *OutboxRepository {
+	return &OutboxRepository{
+		GenericRepository: NewGenericRepository[entities.OutboxMessage](repository),
+	}
+}
+
+func (s *OutboxRepository) Start(_ context.Context) error {
+	log.Info("starting outbox store")
+	return nil
+}
+
+func (s *OutboxRepository) Stop(_ context.Context) error {
+	log.Info("stopping outbox store")
+	return nil
+}
+
+func (s *OutboxRepository) GetPending(
+	ctx context.Context,
+	tx entities.Transaction,
+	limit int,
+) ([]entities.OutboxMessage, error) {
+	return s.GetManyByCriterias(
+		ctx, tx,
+		nil,
+		map[string]any{"sent_at IS NULL": nil, "dead_at IS NULL": nil},
+		[]string{"id"},
+		0, limit,
+	)
+}
+
+func (s *OutboxRepository) MarkSent(
+	ctx context.Context,
+	tx entities.Transaction,
+	ids []uint,
+) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("%w - input ids is empty", entities.ErrInvalid)
+	}
+
+	unlock := s.acquire(ctx, tx, true)
+	defer unlock()
+
+	now := time.Now()
+	values, err := s.selectLive(ctx, map[string]any{"id IN ?": ids, "sent_at IS NULL": nil}, nil)
+	if err != nil {
+		return err
+	}
+	for _, v := range values {
+		message := v.Interface().(entities.OutboxMessage)
+		message.SentAt = &now
+		message.LastError = ""
+		message.UpdatedAt = now
+		s.records[message.ID] = record[entities.OutboxMessage]{entity: message}
+	}
+
+	return nil
+}
+
+// MarkFailed counts a failed attempt of the message, and gives it up when dead is set.
+func (s *OutboxRepository) MarkFailed(
+	ctx context.Context,
+	tx entities.Transaction,
+	id uint,
+	reason string,
+	dead bool,
+) error {
+	unlock := s.acquire(ctx, tx, true)
+	defer unlock()
+
+	r, ok := s.records[id]
+	if !ok || r.deletedAt != nil || !s.visible(ctx, &r.entity) {
+		return fmt.Errorf("%w - no rows affected", entities.ErrNotFound)
+	}
+
+	now := time.Now()
+	r.entity.Attempts++
+	r.entity.LastError = reason
+	r.entity.UpdatedAt = now
+	if dead {
+		r.entity.DeadAt = &now
+	}
+	s.records[id] = r
+
+	return nil
+}
+
+// AuditEventRepository is the audit trail of the memory backend, which records no mutation:
+// the audit events are written by the audit plugin of the GORM backends.
+type AuditEventRepository struct {
+	*GenericRepository[entities.AuditEvent]
+}
+
+func NewAuditEventRepository(repository *Repository) *AuditEventRepository {
+	return &AuditEventRepository{
+		GenericRepository: NewGenericRepository[entities.AuditEvent](repository),
+	}
+}
+
+func (s *AuditEventRepository) Start(_ context.Context) error {
+	log.Info("starting audit event store")
+	return nil
+}
+
+func (s *AuditEventRepository) Stop(_ context.Context) error {
+	log.Info("stopping audit event store")
+	return nil
+}
+
+type TenantRepository struct {
+	*GenericRepository[entities.Tenant]
+}
+
+func NewTenantRepository(repository *Repository) *TenantRepository {
+	return &TenantRepository{
+		GenericRepository: NewGenericRepository[entities.Tenant](repository).WithUniqueIndex("name"),
+	}
+}
+
+// Start creates the default tenant, like the GORM tenant repository does.
+func (s *TenantRepository) Start(ctx context.Context) error {
+	log.Info("starting tenant store")
+
+	_, err := s.Get(ctx, nil, entities.DefaultTenantID)
+	if !errors.Is(err, entities.ErrNotFound) {
+		return err
+	}
+
+	_, err = s.Create(ctx, nil, &entities.Tenant{Name: entities.DefaultTenantName})
+	return err
+}
+
+func (s *TenantRepository) Stop(_ context.Context) error {
+	log.Info("stopping tenant store")
+	return nil
+}
@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+func TestUserRepositories(t *testing.T) {
+	t.Parallel()
+
+	r := NewRepository()
+	userRepository := NewUserRepository(r)
+	userAttributeRepository := NewUserAttributeRepository(r, userRepository)
+
+	email := "user1@example.com"
+	user, err := userRepository.Create(context.Background(), nil, &entities.User{Username: "user1", Email: &email})
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if _, err := userRepository.Create(context.Background(), nil, &entities.User{Username: "user1"}); !errors.Is(err, entities.ErrConflicted) {
+		t.Errorf("userRepository.Create() error = %v, want %v", err, entities.ErrConflicted)
+	}
+	if _, err := userAttributeRepository.CreateMany(context.Background(), nil, []entities.UserAttribute{
+		{UserID: user.ID, Key: "key1", Value: "value1"},
+		{UserID: user.ID, Key: "key2", Value: "value2"},
+	}); err != nil {
+		t.Fatalf("failed to create user attributes: %v", err)
+	}
+
+	found, err := userRepository.FindByUsername(context.Background(), nil, "user1")
+	if err != nil || found.ID != user.ID {
+		t.Errorf("userRepository.FindByUsername() = %v, %v, want %v", found, err, user)
+	}
+	if _, err := userRepository.FindByUsername(context.Background(), nil, "user2"); !errors.Is(err, entities.ErrNotFound) {
+		t.Errorf("userRepository.FindByUsername() error = %v, want %v", err, entities.ErrNotFound)
+	}
+
+	found, err = userRepository.FindByEmail(context.Background(), nil, email)
+	if err != nil || found.ID != user.ID {
+		t.Errorf("userRepository.FindByEmail() = %v, %v, want %v", found, err, user)
+	}
+	if _, err := userRepository.FindByEmail(context.Background(), nil, "user2@example.com"); !errors.Is(err, entities.ErrNotFound) {
+		t.Errorf("userRepository.FindByEmail() error = %v, want %v", err, entities.ErrNotFound)
+	}
+
+	attributes, err := userAttributeRepository.GetManyByUserName(context.Background(), nil, "user1")
+	if err != nil || len(attributes) != 2 {
+		t.Errorf("userAttributeRepository.GetManyByUserName() = %v, %v, want 2 attributes", attributes, err)
+	}
+	cnt, err := userAttributeRepository.CountByUserName(context.Background(), nil, "user2")
+	if err != nil || cnt != 0 {
+		t.Errorf("userAttributeRepository.CountByUserName() = %v, %v, want 0", cnt, err)
+	}
+
+	if _, err := userRepository.Create(context.Background(), nil, &entities.User{Username: "user2", Uuid: "uuid2"}); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	users, err := userRepository.FindByUsernames(
+		context.Background(), nil, []string{"user2", "user1", "user3"}, entities.Preload("Attributes"),
+	)
+	if err != nil || len(users) != 2 || users[0].Username != "user1" || users[1].Username != "user2" {
+		t.Fatalf("userRepository.FindByUsernames() = %v, %v, want user1 and user2", users, err)
+	}
+	if len(users[0].Attributes) != 2 || users[0].Attributes[0].Key != "key1" || users[1].Attributes == nil || len(users[1].Attributes) != 0 {
+		t.Errorf("userRepository.FindByUsernames() attributes = %v and %v, want key1, key2 and none", users[0].Attributes, users[1].Attributes)
+	}
+	users, err = userRepository.FindByUuids(context.Background(), nil, []string{"unknown", "uuid2"}, entities.Preload("Attributes"))
+	if err != nil || len(users) != 1 || users[0].Username != "user2" || users[0].Attributes == nil {
+		t.Errorf("userRepository.FindByUuids() = %v, %v, want user2", users, err)
+	}
+	if _, err := userRepository.FindByUsername(context.Background(), nil, "user1", entities.Preload("Unknown")); !errors.Is(err, entities.ErrInvalid) {
+		t.Errorf("userRepository.FindByUsername() with an unknown association error = %v, want %v", err, entities.ErrInvalid)
+	}
+}
+
+func TestUserRepository_Tenants(t *testing.T) {
+	t.Parallel()
+
+	r := NewRepository()
+	userRepository := NewUserRepository(r)
+	userAttributeRepository := NewUserAttributeRepository(r, userRepository)
+	tenant2 := entities.InjectTenantIDToContext(context.Background(), 2)
+
+	// usernames are unique within a tenant
+	user, err := userRepository.Create(context.Background(), nil, &entities.User{Username: "user1"})
+	if err != nil || user.TenantID != entities.DefaultTenantID {
+		t.Fatalf("userRepository.Create() = %+v, %v", user, err)
+	}
+	other, err := userRepository.Create(tenant2, nil, &entities.User{Username: "user1", TenantID: 3})
+	if err != nil || other.TenantID != 2 {
+		t.Fatalf("userRepository.Create() = %+v, %v", other, err)
+	}
+	if _, err := userAttributeRepository.Create(tenant2, nil, &entities.UserAttribute{UserID: other.ID, Key: "key1"}); err != nil {
+		t.Fatalf("failed to create user attribute: %v", err)
+	}
+
+	if found, err := userRepository.FindByUsername(tenant2, nil, "user1"); err != nil || found.ID != other.ID {
+		t.Errorf("userRepository.FindByUsername() = %v, %v, want %v", found, err, other)
+	}
+	if _, err := userRepository.Get(tenant2, nil, user.ID); !errors.Is(err, entities.ErrNotFound) {
+		t.Errorf("userRepository.Get() error = %v, want %v", err, entities.ErrNotFound)
+	}
+	if err := userRepository.Delete(tenant2, nil, false, user.ID); err != nil {
+		t.Errorf("userRepository.Delete() error = %v", err)
+	}
+	if _, err := userRepository.Get(context.Background(), nil, user.ID); err != nil {
+		t.Errorf("the user of the default tenant is deleted from another tenant: %v", err)
+	}
+	if cnt, err := userAttributeRepository.CountByUserName(context.Background(), nil, "user1"); err != nil || cnt != 0 {
+		t.Errorf("userAttributeRepository.CountByUserName() = %v, %v, want 0", cnt, err)
+	}
+
+	all, err := userRepository.GetManyByCriterias(entities.WithAllTenants(context.Background()), nil, nil, nil, nil, 0, 0)
+	if err != nil || len(all) != 2 {
+		t.Errorf("userRepository.GetManyByCriterias() = %v, %v, want 2 users", all, err)
+	}
+}
+
+func TestOutboxRepository(t *testing.T) {
+	t.Parallel()
+
+	s := NewOutboxRepository(NewRepository())
+	if _, err := s.CreateMany(context.Background(), nil, []entities.OutboxMessage{
+		{Key: "user_created", Value: "user_id: 1"},
+		{Key: "user_created", Value: "user_id: 2"},
+		{Key: "user_created", Value: "user_id: 3"},
+	}); err != nil {
+		t.Fatalf("failed to create outbox messages: %v", err)
+	}
+
+	if err := s.MarkFailed(context.Background(), nil, 1, "fake error", false); err != nil {
+		t.Errorf("s.MarkFailed() error = %v", err)
+	}
+	if err := s.MarkFailed(context.Background(), nil, 3, "fake error", true); err != nil {
+		t.Errorf("s.MarkFailed() error = %v", err)
+	}
+	if err := s.MarkFailed(context.Background(), nil, 10, "fake error", false); !errors.Is(err, entities.ErrNotFound) {
+		t.Errorf("s.MarkFailed() error = %v, want %v", err, entities.ErrNotFound)
+	}
+	if err := s.MarkSent(context.Background(), nil, []uint{2}); err != nil {
+		t.Errorf("s.MarkSent() error = %v", err)
+	}
+
+	pending, err := s.GetPending(context.Background(), nil, 10)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("s.GetPending() = %v, %v, want 1 message", pending, err)
+	}
+	if pending[0].ID != 1 || pending[0].Attempts != 1 || pending[0].LastError != "fake error" {
+		t.Errorf("s.GetPending() = %+v", pending[0])
+	}
+}
@@ -4,24 +4,24 @@ import (
 	"context"
 
 	"github.com/tuantran1810/go-di-template/internal/entities"
-	"github.com/tuantran1810/go-di-template/internal/repositories/mysql"
 	"gorm.io/gorm"
 )
 
 type Message struct {
 	gorm.Model
-	Key   string
-	Value string
+	TenantID uint `gorm:"not null;default:1;index"`
+	Key      string
+	Value    string
 }
 
 type MessageRepository struct {
-	*mysql.GenericRepository[Message, entities.Message]
+	GenericRepository[Message, entities.Message]
 }
 
-func NewMessageRepository(repository *mysql.Repository) *MessageRepository {
+func NewMessageRepository(repository Database) *MessageRepository {
 	transformer := entities.NewBaseExtendedTransformer[Message, entities.Message]()
 	return &MessageRepository{
-		GenericRepository: mysql.NewGenericRepository(repository, transformer),
+		GenericRepository: NewGenericRepository(repository, transformer),
 	}
 }
 
@@ -3,15 +3,43 @@ package mysql
 import (
 	"context"
 	"fmt"
+	"iter"
+	"reflect"
 
 	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/aggregate"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 const DefaultLimit = 100
 
+// withLock adds the row lock of the options to a query, see entities.QueryOptions.
+func withLock(dbtx *gorm.DB, opts []entities.QueryOption) *gorm.DB {
+	options := entities.NewQueryOptions(opts...)
+	if options.Lock == entities.LockNone {
+		return dbtx
+	}
+
+	return dbtx.Clauses(clause.Locking{Strength: string(options.Lock), Options: string(options.LockWait)})
+}
+
+// withPreloads loads the associations of the options along with the records of a query, by a query per association,
+// the associated records in the order of their primary key.
+func withPreloads(dbtx *gorm.DB, opts []entities.QueryOption) *gorm.DB {
+	for _, association := range entities.NewQueryOptions(opts...).Preloads {
+		dbtx = dbtx.Preload(association, func(db *gorm.DB) *gorm.DB {
+			return db.Order(clause.OrderByColumn{Column: clause.PrimaryColumn})
+		})
+	}
+
+	return dbtx
+}
+
 type GenericRepository[T, E any] struct {
 	*Repository
-	transformer *entities.ExtendedDataTransformer[T, E]
+	transformer      *entities.ExtendedDataTransformer[T, E]
+	aggregateColumns []string
 }
 
 func NewGenericRepository[T, E any](
@@ -24,6 +52,30 @@ func NewGenericRepository[T, E any](
 	}
 }
 
+// withDeleted scopes a query to the soft deleted records of the options, see entities.QueryOptions.
+// Unscoped is inherited by the preloads, so they read the soft deleted associated records too.
+func (s *GenericRepository[T, E]) withDeleted(dbtx *gorm.DB, opts []entities.QueryOption) (*gorm.DB, error) {
+	switch entities.NewQueryOptions(opts...).Deleted {
+	case entities.DeletedScopeInclude:
+		return dbtx.Unscoped(), nil
+	case entities.DeletedScopeOnly:
+		var data T
+		stmt := &gorm.Statement{DB: dbtx}
+		if err := stmt.Parse(&data); err != nil {
+			return nil, GenerateError("failed to parse data", err)
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.FieldType == reflect.TypeFor[gorm.DeletedAt]() {
+				column := clause.Column{Table: clause.CurrentTable, Name: field.DBName}
+				return dbtx.Unscoped().Where(clause.Neq{Column: column, Value: nil}), nil
+			}
+		}
+		return nil, fmt.Errorf("%w - %s has no soft delete", entities.ErrInvalid, stmt.Schema.Name)
+	default:
+		return dbtx, nil
+	}
+}
+
 func (s *GenericRepository[T, E]) Ping(ctx context.Context) error {
 	var data T
 	dbtx := s.GetTransaction(nil).WithContext(ctx)
@@ -52,7 +104,7 @@ func (s *GenericRepository[T, E]) Create(
 	if err != nil {
 		return nil, err
 	}
-	dbtx := s.GetTransaction(tx).WithContext(ctx)
+	dbtx := s.GetContextTransaction(ctx, tx)
 	if err := dbtx.Create(data).Error; err != nil {
 		return nil, GenerateError("failed to create data", err)
 	}
@@ -74,20 +126,48 @@ func (s *GenericRepository[T, E]) CreateMany(
 		return nil, err
 	}
 
-	dbtx := s.GetTransaction(tx).WithContext(ctx)
-	if err := dbtx.Create(dataArray).Error; err != nil {
+	dbtx := s.GetContextTransaction(ctx, tx)
+	batchSize, err := s.insertBatchSize(dbtx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the batches are inserted in a transaction, the IDs are set on dataArray in place
+	if err := dbtx.CreateInBatches(dataArray, batchSize).Error; err != nil {
 		return nil, GenerateError("failed to create data records", err)
 	}
 
 	return s.transformer.ToEntityArray_I2I(dataArray)
 }
 
+// insertBatchSize is the number of rows of T inserted per statement, the CreateBatchSize of the repository
+// within the placeholders of a statement, a placeholder per column.
+func (s *GenericRepository[T, E]) insertBatchSize(dbtx *gorm.DB) (int, error) {
+	var data T
+	stmt := &gorm.Statement{DB: dbtx}
+	if err := stmt.Parse(&data); err != nil {
+		return 0, fmt.Errorf("%w - failed to parse model: %w", entities.ErrInternal, err)
+	}
+
+	batchSize := max(maxPlaceholders/max(len(stmt.Schema.DBNames), 1), 1)
+	if s.CreateBatchSize > 0 {
+		batchSize = min(batchSize, s.CreateBatchSize)
+	}
+
+	return batchSize, nil
+}
+
 func (s *GenericRepository[T, E]) Get(
 	ctx context.Context,
 	tx entities.Transaction,
 	id uint,
+	opts ...entities.QueryOption,
 ) (*E, error) {
-	dbtx := s.GetTransaction(tx).WithContext(ctx)
+	dbtx, err := s.withDeleted(s.GetContextTransaction(ctx, tx), opts)
+	if err != nil {
+		return nil, err
+	}
+	dbtx = withPreloads(withLock(dbtx, opts), opts)
 	var data T
 	if err := dbtx.First(&data, id).Error; err != nil {
 		return nil, GenerateError("failed to get data", err)
@@ -100,12 +180,17 @@ func (s *GenericRepository[T, E]) GetMany(
 	ctx context.Context,
 	tx entities.Transaction,
 	ids []uint,
+	opts ...entities.QueryOption,
 ) ([]E, error) {
 	if len(ids) == 0 {
 		return nil, fmt.Errorf("%w - input ids is empty", entities.ErrInvalid)
 	}
 
-	dbtx := s.GetTransaction(tx).WithContext(ctx)
+	dbtx, err := s.withDeleted(s.GetContextTransaction(ctx, tx), opts)
+	if err != nil {
+		return nil, err
+	}
+	dbtx = withPreloads(dbtx, opts)
 	var dataArray []T
 	if err := dbtx.Find(&dataArray, ids).Error; err != nil {
 		return nil, GenerateError("failed to get records", err)
@@ -120,11 +205,14 @@ func (s *GenericRepository[T, E]) GetByCriterias(
 	fields []string,
 	criterias map[string]any,
 	orderBys []string,
+	opts ...entities.QueryOption,
 ) (*E, error) {
 	var data T
-	dbtx := s.
-		GetTransaction(tx).
-		WithContext(ctx)
+	dbtx, err := s.withDeleted(s.GetContextTransaction(ctx, tx), opts)
+	if err != nil {
+		return nil, err
+	}
+	dbtx = withPreloads(withLock(dbtx, opts), opts)
 	if len(fields) > 0 {
 		dbtx = dbtx.Select(fields)
 	}
@@ -154,8 +242,13 @@ func (s *GenericRepository[T, E]) GetManyByCriterias(
 	orderBys []string,
 	offset int,
 	limit int,
+	opts ...entities.QueryOption,
 ) ([]E, error) {
-	dbtx := s.GetTransaction(tx).WithContext(ctx)
+	dbtx, err := s.withDeleted(s.GetContextTransaction(ctx, tx), opts)
+	if err != nil {
+		return nil, err
+	}
+	dbtx = withPreloads(withLock(dbtx, opts), opts)
 
 	for k, v := range criterias {
 		if v == nil {
@@ -184,12 +277,109 @@ func (s *GenericRepository[T, E]) GetManyByCriterias(
 	return s.transformer.ToEntityArray_I2I(dataArray)
 }
 
+// FindInBatches walks the records matching the criterias by batches of batchSize, DefaultLimit when not positive,
+// ordered by primary key. Each batch is read by its own query, keyed by the last primary key of the previous one,
+// so the records written meanwhile may be seen or not. The iteration stops at the first error, which is yielded,
+// e.g. when ctx is canceled.
+func (s *GenericRepository[T, E]) FindInBatches(
+	ctx context.Context,
+	tx entities.Transaction,
+	criterias map[string]any,
+	batchSize int,
+) iter.Seq2[[]E, error] {
+	if batchSize <= 0 {
+		batchSize = DefaultLimit
+	}
+
+	return func(yield func([]E, error) bool) {
+		var cursor any
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, fmt.Errorf("%w - failed to find data in batches: %w", entities.ErrCanceled, err))
+				return
+			}
+
+			dataArray, next, err := s.findBatch(ctx, tx, criterias, cursor, batchSize)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(dataArray) == 0 {
+				return
+			}
+
+			batch, err := s.transformer.ToEntityArray_I2I(dataArray)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(batch, nil) || len(dataArray) < batchSize {
+				return
+			}
+			cursor = next
+		}
+	}
+}
+
+// findBatch reads the batch of records following the cursor, the primary key of the last record of the previous batch,
+// and returns the cursor of the next batch.
+func (s *GenericRepository[T, E]) findBatch(
+	ctx context.Context,
+	tx entities.Transaction,
+	criterias map[string]any,
+	cursor any,
+	batchSize int,
+) ([]T, any, error) {
+	dbtx := s.GetContextTransaction(ctx, tx)
+	for k, v := range criterias {
+		if v == nil {
+			dbtx = dbtx.Where(k)
+		} else {
+			dbtx = dbtx.Where(k, v)
+		}
+	}
+
+	var data T
+	stmt := &gorm.Statement{DB: dbtx}
+	if err := stmt.Parse(&data); err != nil {
+		return nil, nil, GenerateError("failed to parse data", err)
+	}
+	primary := stmt.Schema.PrioritizedPrimaryField
+	if primary == nil {
+		return nil, nil, fmt.Errorf("%w - %s has no primary key", entities.ErrInternal, stmt.Schema.Name)
+	}
+
+	column := clause.Column{Table: clause.CurrentTable, Name: primary.DBName}
+	if cursor != nil {
+		dbtx = dbtx.Where(clause.Gt{Column: column, Value: cursor})
+	}
+
+	var dataArray []T
+	if err := dbtx.
+		Order(clause.OrderByColumn{Column: column}).
+		Limit(batchSize).
+		Find(&dataArray).
+		Error; err != nil {
+		return nil, nil, GenerateError("failed to find data in batches", err)
+	}
+	if len(dataArray) == 0 {
+		return nil, nil, nil
+	}
+
+	next := primary.ReflectValueOf(ctx, reflect.ValueOf(&dataArray[len(dataArray)-1])).Interface()
+	return dataArray, next, nil
+}
+
 func (s *GenericRepository[T, E]) Count(
 	ctx context.Context,
 	tx entities.Transaction,
 	criterias map[string]any,
+	opts ...entities.QueryOption,
 ) (int64, error) {
-	dbtx := s.GetTransaction(tx).WithContext(ctx)
+	dbtx, err := s.withDeleted(s.GetContextTransaction(ctx, tx), opts)
+	if err != nil {
+		return 0, err
+	}
 
 	for k, v := range criterias {
 		if v == nil {
@@ -208,6 +398,51 @@ func (s *GenericRepository[T, E]) Count(
 	return cnt, nil
 }
 
+// WithAggregateColumns allows Aggregate to group by and aggregate the columns.
+func (s *GenericRepository[T, E]) WithAggregateColumns(columns ...string) *GenericRepository[T, E] {
+	s.aggregateColumns = append(s.aggregateColumns, columns...)
+	return s
+}
+
+// bucketOf truncates a DATETIME column, stored in UTC, to the start of its time bucket.
+func bucketOf(column string, bucket entities.TimeBucket) string {
+	switch bucket {
+	case entities.TimeBucketWeek:
+		return fmt.Sprintf("DATE_SUB(DATE(%[1]s), INTERVAL WEEKDAY(%[1]s) DAY)", column)
+	case entities.TimeBucketMonth:
+		return fmt.Sprintf("DATE_SUB(DATE(%[1]s), INTERVAL DAYOFMONTH(%[1]s) - 1 DAY)", column)
+	default:
+		return fmt.Sprintf("DATE(%s)", column)
+	}
+}
+
+// Aggregate groups the records matching the criterias of the query and aggregates each group,
+// the rows are ordered by the groups unless query.OrderByValue. The grouped and aggregated columns
+// must be allowed by WithAggregateColumns.
+func (s *GenericRepository[T, E]) Aggregate(
+	ctx context.Context,
+	tx entities.Transaction,
+	query entities.AggregateQuery,
+) ([]entities.AggregateRow, error) {
+	var data T
+	dbtx, err := aggregate.Build(s.GetContextTransaction(ctx, tx).Model(&data), s.aggregateColumns, query, bucketOf)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := dbtx.Rows()
+	if err != nil {
+		return nil, GenerateError("failed to aggregate data", err)
+	}
+
+	out, err := aggregate.Scan(rows, query)
+	if err != nil {
+		return nil, GenerateError("failed to aggregate data", err)
+	}
+
+	return out, nil
+}
+
 func (s *GenericRepository[T, E]) Update(
 	ctx context.Context,
 	tx entities.Transaction,
@@ -222,7 +457,7 @@ func (s *GenericRepository[T, E]) Update(
 		return err
 	}
 
-	dbtx := s.GetTransaction(tx).WithContext(ctx)
+	dbtx := s.GetContextTransaction(ctx, tx)
 	dbtx = dbtx.Updates(data)
 	if err := dbtx.Error; err != nil {
 		return GenerateError("failed to update data", err)
@@ -240,7 +475,7 @@ func (s *GenericRepository[T, E]) Delete(
 	permanent bool,
 	id uint,
 ) error {
-	dbtx := s.GetTransaction(tx).WithContext(ctx)
+	dbtx := s.GetContextTransaction(ctx, tx)
 	if permanent {
 		dbtx = dbtx.Unscoped()
 	}
@@ -265,7 +500,7 @@ func (s *GenericRepository[T, E]) DeleteMany(
 		return 0, fmt.Errorf("%w - input ids is empty", entities.ErrInvalid)
 	}
 
-	dbtx := s.GetTransaction(tx).WithContext(ctx)
+	dbtx := s.GetContextTransaction(ctx, tx)
 	if permanent {
 		dbtx = dbtx.Unscoped()
 	}
@@ -3,9 +3,11 @@ package mysql
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
+	"slices"
 	"testing"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/mysql"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"github.com/tuantran1810/go-di-template/internal/entities"
+	gormMysql "gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
@@ -1193,6 +1196,391 @@ func (s *GenericDataTestSuite) TestGenericRepository_Transaction() {
 	}
 }
 
+func (s *GenericDataTestSuite) TestGenericRepository_DeletedScope() {
+	t := s.T()
+	ctx := context.Background()
+
+	deleted := s.initData[0]
+	if err := s.store.Delete(ctx, nil, false, deleted.ID); err != nil {
+		t.Errorf("failed to delete data: %v", err)
+		return
+	}
+	allIDs := []uint{s.initData[0].ID, s.initData[1].ID, s.initData[2].ID}
+	// idsOf returns the ids in order, GetMany has no order
+	idsOf := func(dataArray []DataEntity) []uint {
+		ids := make([]uint, 0, len(dataArray))
+		for _, data := range dataArray {
+			ids = append(ids, data.ID)
+		}
+		slices.Sort(ids)
+		return ids
+	}
+
+	tests := []struct {
+		name    string
+		opts    []entities.QueryOption
+		wantIDs []uint
+	}{
+		{
+			name:    "active only",
+			wantIDs: allIDs[1:],
+		},
+		{
+			name:    "include deleted",
+			opts:    []entities.QueryOption{entities.WithDeleted()},
+			wantIDs: allIDs,
+		},
+		{
+			name:    "only deleted",
+			opts:    []entities.QueryOption{entities.OnlyDeleted()},
+			wantIDs: allIDs[:1],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantDeleted := slices.Contains(tt.wantIDs, deleted.ID)
+
+			if _, err := s.store.Get(ctx, nil, deleted.ID, tt.opts...); (err == nil) != wantDeleted {
+				t.Errorf("store.Get() error = %v, want deleted record %v", err, wantDeleted)
+			}
+			_, err := s.store.GetByCriterias(ctx, nil, nil, map[string]any{"unique_id": deleted.UniqueID}, nil, tt.opts...)
+			if (err == nil) != wantDeleted {
+				t.Errorf("store.GetByCriterias() error = %v, want deleted record %v", err, wantDeleted)
+			}
+
+			many, err := s.store.GetMany(ctx, nil, allIDs, tt.opts...)
+			if err != nil {
+				t.Errorf("store.GetMany() error = %v", err)
+			} else if got := idsOf(many); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("store.GetMany() ids = %v, want %v", got, tt.wantIDs)
+			}
+
+			all, err := s.store.GetManyByCriterias(ctx, nil, nil, nil, []string{"id"}, 0, 0, tt.opts...)
+			if err != nil {
+				t.Errorf("store.GetManyByCriterias() error = %v", err)
+			} else if got := idsOf(all); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("store.GetManyByCriterias() ids = %v, want %v", got, tt.wantIDs)
+			}
+
+			count, err := s.store.Count(ctx, nil, map[string]any{"id IN ?": allIDs}, tt.opts...)
+			if err != nil || count != int64(len(tt.wantIDs)) {
+				t.Errorf("store.Count() = %d, %v, want %d", count, err, len(tt.wantIDs))
+			}
+		})
+	}
+}
+
+func (s *GenericDataTestSuite) TestGenericRepository_FindInBatches() {
+	t := s.T()
+
+	tests := []struct {
+		name      string
+		criterias map[string]any
+		batchSize int
+		want      [][]uint
+	}{
+		{
+			name:      "batches of 2",
+			criterias: nil,
+			batchSize: 2,
+			want:      [][]uint{{1, 2}, {3}},
+		},
+		{
+			name:      "exact batches",
+			criterias: nil,
+			batchSize: 3,
+			want:      [][]uint{{1, 2, 3}},
+		},
+		{
+			name:      "default batch size",
+			criterias: nil,
+			batchSize: 0,
+			want:      [][]uint{{1, 2, 3}},
+		},
+		{
+			name: "criterias",
+			criterias: map[string]any{
+				"key IN ?": []string{"key1", "key3"},
+			},
+			batchSize: 1,
+			want:      [][]uint{{1}, {3}},
+		},
+		{
+			name: "no records",
+			criterias: map[string]any{
+				"key": "key1000",
+			},
+			batchSize: 1,
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]uint
+			for batch, err := range s.store.FindInBatches(context.Background(), nil, tt.criterias, tt.batchSize) {
+				if err != nil {
+					t.Errorf("store.FindInBatches() error = %v", err)
+					return
+				}
+				ids := make([]uint, 0, len(batch))
+				for _, entity := range batch {
+					ids = append(ids, entity.ID)
+				}
+				got = append(got, ids)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("store.FindInBatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// the records can be written while iterating, and the iteration stops on break
+	batches := 0
+	for batch, err := range s.store.FindInBatches(context.Background(), nil, nil, 1) {
+		s.Require().NoError(err)
+		batch[0].Value = "updated"
+		s.Require().NoError(s.store.Update(context.Background(), nil, &batch[0]))
+		batches++
+		if batches == 2 {
+			break
+		}
+	}
+	s.Require().Equal(2, batches)
+	updated, err := s.store.Count(context.Background(), nil, map[string]any{"value": "updated"})
+	s.Require().NoError(err)
+	s.Require().Equal(int64(2), updated)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	batches = 0
+	for _, err := range s.store.FindInBatches(ctx, nil, nil, 1) {
+		if err != nil {
+			s.Require().ErrorIs(err, entities.ErrCanceled)
+			break
+		}
+		batches++
+		cancel()
+	}
+	s.Require().Equal(1, batches)
+}
+
+func (s *GenericDataTestSuite) TestGenericRepository_Aggregate() {
+	t := s.T()
+	s.store.WithAggregateColumns("id", "key", "created_at")
+
+	day := func(d int) time.Time {
+		return time.Date(2025, time.January, d, 0, 0, 0, 0, time.UTC)
+	}
+	if _, err := s.store.CreateMany(context.Background(), nil, []DataEntity{
+		// Monday, Tuesday and Sunday of the same week, then Monday of the next one
+		{CreatedAt: day(6).Add(10 * time.Hour), UniqueID: "unique-id-4", Key: "key1"},
+		{CreatedAt: day(7).Add(23 * time.Hour), UniqueID: "unique-id-5", Key: "key1"},
+		{CreatedAt: day(12).Add(time.Hour), UniqueID: "unique-id-6", Key: "key2"},
+		{CreatedAt: day(13), UniqueID: "unique-id-7", Key: "key1"},
+	}); err != nil {
+		t.Errorf("failed to create data: %v", err)
+		return
+	}
+	january := map[string]any{
+		"created_at >= ?": day(1),
+		"created_at < ?":  day(1).AddDate(0, 1, 0),
+	}
+	count := []entities.Aggregate{{Func: entities.AggregateCount}}
+
+	tests := []struct {
+		name    string
+		query   entities.AggregateQuery
+		want    []entities.AggregateRow
+		wantErr error
+	}{
+		{
+			name: "count by key",
+			query: entities.AggregateQuery{
+				GroupBys:   []entities.GroupBy{{Column: "key"}},
+				Aggregates: count,
+			},
+			want: []entities.AggregateRow{
+				{Groups: []any{"key1"}, Values: []float64{4}},
+				{Groups: []any{"key2"}, Values: []float64{2}},
+				{Groups: []any{"key3"}, Values: []float64{1}},
+			},
+		},
+		{
+			name: "order by value",
+			query: entities.AggregateQuery{
+				GroupBys:     []entities.GroupBy{{Column: "key"}},
+				Aggregates:   count,
+				Criterias:    map[string]any{"key != ?": "key1"},
+				OrderByValue: true,
+				Limit:        1,
+			},
+			want: []entities.AggregateRow{
+				{Groups: []any{"key2"}, Values: []float64{2}},
+			},
+		},
+		{
+			name: "sum min max",
+			query: entities.AggregateQuery{
+				Aggregates: []entities.Aggregate{
+					{Func: entities.AggregateSum, Column: "id"},
+					{Func: entities.AggregateMin, Column: "id"},
+					{Func: entities.AggregateMax, Column: "id"},
+					{Func: entities.AggregateCount, Column: "id"},
+				},
+			},
+			want: []entities.AggregateRow{
+				{Groups: []any{}, Values: []float64{28, 1, 7, 7}},
+			},
+		},
+		{
+			name: "no records",
+			query: entities.AggregateQuery{
+				Aggregates: []entities.Aggregate{{Func: entities.AggregateSum, Column: "id"}},
+				Criterias:  map[string]any{"key": "key1000"},
+			},
+			want: []entities.AggregateRow{
+				{Groups: []any{}, Values: []float64{0}},
+			},
+		},
+		{
+			name: "per day",
+			query: entities.AggregateQuery{
+				GroupBys:   []entities.GroupBy{{Column: "created_at", Bucket: entities.TimeBucketDay}},
+				Aggregates: count,
+				Criterias:  january,
+			},
+			want: []entities.AggregateRow{
+				{Groups: []any{day(6)}, Values: []float64{1}},
+				{Groups: []any{day(7)}, Values: []float64{1}},
+				{Groups: []any{day(12)}, Values: []float64{1}},
+				{Groups: []any{day(13)}, Values: []float64{1}},
+			},
+		},
+		{
+			name: "per week and key",
+			query: entities.AggregateQuery{
+				GroupBys: []entities.GroupBy{
+					{Column: "created_at", Bucket: entities.TimeBucketWeek},
+					{Column: "key"},
+				},
+				Aggregates: count,
+				Criterias:  january,
+			},
+			want: []entities.AggregateRow{
+				{Groups: []any{day(6), "key1"}, Values: []float64{2}},
+				{Groups: []any{day(6), "key2"}, Values: []float64{1}},
+				{Groups: []any{day(13), "key1"}, Values: []float64{1}},
+			},
+		},
+		{
+			name: "per month",
+			query: entities.AggregateQuery{
+				GroupBys:   []entities.GroupBy{{Column: "created_at", Bucket: entities.TimeBucketMonth}},
+				Aggregates: count,
+				Criterias:  january,
+			},
+			want: []entities.AggregateRow{
+				{Groups: []any{day(1)}, Values: []float64{4}},
+			},
+		},
+		{
+			name:    "no aggregate",
+			query:   entities.AggregateQuery{GroupBys: []entities.GroupBy{{Column: "key"}}},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name: "column not allowed",
+			query: entities.AggregateQuery{
+				GroupBys:   []entities.GroupBy{{Column: "value"}},
+				Aggregates: count,
+			},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name: "unknown bucket",
+			query: entities.AggregateQuery{
+				GroupBys:   []entities.GroupBy{{Column: "created_at", Bucket: "year"}},
+				Aggregates: count,
+			},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name: "unknown aggregate",
+			query: entities.AggregateQuery{
+				Aggregates: []entities.Aggregate{{Func: "avg", Column: "id"}},
+			},
+			wantErr: entities.ErrInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.store.Aggregate(context.Background(), nil, tt.query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("store.Aggregate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("store.Aggregate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_withLock(t *testing.T) {
+	t.Parallel()
+
+	db, err := gorm.Open(gormMysql.New(gormMysql.Config{DSN: "root:secret@tcp(127.0.0.1:3306)/test", SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("failed to open dry run database: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts []entities.QueryOption
+		want string
+	}{
+		{
+			name: "no lock",
+			opts: nil,
+			want: "SELECT * FROM `data` WHERE `key` = 'key' AND `data`.`deleted_at` IS NULL",
+		},
+		{
+			name: "wait without lock",
+			opts: []entities.QueryOption{entities.NoWait()},
+			want: "SELECT * FROM `data` WHERE `key` = 'key' AND `data`.`deleted_at` IS NULL",
+		},
+		{
+			name: "for update",
+			opts: []entities.QueryOption{entities.ForUpdate()},
+			want: "SELECT * FROM `data` WHERE `key` = 'key' AND `data`.`deleted_at` IS NULL" + " FOR UPDATE",
+		},
+		{
+			name: "for share nowait",
+			opts: []entities.QueryOption{entities.ForShare(), entities.NoWait()},
+			want: "SELECT * FROM `data` WHERE `key` = 'key' AND `data`.`deleted_at` IS NULL" + " FOR SHARE NOWAIT",
+		},
+		{
+			name: "for update skip locked",
+			opts: []entities.QueryOption{entities.ForUpdate(), entities.SkipLocked()},
+			want: "SELECT * FROM `data` WHERE `key` = 'key' AND `data`.`deleted_at` IS NULL" + " FOR UPDATE SKIP LOCKED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				var data Data
+				return withLock(tx, tt.opts).Where("`key` = ?", "key").Find(&data)
+			})
+			if got != tt.want {
+				t.Errorf("withLock() SQL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGenericDataTestSuite(t *testing.T) {
 	suite.Run(t, new(GenericDataTestSuite))
 }
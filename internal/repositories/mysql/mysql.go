@@ -5,31 +5,81 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	goMysql "github.com/go-sql-driver/mysql"
 	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/audit"
+	"github.com/tuantran1810/go-di-template/internal/repositories/connect"
+	"github.com/tuantran1810/go-di-template/internal/repositories/dbmetrics"
+	"github.com/tuantran1810/go-di-template/internal/repositories/dbtls"
+	"github.com/tuantran1810/go-di-template/internal/repositories/encryption"
+	"github.com/tuantran1810/go-di-template/internal/repositories/tenancy"
 	"github.com/tuantran1810/go-di-template/libs/logger"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 var log = logger.MustNamedLogger("mysql")
 
+// maxPlaceholders is the maximum number of placeholders of a statement, the limit of the MySQL protocol.
+const maxPlaceholders = 65535
+
 func isInvalidInputError(err error) bool {
 	if err == nil {
 		return false
 	}
 
-	if strings.Contains(err.Error(), "constraint") {
-		return true
-	}
-
 	return errors.Is(err, gorm.ErrInvalidData) ||
 		errors.Is(err, gorm.ErrInvalidField) ||
 		errors.Is(err, gorm.ErrInvalidValue) ||
-		errors.Is(err, gorm.ErrInvalidValueOfLength)
+		errors.Is(err, gorm.ErrInvalidValueOfLength) ||
+		errors.Is(err, gorm.ErrForeignKeyViolated) ||
+		errors.Is(err, gorm.ErrCheckConstraintViolated)
+}
+
+// mysqlErrors are the entities errors of the MySQL server error numbers.
+var mysqlErrors = map[uint16]error{
+	1022: entities.ErrConflicted,      // ER_DUP_KEY
+	1062: entities.ErrConflicted,      // ER_DUP_ENTRY
+	1586: entities.ErrConflicted,      // ER_DUP_ENTRY_WITH_KEY_NAME
+	1451: entities.ErrInvalid,         // ER_ROW_IS_REFERENCED_2
+	1452: entities.ErrInvalid,         // ER_NO_REFERENCED_ROW_2
+	1048: entities.ErrInvalid,         // ER_BAD_NULL_ERROR
+	1264: entities.ErrInvalid,         // ER_WARN_DATA_OUT_OF_RANGE
+	1292: entities.ErrInvalid,         // ER_TRUNCATED_WRONG_VALUE
+	1364: entities.ErrInvalid,         // ER_NO_DEFAULT_FOR_FIELD
+	1366: entities.ErrInvalid,         // ER_TRUNCATED_WRONG_VALUE_FOR_FIELD
+	1406: entities.ErrInvalid,         // ER_DATA_TOO_LONG
+	3819: entities.ErrInvalid,         // ER_CHECK_CONSTRAINT_VIOLATED
+	1040: entities.ErrTooManyRequests, // ER_CON_COUNT_ERROR
+	1203: entities.ErrTooManyRequests, // ER_TOO_MANY_USER_CONNECTIONS
+	1205: entities.ErrLocked,          // ER_LOCK_WAIT_TIMEOUT
+	1213: entities.ErrLocked,          // ER_LOCK_DEADLOCK
+	3572: entities.ErrLocked,          // ER_LOCK_NOWAIT
+	1054: entities.ErrMalformed,       // ER_BAD_FIELD_ERROR
+	1064: entities.ErrMalformed,       // ER_PARSE_ERROR
+	1146: entities.ErrMalformed,       // ER_NO_SUCH_TABLE
+	1317: entities.ErrCanceled,        // ER_QUERY_INTERRUPTED
+	3024: entities.ErrCanceled,        // ER_QUERY_TIMEOUT
+}
+
+// getDriverError returns the entities error of a MySQL server error, nil for the other errors.
+func getDriverError(err error) error {
+	var mysqlErr *goMysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return nil
+	}
+
+	if entityErr, ok := mysqlErrors[mysqlErr.Number]; ok {
+		return entityErr
+	}
+
+	return entities.ErrDatabase
 }
 
 func isNotFoundError(err error) bool {
@@ -63,6 +113,14 @@ func getEntityError(err error) error {
 		return entities.ErrCanceled
 	}
 
+	if driverErr := getDriverError(err); driverErr != nil {
+		return driverErr
+	}
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return entities.ErrConflicted
+	}
+
 	if isInvalidInputError(err) {
 		return entities.ErrInvalid
 	}
@@ -89,6 +147,10 @@ func handleTransactionError(err error) error {
 
 	eligibleErr := errors.Is(err, entities.ErrCanceled) ||
 		errors.Is(err, entities.ErrInvalid) ||
+		errors.Is(err, entities.ErrConflicted) ||
+		errors.Is(err, entities.ErrMalformed) ||
+		errors.Is(err, entities.ErrTooManyRequests) ||
+		errors.Is(err, entities.ErrLocked) ||
 		errors.Is(err, entities.ErrNotFound) ||
 		errors.Is(err, entities.ErrDatabase)
 	if eligibleErr {
@@ -119,7 +181,11 @@ type RepositoryConfig struct {
 	Params    map[string]string
 	Collation string
 	Loc       *time.Location
+	// TLSConfig is the name of a TLS config registered in the driver, or true, false, skip-verify or preferred.
+	// It is the name TLS is registered with when enabled, a name of the database when empty.
 	TLSConfig string
+	// TLS is the TLS of the connections, with the certificates reloaded once their files change
+	TLS dbtls.Config
 
 	Timeout      time.Duration
 	ReadTimeout  time.Duration
@@ -137,6 +203,18 @@ type RepositoryConfig struct {
 	MaxOpenConns           uint32
 	MaxIdleConns           uint32
 	ConnMaxLifeTimeSeconds uint32
+
+	// Connect is the retry of the connection when the repository starts
+	Connect connect.Config
+
+	// CreateBatchSize is the maximum number of rows inserted per statement by CreateMany,
+	// as many as the placeholders of a statement allow when 0
+	CreateBatchSize int
+
+	// Logger replaces the default GORM logger when set
+	Logger gormlogger.Interface
+	// Keyring encrypts the tagged fields, they are stored as they are when nil
+	Keyring *encryption.Keyring
 }
 
 func (cfg RepositoryConfig) DSN() string {
@@ -149,7 +227,7 @@ func (cfg RepositoryConfig) DSN() string {
 		Params:                  cfg.Params,
 		Collation:               cfg.Collation,
 		Loc:                     cfg.Loc,
-		TLSConfig:               cfg.TLSConfig,
+		TLSConfig:               cfg.tlsConfigName(),
 		Timeout:                 cfg.Timeout,
 		ReadTimeout:             cfg.ReadTimeout,
 		WriteTimeout:            cfg.WriteTimeout,
@@ -167,9 +245,40 @@ func (cfg RepositoryConfig) DSN() string {
 	return mysqlConfig.FormatDSN()
 }
 
+func (cfg RepositoryConfig) tlsConfigName() string {
+	if cfg.TLS.Enabled && cfg.TLSConfig == "" {
+		return fmt.Sprintf("mysql:%s/%s", cfg.Address, cfg.Database)
+	}
+
+	return cfg.TLSConfig
+}
+
+// registerTLS registers the TLS config of the repository in the driver, under the name of its DSN.
+func (cfg RepositoryConfig) registerTLS() error {
+	if !cfg.TLS.Enabled {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(cfg.Address)
+	if err != nil {
+		host = cfg.Address
+	}
+	tlsConfig, err := dbtls.Load(cfg.TLS, host)
+	if err != nil {
+		return err
+	}
+	if err := goMysql.RegisterTLSConfig(cfg.tlsConfigName(), tlsConfig); err != nil {
+		return fmt.Errorf("%w - failed to register tls config: %w", entities.ErrInvalid, err)
+	}
+
+	return nil
+}
+
 type Repository struct {
 	RepositoryConfig
-	db *gorm.DB
+	db                *gorm.DB
+	connected         atomic.Bool
+	unregisterMetrics func()
 }
 
 func MustNewRepository(cfg RepositoryConfig) *Repository {
@@ -178,36 +287,84 @@ func MustNewRepository(cfg RepositoryConfig) *Repository {
 	}
 }
 
-func (r *Repository) Start(ctx context.Context) error {
-	log.Info("starting mysql repository")
+// open opens the pool of connections to the database and pings it, the pool is closed when the ping fails.
+func (r *Repository) open(ctx context.Context) (*gorm.DB, error) {
 	dsn := r.DSN()
 	db, err := gorm.Open(
 		mysql.Open(dsn),
-		&gorm.Config{},
+		&gorm.Config{Logger: r.Logger, DisableAutomaticPing: true},
 	)
 	if err != nil {
-		return fmt.Errorf("%w - failed to open database: %w", entities.ErrDatabase, err)
+		return nil, fmt.Errorf("%w - failed to open database: %w", entities.ErrDatabase, err)
 	}
 
 	dbInstance, err := db.DB()
 	if err != nil {
-		return fmt.Errorf("%w - failed to get database instance: %w", entities.ErrDatabase, err)
+		return nil, fmt.Errorf("%w - failed to get database instance: %w", entities.ErrDatabase, err)
 	}
 
 	dbInstance.SetMaxOpenConns(int(r.MaxOpenConns))
 	dbInstance.SetMaxIdleConns(int(r.MaxIdleConns))
 	dbInstance.SetConnMaxLifetime(time.Duration(r.ConnMaxLifeTimeSeconds) * time.Second)
 
-	if err := dbInstance.Ping(); err != nil {
-		return fmt.Errorf("%w - failed to ping database: %w", entities.ErrDatabase, err)
+	if err := dbInstance.PingContext(ctx); err != nil {
+		_ = dbInstance.Close()
+		return nil, fmt.Errorf("%w - failed to ping database: %w", entities.ErrDatabase, err)
+	}
+
+	return db, nil
+}
+
+func (r *Repository) Start(ctx context.Context) error {
+	log.Info("starting mysql repository")
+	if err := r.registerTLS(); err != nil {
+		return err
+	}
+
+	var db *gorm.DB
+	err := connect.Retry(ctx, r.Connect, fmt.Sprintf("mysql:%s", r.Database), func(ctx context.Context) error {
+		var err error
+		db, err = r.open(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	dbInstance, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("%w - failed to get database instance: %w", entities.ErrDatabase, err)
+	}
+
+	if err := db.Use(encryption.NewPlugin(r.Keyring)); err != nil {
+		return fmt.Errorf("%w - failed to install encryption plugin: %w", entities.ErrDatabase, err)
+	}
+
+	if err := db.Use(tenancy.NewPlugin()); err != nil {
+		return fmt.Errorf("%w - failed to install tenancy plugin: %w", entities.ErrDatabase, err)
+	}
+
+	if err := db.Use(audit.NewPlugin(maxPlaceholders)); err != nil {
+		return fmt.Errorf("%w - failed to install audit plugin: %w", entities.ErrDatabase, err)
+	}
+
+	unregisterMetrics, err := dbmetrics.Instrument(db, dbInstance, fmt.Sprintf("%s:%s", "mysql", r.Database), getEntityError)
+	if err != nil {
+		return err
 	}
 
 	r.db = db
+	r.connected.Store(true)
+	r.unregisterMetrics = unregisterMetrics
 	return r.Check(ctx)
 }
 
 func (r *Repository) Stop(_ context.Context) error {
 	log.Info("stopping mysql repository")
+	if r.unregisterMetrics != nil {
+		r.unregisterMetrics()
+	}
+
 	db, err := r.db.DB()
 	if err != nil {
 		return fmt.Errorf("%w - failed to get database connection: %w", entities.ErrDatabase, err)
@@ -220,8 +377,18 @@ func (r *Repository) Stop(_ context.Context) error {
 	return nil
 }
 
+// Check pings the database, for the health checks. The pool of connections replaces the broken ones by itself,
+// so a lost connection is reported until the database is reachable again, without restarting the repository.
 func (r *Repository) Check(ctx context.Context) error {
-	if err := r.db.WithContext(ctx).Exec("SELECT 1").Error; err != nil {
+	err := r.db.WithContext(ctx).Exec("SELECT 1").Error
+	if connected := err == nil; r.connected.Swap(connected) != connected {
+		if connected {
+			log.Infow("database connection re-established", "database", r.Database)
+		} else {
+			log.Warnw("database connection lost", "database", r.Database, "error", err)
+		}
+	}
+	if err != nil {
 		return GenerateError("failed to ping database", err)
 	}
 
@@ -232,6 +399,11 @@ func (r *Repository) DB() *gorm.DB {
 	return r.db
 }
 
+// GenerateError is GenerateError, for the callers holding the repository behind an interface.
+func (r *Repository) GenerateError(errStr string, err error) error {
+	return GenerateError(errStr, err)
+}
+
 func (r *Repository) GetTransaction(tx entities.Transaction) *gorm.DB {
 	if tx == nil {
 		return r.db
@@ -245,17 +417,30 @@ func (r *Repository) GetTransaction(tx entities.Transaction) *gorm.DB {
 	return txImpl.(*gorm.DB)
 }
 
+// GetContextTransaction resolves the database handle for a repository call bound to ctx.
+// An explicit tx takes precedence, otherwise the transaction carried by ctx is joined.
+func (r *Repository) GetContextTransaction(ctx context.Context, tx entities.Transaction) *gorm.DB {
+	if tx == nil {
+		tx = entities.GetTransactionFromContext(ctx)
+	}
+
+	return r.GetTransaction(tx).WithContext(ctx)
+}
+
 func (r *Repository) RunTx(ctx context.Context, funcs ...entities.DBTxHandleFunc) error {
 	if len(funcs) == 0 {
 		return fmt.Errorf("%w - input no handler function", entities.ErrInternal)
 	}
 
-	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	ctx, runCommitHooks := entities.WithCommitHooks(ctx)
+	// a transaction already carried by ctx is continued with a nested savepoint
+	err := r.GetContextTransaction(ctx, nil).Transaction(func(tx *gorm.DB) error {
 		txKeeper := NewGormTransaction(tx)
+		txCtx := entities.InjectTransactionToContext(ctx, txKeeper)
 
 		for _, f := range funcs {
 			if f != nil {
-				ferr := f(ctx, txKeeper)
+				ferr := f(txCtx, txKeeper)
 				if ferr != nil {
 					return ferr
 				}
@@ -264,6 +449,7 @@ func (r *Repository) RunTx(ctx context.Context, funcs ...entities.DBTxHandleFunc
 
 		return nil
 	})
+	runCommitHooks(err)
 
 	return handleTransactionError(err)
 }
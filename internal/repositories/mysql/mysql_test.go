@@ -5,15 +5,21 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	goMysql "github.com/go-sql-driver/mysql"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/mysql"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/connect"
+	"github.com/tuantran1810/go-di-template/internal/repositories/dbtls"
 	"gorm.io/gorm"
 )
 
@@ -30,8 +36,13 @@ func Test_isInvalidInputError(t *testing.T) {
 			want: false,
 		},
 		{
-			name: "constraint failed",
+			name: "constraint in the message only",
 			err:  errors.New("xxx constraint failed"),
+			want: false,
+		},
+		{
+			name: "foreign key violated",
+			err:  gorm.ErrForeignKeyViolated,
 			want: true,
 		},
 		{
@@ -131,6 +142,74 @@ func Test_isCanceledError(t *testing.T) {
 	}
 }
 
+func Test_getDriverError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{
+			name: "not a driver error",
+			err:  errors.New("xxx constraint failed"),
+			want: nil,
+		},
+		{
+			name: "duplicate entry",
+			err:  fmt.Errorf("wrapped: %w", &goMysql.MySQLError{Number: 1062}),
+			want: entities.ErrConflicted,
+		},
+		{
+			name: "foreign key violation",
+			err:  &goMysql.MySQLError{Number: 1452},
+			want: entities.ErrInvalid,
+		},
+		{
+			name: "data too long",
+			err:  &goMysql.MySQLError{Number: 1406},
+			want: entities.ErrInvalid,
+		},
+		{
+			name: "lock wait timeout",
+			err:  &goMysql.MySQLError{Number: 1205},
+			want: entities.ErrLocked,
+		},
+		{
+			name: "lock nowait",
+			err:  &goMysql.MySQLError{Number: 3572},
+			want: entities.ErrLocked,
+		},
+		{
+			name: "deadlock",
+			err:  &goMysql.MySQLError{Number: 1213},
+			want: entities.ErrLocked,
+		},
+		{
+			name: "too many connections",
+			err:  &goMysql.MySQLError{Number: 1040},
+			want: entities.ErrTooManyRequests,
+		},
+		{
+			name: "unknown column",
+			err:  &goMysql.MySQLError{Number: 1054},
+			want: entities.ErrMalformed,
+		},
+		{
+			name: "unknown error number",
+			err:  &goMysql.MySQLError{Number: 1105},
+			want: entities.ErrDatabase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := getDriverError(tt.err); got != tt.want {
+				t.Errorf("getDriverError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_getEntityError(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -153,6 +232,11 @@ func Test_getEntityError(t *testing.T) {
 			err:  gorm.ErrInvalidField,
 			want: entities.ErrInvalid,
 		},
+		{
+			name: "duplicated key error",
+			err:  gorm.ErrDuplicatedKey,
+			want: entities.ErrConflicted,
+		},
 		{
 			name: "not found error",
 			err:  gorm.ErrRecordNotFound,
@@ -272,3 +356,93 @@ func TestGormTransaction_GetTransaction(t *testing.T) {
 		})
 	}
 }
+
+// TestRepository_StartRetry checks that Start retries to connect to an unreachable database until the deadline.
+func TestRepository_StartRetry(t *testing.T) {
+	t.Parallel()
+
+	// a port nothing listens on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	if err := listener.Close(); err != nil {
+		t.Fatalf("failed to close listener: %v", err)
+	}
+
+	r := MustNewRepository(RepositoryConfig{
+		Username: "root",
+		Password: "secret",
+		Protocol: "tcp",
+		Address:  listener.Addr().String(),
+		Database: "test",
+		Connect:  connect.Config{Deadline: 300 * time.Millisecond, InitialBackoff: 50 * time.Millisecond},
+	})
+	start := time.Now()
+	err = r.Start(context.Background())
+	if !errors.Is(err, entities.ErrDatabase) {
+		t.Errorf("Start() error = %v, want %v", err, entities.ErrDatabase)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("Start() failed after %v, want after the deadline of 300ms", elapsed)
+	}
+}
+
+func TestRepositoryConfig_tlsConfigName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config RepositoryConfig
+		want   string
+	}{
+		{
+			name:   "no tls",
+			config: RepositoryConfig{Address: "127.0.0.1:3306", Database: "test"},
+			want:   "",
+		},
+		{
+			name:   "name of the driver",
+			config: RepositoryConfig{Address: "127.0.0.1:3306", Database: "test", TLSConfig: "skip-verify"},
+			want:   "skip-verify",
+		},
+		{
+			name:   "tls of the database",
+			config: RepositoryConfig{Address: "127.0.0.1:3306", Database: "test", TLS: dbtls.Config{Enabled: true}},
+			want:   "mysql:127.0.0.1:3306/test",
+		},
+		{
+			name: "tls with a name",
+			config: RepositoryConfig{
+				Address: "127.0.0.1:3306", Database: "test", TLSConfig: "primary", TLS: dbtls.Config{Enabled: true},
+			},
+			want: "primary",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.config.tlsConfigName(); got != tt.want {
+				t.Errorf("RepositoryConfig.tlsConfigName() = %v, want %v", got, tt.want)
+			}
+			if tt.want != "" && !strings.Contains(tt.config.DSN(), "tls="+url.QueryEscape(tt.want)) {
+				t.Errorf("RepositoryConfig.DSN() = %v, want the tls config %v", tt.config.DSN(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRepository_StartTLS(t *testing.T) {
+	t.Parallel()
+
+	r := MustNewRepository(RepositoryConfig{
+		Protocol: "tcp",
+		Address:  "127.0.0.1:3306",
+		Database: "test",
+		TLS:      dbtls.Config{Enabled: true, CAFile: "missing.pem"},
+	})
+	if err := r.Start(context.Background()); !errors.Is(err, entities.ErrInvalid) {
+		t.Errorf("Start() with a missing CA file error = %v, want %v", err, entities.ErrInvalid)
+	}
+}
@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"gorm.io/gorm"
+)
+
+type OutboxMessage struct {
+	gorm.Model
+	TenantID  uint `gorm:"not null;default:1;index"`
+	Key       string
+	Value     string
+	Attempts  uint
+	LastError string
+	SentAt    sql.NullTime `gorm:"index"`
+	DeadAt    sql.NullTime `gorm:"index"`
+}
+
+type outboxMessageTransformer struct{}
+
+func (t *outboxMessageTransformer) ToEntity(data *OutboxMessage) (*entities.OutboxMessage, error) {
+	var sentAt *time.Time
+	if data.SentAt.Valid {
+		sentAt = &data.SentAt.Time
+	}
+	var deadAt *time.Time
+	if data.DeadAt.Valid {
+		deadAt = &data.DeadAt.Time
+	}
+	return &entities.OutboxMessage{
+		ID:        data.ID,
+		CreatedAt: data.CreatedAt,
+		UpdatedAt: data.UpdatedAt,
+		TenantID:  data.TenantID,
+		Key:       data.Key,
+		Value:     data.Value,
+		Attempts:  data.Attempts,
+		LastError: data.LastError,
+		SentAt:    sentAt,
+		DeadAt:    deadAt,
+	}, nil
+}
+
+func (t *outboxMessageTransformer) FromEntity(entity *entities.OutboxMessage) (*OutboxMessage, error) {
+	var sentAt sql.NullTime
+	if entity.SentAt != nil {
+		sentAt = sql.NullTime{Time: *entity.SentAt, Valid: true}
+	}
+	var deadAt sql.NullTime
+	if entity.DeadAt != nil {
+		deadAt = sql.NullTime{Time: *entity.DeadAt, Valid: true}
+	}
+	return &OutboxMessage{
+		Model: gorm.Model{
+			ID:        entity.ID,
+			CreatedAt: entity.CreatedAt,
+			UpdatedAt: entity.UpdatedAt,
+		},
+		TenantID:  entity.TenantID,
+		Key:       entity.Key,
+		Value:     entity.Value,
+		Attempts:  entity.Attempts,
+		LastError: entity.LastError,
+		SentAt:    sentAt,
+		DeadAt:    deadAt,
+	}, nil
+}
+
+type OutboxRepository struct {
+	GenericRepository[OutboxMessage, entities.OutboxMessage]
+}
+
+func NewOutboxRepository(repository Database) *OutboxRepository {
+	transformer := entities.NewExtendedDataTransformer(&outboxMessageTransformer{})
+	return &OutboxRepository{
+		GenericRepository: NewGenericRepository(repository, transformer),
+	}
+}
+
+func (s *OutboxRepository) Start(ctx context.Context) error {
+	log.Info("starting outbox store")
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	err := s.AutoMigrate(timeoutCtx)
+	if err != nil {
+		return err
+	}
+
+	return s.Ping(timeoutCtx)
+}
+
+func (s *OutboxRepository) Stop(_ context.Context) error {
+	log.Info("stopping outbox store")
+	return nil
+}
+
+// GetPending returns the oldest messages that have not been sent yet nor given up, in insertion order.
+func (s *OutboxRepository) GetPending(
+	ctx context.Context,
+	tx entities.Transaction,
+	limit int,
+) ([]entities.OutboxMessage, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	return s.GetManyByCriterias(
+		timeoutCtx, tx,
+		nil,
+		map[string]any{"sent_at IS NULL": nil, "dead_at IS NULL": nil},
+		[]string{"id"},
+		0, limit,
+	)
+}
+
+func (s *OutboxRepository) MarkSent(
+	ctx context.Context,
+	dbtx entities.Transaction,
+	ids []uint,
+) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("%w - input ids is empty", entities.ErrInvalid)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	tx := s.GetContextTransaction(timeoutCtx, dbtx)
+	if err := tx.
+		Model(&OutboxMessage{}).
+		Where("id IN ?", ids).
+		Where("sent_at IS NULL").
+		Updates(map[string]any{
+			"sent_at":    time.Now(),
+			"last_error": "",
+		}).
+		Error; err != nil {
+		return s.GenerateError("failed to mark outbox messages as sent", err)
+	}
+
+	return nil
+}
+
+// MarkFailed counts a failed attempt of the message, and gives it up when dead is set.
+func (s *OutboxRepository) MarkFailed(
+	ctx context.Context,
+	dbtx entities.Transaction,
+	id uint,
+	reason string,
+	dead bool,
+) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	updates := map[string]any{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": reason,
+	}
+	if dead {
+		updates["dead_at"] = time.Now()
+	}
+
+	tx := s.GetContextTransaction(timeoutCtx, dbtx)
+	tx = tx.
+		Model(&OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(updates)
+	if err := tx.Error; err != nil {
+		return s.GenerateError("failed to mark outbox message as failed", err)
+	}
+	if tx.RowsAffected == 0 {
+		return fmt.Errorf("%w - no rows affected", entities.ErrNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	mysqlModule "github.com/testcontainers/testcontainers-go/modules/mysql"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/mysql"
+)
+
+func (s *OutboxRepositoryTestSuite) createTestData(t *testing.T, store *OutboxRepository) {
+	t.Helper()
+
+	data := []entities.OutboxMessage{
+		{Key: "user_created", Value: "user_id: 1, username: user1"},
+		{Key: "user_created", Value: "user_id: 2, username: user2"},
+		{Key: "user_created", Value: "user_id: 3, username: user3"},
+	}
+	if _, err := store.CreateMany(context.Background(), nil, data); err != nil {
+		t.Errorf("failed to create data: %v", err)
+		return
+	}
+}
+
+func (s *OutboxRepositoryTestSuite) setup(t *testing.T, port int) (*OutboxRepository, error) {
+	t.Helper()
+
+	config := mysql.RepositoryConfig{
+		Username:  "root",
+		Password:  "secret",
+		Protocol:  "tcp",
+		Address:   fmt.Sprintf("127.0.0.1:%d", port),
+		Database:  "test",
+		Params:    map[string]string{},
+		Collation: "utf8mb4_general_ci",
+		Loc:       time.Local,
+		ParseTime: true,
+
+		Timeout:      10 * time.Second,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+
+		MaxOpenConns:           10,
+		MaxIdleConns:           10,
+		ConnMaxLifeTimeSeconds: 1800,
+	}
+	r := mysql.MustNewRepository(config)
+	if err := r.Start(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return NewOutboxRepository(r), nil
+}
+
+type OutboxRepositoryTestSuite struct {
+	suite.Suite
+	store     *OutboxRepository
+	container *mysqlModule.MySQLContainer
+}
+
+func (s *OutboxRepositoryTestSuite) SetupSuite() {
+	t := s.T()
+	if err := os.Setenv("TZ", "UTC"); err != nil {
+		t.Errorf("failed to set time zone: %v", err)
+		return
+	}
+
+	mysqlContainer, err := mysqlModule.Run(context.Background(),
+		"mysql:lts",
+		mysqlModule.WithDatabase("test"),
+		mysqlModule.WithUsername("root"),
+		mysqlModule.WithPassword("secret"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("port: 3306  MySQL Community Server - GPL").WithStartupTimeout(30*time.Second),
+			wait.ForListeningPort("3306/tcp").WithStartupTimeout(30*time.Second),
+		),
+	)
+	s.Require().NoError(err)
+	s.container = mysqlContainer
+
+	port, err := mysqlContainer.MappedPort(context.Background(), "3306")
+	s.Require().NoError(err)
+
+	store, err := s.setup(t, port.Int())
+	s.Require().NoError(err)
+	s.store = store
+}
+
+func (s *OutboxRepositoryTestSuite) TearDownSuite() {
+	t := s.T()
+	if err := s.store.DB().Exec("DROP TABLE IF EXISTS `test`.`outbox_messages`").Error; err != nil {
+		t.Logf("failed to cleanup data: %v\n", err)
+	}
+
+	if err := testcontainers.TerminateContainer(s.container); err != nil {
+		t.Errorf("failed to terminate container: %v", err)
+		return
+	}
+}
+
+func (s *OutboxRepositoryTestSuite) SetupTest() {
+	t := s.T()
+	s.Require().NoError(s.store.AutoMigrate(context.Background()))
+
+	if err := s.store.DB().Exec("TRUNCATE TABLE `test`.`outbox_messages`").Error; err != nil {
+		t.Errorf("failed to cleanup data: %v\n", err)
+		return
+	}
+
+	s.createTestData(t, s.store)
+}
+
+func (s *OutboxRepositoryTestSuite) TestOutboxRepository_GetPending() {
+	pending, err := s.store.GetPending(context.Background(), nil, 2)
+	s.Require().NoError(err)
+	s.Require().Len(pending, 2)
+	s.Equal(uint(1), pending[0].ID)
+	s.Equal(uint(2), pending[1].ID)
+	s.Nil(pending[0].SentAt)
+}
+
+func (s *OutboxRepositoryTestSuite) TestOutboxRepository_MarkSent() {
+	s.Require().NoError(s.store.MarkSent(context.Background(), nil, []uint{1, 2}))
+
+	pending, err := s.store.GetPending(context.Background(), nil, 10)
+	s.Require().NoError(err)
+	s.Require().Len(pending, 1)
+	s.Equal(uint(3), pending[0].ID)
+
+	sent, err := s.store.Get(context.Background(), nil, 1)
+	s.Require().NoError(err)
+	s.NotNil(sent.SentAt)
+
+	s.Require().ErrorIs(s.store.MarkSent(context.Background(), nil, nil), entities.ErrInvalid)
+}
+
+func (s *OutboxRepositoryTestSuite) TestOutboxRepository_MarkFailed() {
+	s.Require().NoError(s.store.MarkFailed(context.Background(), nil, 1, "fake error", false))
+	s.Require().NoError(s.store.MarkFailed(context.Background(), nil, 1, "another error", false))
+
+	failed, err := s.store.Get(context.Background(), nil, 1)
+	s.Require().NoError(err)
+	s.Equal(uint(2), failed.Attempts)
+	s.Equal("another error", failed.LastError)
+	s.Nil(failed.SentAt)
+	s.Nil(failed.DeadAt)
+
+	s.Require().NoError(s.store.MarkFailed(context.Background(), nil, 1, "last error", true))
+	dead, err := s.store.Get(context.Background(), nil, 1)
+	s.Require().NoError(err)
+	s.Equal(uint(3), dead.Attempts)
+	s.NotNil(dead.DeadAt)
+
+	pending, err := s.store.GetPending(context.Background(), nil, 10)
+	s.Require().NoError(err)
+	s.Require().Len(pending, 2)
+	s.Equal(uint(2), pending[0].ID)
+
+	s.Require().ErrorIs(s.store.MarkFailed(context.Background(), nil, 10, "fake error", false), entities.ErrNotFound)
+}
+
+func TestOutboxRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxRepositoryTestSuite))
+}
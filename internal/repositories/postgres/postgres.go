@@ -2,33 +2,88 @@ package postgres
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/audit"
+	"github.com/tuantran1810/go-di-template/internal/repositories/connect"
+	"github.com/tuantran1810/go-di-template/internal/repositories/dbmetrics"
+	"github.com/tuantran1810/go-di-template/internal/repositories/dbtls"
+	"github.com/tuantran1810/go-di-template/internal/repositories/encryption"
+	"github.com/tuantran1810/go-di-template/internal/repositories/tenancy"
 	"github.com/tuantran1810/go-di-template/libs/logger"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 var log = logger.MustNamedLogger("postgres")
 
+// maxPlaceholders is the maximum number of placeholders of a statement, the limit of the PostgreSQL protocol.
+const maxPlaceholders = 65535
+
 func isInvalidInputError(err error) bool {
 	if err == nil {
 		return false
 	}
 
-	if strings.Contains(err.Error(), "constraint") {
-		return true
-	}
-
 	return errors.Is(err, gorm.ErrInvalidData) ||
 		errors.Is(err, gorm.ErrInvalidField) ||
 		errors.Is(err, gorm.ErrInvalidValue) ||
-		errors.Is(err, gorm.ErrInvalidValueOfLength)
+		errors.Is(err, gorm.ErrInvalidValueOfLength) ||
+		errors.Is(err, gorm.ErrForeignKeyViolated) ||
+		errors.Is(err, gorm.ErrCheckConstraintViolated)
+}
+
+// postgresErrors are the entities errors of the Postgres SQLSTATEs.
+var postgresErrors = map[string]error{
+	"23505": entities.ErrConflicted,      // unique_violation
+	"23P01": entities.ErrConflicted,      // exclusion_violation
+	"23502": entities.ErrInvalid,         // not_null_violation
+	"23503": entities.ErrInvalid,         // foreign_key_violation
+	"23514": entities.ErrInvalid,         // check_violation
+	"40001": entities.ErrLocked,          // serialization_failure
+	"40P01": entities.ErrLocked,          // deadlock_detected
+	"53300": entities.ErrTooManyRequests, // too_many_connections
+	"55P03": entities.ErrLocked,          // lock_not_available
+	"57014": entities.ErrCanceled,        // query_canceled
+}
+
+// postgresErrorClasses are the entities errors of the SQLSTATE classes, for the codes not in postgresErrors.
+var postgresErrorClasses = map[string]error{
+	"22": entities.ErrInvalid,   // data exception
+	"23": entities.ErrInvalid,   // integrity constraint violation
+	"42": entities.ErrMalformed, // syntax error or access rule violation
+}
+
+// getDriverError returns the entities error of a Postgres server error, nil for the other errors.
+func getDriverError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return nil
+	}
+
+	if entityErr, ok := postgresErrors[pgErr.Code]; ok {
+		return entityErr
+	}
+
+	if len(pgErr.Code) == 5 {
+		if entityErr, ok := postgresErrorClasses[pgErr.Code[:2]]; ok {
+			return entityErr
+		}
+	}
+
+	return entities.ErrDatabase
 }
 
 func isNotFoundError(err error) bool {
@@ -62,6 +117,14 @@ func getEntityError(err error) error {
 		return entities.ErrCanceled
 	}
 
+	if driverErr := getDriverError(err); driverErr != nil {
+		return driverErr
+	}
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return entities.ErrConflicted
+	}
+
 	if isInvalidInputError(err) {
 		return entities.ErrInvalid
 	}
@@ -88,6 +151,10 @@ func handleTransactionError(err error) error {
 
 	eligibleErr := errors.Is(err, entities.ErrCanceled) ||
 		errors.Is(err, entities.ErrInvalid) ||
+		errors.Is(err, entities.ErrConflicted) ||
+		errors.Is(err, entities.ErrMalformed) ||
+		errors.Is(err, entities.ErrTooManyRequests) ||
+		errors.Is(err, entities.ErrLocked) ||
 		errors.Is(err, entities.ErrNotFound) ||
 		errors.Is(err, entities.ErrDatabase)
 	if eligibleErr {
@@ -118,11 +185,26 @@ type RepositoryConfig struct {
 	SSLMode  *string
 	Timezone *string
 	Params   map[string]string
+	// TLS is the TLS of the connections when enabled, with the certificates reloaded once their files change,
+	// instead of the TLS of the SSLMode
+	TLS dbtls.Config
 
 	Timeout                time.Duration
 	MaxOpenConns           uint32
 	MaxIdleConns           uint32
 	ConnMaxLifeTimeSeconds uint32
+
+	// Connect is the retry of the connection when the repository starts
+	Connect connect.Config
+
+	// CreateBatchSize is the maximum number of rows inserted per statement by CreateMany,
+	// as many as the placeholders of a statement allow when 0
+	CreateBatchSize int
+
+	// Logger replaces the default GORM logger when set
+	Logger gormlogger.Interface
+	// Keyring encrypts the tagged fields, they are stored as they are when nil
+	Keyring *encryption.Keyring
 }
 
 func (cfg RepositoryConfig) DSN() string {
@@ -146,9 +228,43 @@ func (cfg RepositoryConfig) DSN() string {
 	return strings.Join(parts, " ")
 }
 
+// connConfig is the pgx config of the DSN with the TLS config, which the DSN cannot hold.
+// The connections are in TLS only, without the plain text fallback of the SSLMode.
+func (cfg RepositoryConfig) connConfig(tlsConfig *tls.Config) (*pgx.ConnConfig, []stdlib.OptionOpenDB, error) {
+	connConfig, err := pgx.ParseConfig(cfg.DSN())
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w - invalid dsn: %w", entities.ErrInvalid, err)
+	}
+	connConfig.TLSConfig = tlsConfig
+	connConfig.Fallbacks = nil
+
+	// what the GORM driver does with the TimeZone of a DSN
+	var options []stdlib.OptionOpenDB
+	if cfg.Timezone != nil && *cfg.Timezone != "" {
+		loc, err := time.LoadLocation(*cfg.Timezone)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w - invalid timezone: %w", entities.ErrInvalid, err)
+		}
+		connConfig.RuntimeParams["timezone"] = *cfg.Timezone
+		options = append(options, stdlib.OptionAfterConnect(func(_ context.Context, conn *pgx.Conn) error {
+			conn.TypeMap().RegisterType(&pgtype.Type{
+				Name:  "timestamp",
+				OID:   pgtype.TimestampOID,
+				Codec: &pgtype.TimestampCodec{ScanLocation: loc},
+			})
+			return nil
+		}))
+	}
+
+	return connConfig, options, nil
+}
+
 type Repository struct {
 	RepositoryConfig
-	db *gorm.DB
+	db                *gorm.DB
+	tlsConfig         *tls.Config
+	connected         atomic.Bool
+	unregisterMetrics func()
 }
 
 func MustNewRepository(cfg RepositoryConfig) *Repository {
@@ -157,36 +273,96 @@ func MustNewRepository(cfg RepositoryConfig) *Repository {
 	}
 }
 
-func (r *Repository) Start(ctx context.Context) error {
-	log.Info("starting postgres repository")
-	dsn := r.DSN()
+// open opens the pool of connections to the database and pings it, the pool is closed when the ping fails.
+func (r *Repository) open(ctx context.Context) (*gorm.DB, error) {
+	dialector := postgres.Open(r.DSN())
+	if r.tlsConfig != nil {
+		connConfig, options, err := r.connConfig(r.tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+		dialector = postgres.New(postgres.Config{Conn: stdlib.OpenDB(*connConfig, options...)})
+	}
+
 	db, err := gorm.Open(
-		postgres.Open(dsn),
-		&gorm.Config{},
+		dialector,
+		&gorm.Config{Logger: r.Logger, DisableAutomaticPing: true},
 	)
 	if err != nil {
-		return fmt.Errorf("%w - failed to open database: %w", entities.ErrDatabase, err)
+		return nil, fmt.Errorf("%w - failed to open database: %w", entities.ErrDatabase, err)
 	}
 
 	dbInstance, err := db.DB()
 	if err != nil {
-		return fmt.Errorf("%w - failed to get database instance: %w", entities.ErrDatabase, err)
+		return nil, fmt.Errorf("%w - failed to get database instance: %w", entities.ErrDatabase, err)
 	}
 
 	dbInstance.SetMaxOpenConns(int(r.MaxOpenConns))
 	dbInstance.SetMaxIdleConns(int(r.MaxIdleConns))
 	dbInstance.SetConnMaxLifetime(time.Duration(r.ConnMaxLifeTimeSeconds) * time.Second)
 
-	if err := dbInstance.Ping(); err != nil {
-		return fmt.Errorf("%w - failed to ping database: %w", entities.ErrDatabase, err)
+	if err := dbInstance.PingContext(ctx); err != nil {
+		_ = dbInstance.Close()
+		return nil, fmt.Errorf("%w - failed to ping database: %w", entities.ErrDatabase, err)
+	}
+
+	return db, nil
+}
+
+func (r *Repository) Start(ctx context.Context) error {
+	log.Info("starting postgres repository")
+	if r.TLS.Enabled {
+		tlsConfig, err := dbtls.Load(r.TLS, r.Host)
+		if err != nil {
+			return err
+		}
+		r.tlsConfig = tlsConfig
+	}
+
+	var db *gorm.DB
+	err := connect.Retry(ctx, r.Connect, fmt.Sprintf("postgres:%s", r.Database), func(ctx context.Context) error {
+		var err error
+		db, err = r.open(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	dbInstance, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("%w - failed to get database instance: %w", entities.ErrDatabase, err)
+	}
+
+	if err := db.Use(encryption.NewPlugin(r.Keyring)); err != nil {
+		return fmt.Errorf("%w - failed to install encryption plugin: %w", entities.ErrDatabase, err)
+	}
+
+	if err := db.Use(tenancy.NewPlugin()); err != nil {
+		return fmt.Errorf("%w - failed to install tenancy plugin: %w", entities.ErrDatabase, err)
+	}
+
+	if err := db.Use(audit.NewPlugin(maxPlaceholders)); err != nil {
+		return fmt.Errorf("%w - failed to install audit plugin: %w", entities.ErrDatabase, err)
+	}
+
+	unregisterMetrics, err := dbmetrics.Instrument(db, dbInstance, fmt.Sprintf("%s:%s", "postgres", r.Database), getEntityError)
+	if err != nil {
+		return err
 	}
 
 	r.db = db
+	r.connected.Store(true)
+	r.unregisterMetrics = unregisterMetrics
 	return r.Check(ctx)
 }
 
 func (r *Repository) Stop(_ context.Context) error {
 	log.Info("stopping postgres repository")
+	if r.unregisterMetrics != nil {
+		r.unregisterMetrics()
+	}
+
 	db, err := r.db.DB()
 	if err != nil {
 		return fmt.Errorf("%w - failed to get database connection: %w", entities.ErrDatabase, err)
@@ -199,8 +375,18 @@ func (r *Repository) Stop(_ context.Context) error {
 	return nil
 }
 
+// Check pings the database, for the health checks. The pool of connections replaces the broken ones by itself,
+// so a lost connection is reported until the database is reachable again, without restarting the repository.
 func (r *Repository) Check(ctx context.Context) error {
-	if err := r.db.WithContext(ctx).Exec("SELECT 1").Error; err != nil {
+	err := r.db.WithContext(ctx).Exec("SELECT 1").Error
+	if connected := err == nil; r.connected.Swap(connected) != connected {
+		if connected {
+			log.Infow("database connection re-established", "database", r.Database)
+		} else {
+			log.Warnw("database connection lost", "database", r.Database, "error", err)
+		}
+	}
+	if err != nil {
 		return GenerateError("failed to ping database", err)
 	}
 
@@ -211,6 +397,11 @@ func (r *Repository) DB() *gorm.DB {
 	return r.db
 }
 
+// GenerateError is GenerateError, for the callers holding the repository behind an interface.
+func (r *Repository) GenerateError(errStr string, err error) error {
+	return GenerateError(errStr, err)
+}
+
 func (r *Repository) GetTransaction(tx entities.Transaction) *gorm.DB {
 	if tx == nil {
 		return r.db
@@ -224,17 +415,30 @@ func (r *Repository) GetTransaction(tx entities.Transaction) *gorm.DB {
 	return txImpl.(*gorm.DB)
 }
 
+// GetContextTransaction resolves the database handle for a repository call bound to ctx.
+// An explicit tx takes precedence, otherwise the transaction carried by ctx is joined.
+func (r *Repository) GetContextTransaction(ctx context.Context, tx entities.Transaction) *gorm.DB {
+	if tx == nil {
+		tx = entities.GetTransactionFromContext(ctx)
+	}
+
+	return r.GetTransaction(tx).WithContext(ctx)
+}
+
 func (r *Repository) RunTx(ctx context.Context, funcs ...entities.DBTxHandleFunc) error {
 	if len(funcs) == 0 {
 		return fmt.Errorf("%w - input no handler function", entities.ErrInternal)
 	}
 
-	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	ctx, runCommitHooks := entities.WithCommitHooks(ctx)
+	// a transaction already carried by ctx is continued with a nested savepoint
+	err := r.GetContextTransaction(ctx, nil).Transaction(func(tx *gorm.DB) error {
 		txKeeper := NewGormTransaction(tx)
+		txCtx := entities.InjectTransactionToContext(ctx, txKeeper)
 
 		for _, f := range funcs {
 			if f != nil {
-				ferr := f(ctx, txKeeper)
+				ferr := f(txCtx, txKeeper)
 				if ferr != nil {
 					return ferr
 				}
@@ -243,6 +447,7 @@ func (r *Repository) RunTx(ctx context.Context, funcs ...entities.DBTxHandleFunc
 
 		return nil
 	})
+	runCommitHooks(err)
 
 	return handleTransactionError(err)
 }
@@ -2,14 +2,21 @@ package postgres
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"errors"
+	"fmt"
+	"net"
 	"reflect"
 	"testing"
+	"time"
 
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/connect"
+	"github.com/tuantran1810/go-di-template/internal/repositories/dbtls"
 	"github.com/tuantran1810/go-di-template/libs/utils"
 	"gorm.io/gorm"
 )
@@ -27,8 +34,13 @@ func Test_isInvalidInputError(t *testing.T) {
 			want: false,
 		},
 		{
-			name: "constraint failed",
+			name: "constraint in the message only",
 			err:  errors.New("xxx constraint failed"),
+			want: false,
+		},
+		{
+			name: "foreign key violated",
+			err:  gorm.ErrForeignKeyViolated,
 			want: true,
 		},
 		{
@@ -128,6 +140,79 @@ func Test_isCanceledError(t *testing.T) {
 	}
 }
 
+func Test_getDriverError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{
+			name: "not a driver error",
+			err:  errors.New("xxx constraint failed"),
+			want: nil,
+		},
+		{
+			name: "unique violation",
+			err:  fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "23505"}),
+			want: entities.ErrConflicted,
+		},
+		{
+			name: "foreign key violation",
+			err:  &pgconn.PgError{Code: "23503"},
+			want: entities.ErrInvalid,
+		},
+		{
+			name: "data exception class",
+			err:  &pgconn.PgError{Code: "22001"},
+			want: entities.ErrInvalid,
+		},
+		{
+			name: "lock not available",
+			err:  &pgconn.PgError{Code: "55P03"},
+			want: entities.ErrLocked,
+		},
+		{
+			name: "deadlock",
+			err:  &pgconn.PgError{Code: "40P01"},
+			want: entities.ErrLocked,
+		},
+		{
+			name: "serialization failure",
+			err:  &pgconn.PgError{Code: "40001"},
+			want: entities.ErrLocked,
+		},
+		{
+			name: "too many connections",
+			err:  &pgconn.PgError{Code: "53300"},
+			want: entities.ErrTooManyRequests,
+		},
+		{
+			name: "query canceled",
+			err:  &pgconn.PgError{Code: "57014"},
+			want: entities.ErrCanceled,
+		},
+		{
+			name: "undefined column",
+			err:  &pgconn.PgError{Code: "42703"},
+			want: entities.ErrMalformed,
+		},
+		{
+			name: "connection failure",
+			err:  &pgconn.PgError{Code: "08006"},
+			want: entities.ErrDatabase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := getDriverError(tt.err); got != tt.want {
+				t.Errorf("getDriverError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_getEntityError(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -150,6 +235,11 @@ func Test_getEntityError(t *testing.T) {
 			err:  gorm.ErrInvalidField,
 			want: entities.ErrInvalid,
 		},
+		{
+			name: "duplicated key error",
+			err:  gorm.ErrDuplicatedKey,
+			want: entities.ErrConflicted,
+		},
 		{
 			name: "not found error",
 			err:  gorm.ErrRecordNotFound,
@@ -249,6 +339,39 @@ func TestRepositoryConfig_DSN(t *testing.T) {
 	}
 }
 
+func TestRepositoryConfig_connConfig(t *testing.T) {
+	t.Parallel()
+
+	config := RepositoryConfig{
+		Host:     "localhost",
+		Port:     5432,
+		Username: "postgres",
+		Password: "postgres",
+		Database: "test",
+		SSLMode:  utils.Pointer("prefer"),
+		Timezone: utils.Pointer("UTC"),
+	}
+	tlsConfig := &tls.Config{ServerName: "db.internal", MinVersion: tls.VersionTLS12}
+	connConfig, options, err := config.connConfig(tlsConfig)
+	if err != nil {
+		t.Fatalf("RepositoryConfig.connConfig() error = %v", err)
+	}
+	if connConfig.TLSConfig != tlsConfig {
+		t.Errorf("RepositoryConfig.connConfig() TLS = %v, want %v", connConfig.TLSConfig, tlsConfig)
+	}
+	if len(connConfig.Fallbacks) != 0 {
+		t.Errorf("RepositoryConfig.connConfig() has %d fallbacks, want none", len(connConfig.Fallbacks))
+	}
+	if connConfig.RuntimeParams["timezone"] != "UTC" || len(options) != 1 {
+		t.Errorf("RepositoryConfig.connConfig() timezone = %q with %d options, want UTC", connConfig.RuntimeParams["timezone"], len(options))
+	}
+
+	config.Timezone = utils.Pointer("Nowhere/Unknown")
+	if _, _, err := config.connConfig(tlsConfig); !errors.Is(err, entities.ErrInvalid) {
+		t.Errorf("RepositoryConfig.connConfig() with an unknown timezone error = %v, want %v", err, entities.ErrInvalid)
+	}
+}
+
 func TestGormTransaction_GetTransaction(t *testing.T) {
 	t.Parallel()
 	var nilptr *gorm.DB
@@ -277,3 +400,50 @@ func TestGormTransaction_GetTransaction(t *testing.T) {
 		})
 	}
 }
+
+// TestRepository_StartRetry checks that Start retries to connect to an unreachable database until the deadline.
+func TestRepository_StartRetry(t *testing.T) {
+	t.Parallel()
+
+	// a port nothing listens on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	if err := listener.Close(); err != nil {
+		t.Fatalf("failed to close listener: %v", err)
+	}
+
+	r := MustNewRepository(RepositoryConfig{
+		Host:     "127.0.0.1",
+		Port:     listener.Addr().(*net.TCPAddr).Port,
+		Username: "postgres",
+		Password: "postgres",
+		Database: "test",
+		Connect:  connect.Config{Deadline: 300 * time.Millisecond, InitialBackoff: 50 * time.Millisecond},
+	})
+	start := time.Now()
+	err = r.Start(context.Background())
+	if !errors.Is(err, entities.ErrDatabase) {
+		t.Errorf("Start() error = %v, want %v", err, entities.ErrDatabase)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("Start() failed after %v, want after the deadline of 300ms", elapsed)
+	}
+}
+
+func TestRepository_StartTLS(t *testing.T) {
+	t.Parallel()
+
+	r := MustNewRepository(RepositoryConfig{
+		Host:     "127.0.0.1",
+		Port:     5432,
+		Username: "postgres",
+		Password: "postgres",
+		Database: "test",
+		TLS:      dbtls.Config{Enabled: true, CAFile: "missing.pem"},
+	})
+	if err := r.Start(context.Background()); !errors.Is(err, entities.ErrInvalid) {
+		t.Errorf("Start() with a missing CA file error = %v, want %v", err, entities.ErrInvalid)
+	}
+}
@@ -3,9 +3,17 @@ package repositories
 import (
 	"time"
 
+	"github.com/tuantran1810/go-di-template/internal/repositories/audit"
 	"github.com/tuantran1810/go-di-template/libs/logger"
 )
 
 var log = logger.MustNamedLogger("repositories")
 
 const defaultTimeout = 20 * time.Second
+
+// EncryptedModels are the models having encrypted fields, rotated by `keys rotate`
+var EncryptedModels = []any{&User{}}
+
+// ManagedModels are the models of the tables of the server, copied between databases by `db copy`,
+// the referenced ones first. A new model must be added here to be copied.
+var ManagedModels = []any{&Tenant{}, &User{}, &UserAttribute{}, &Message{}, &OutboxMessage{}, &audit.Event{}}
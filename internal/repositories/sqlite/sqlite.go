@@ -5,30 +5,76 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
-	"sync"
+	"time"
 
+	"github.com/mattn/go-sqlite3"
 	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/audit"
+	"github.com/tuantran1810/go-di-template/internal/repositories/dbmetrics"
+	"github.com/tuantran1810/go-di-template/internal/repositories/encryption"
+	"github.com/tuantran1810/go-di-template/internal/repositories/tenancy"
 	"github.com/tuantran1810/go-di-template/libs/logger"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 var log = logger.MustNamedLogger("sqlite")
 
+// maxPlaceholders is the maximum number of placeholders of a statement, the limit of SQLITE_MAX_VARIABLE_NUMBER of the bundled SQLite.
+const maxPlaceholders = 32766
+
 func isInvalidInputError(err error) bool {
 	if err == nil {
 		return false
 	}
 
-	if strings.Contains(err.Error(), "constraint") {
-		return true
-	}
-
 	return errors.Is(err, gorm.ErrInvalidData) ||
 		errors.Is(err, gorm.ErrInvalidField) ||
 		errors.Is(err, gorm.ErrInvalidValue) ||
-		errors.Is(err, gorm.ErrInvalidValueOfLength)
+		errors.Is(err, gorm.ErrInvalidValueOfLength) ||
+		errors.Is(err, gorm.ErrForeignKeyViolated) ||
+		errors.Is(err, gorm.ErrCheckConstraintViolated)
+}
+
+// sqliteExtendedErrors are the entities errors of the SQLite extended result codes.
+var sqliteExtendedErrors = map[sqlite3.ErrNoExtended]error{
+	sqlite3.ErrConstraintUnique:     entities.ErrConflicted,
+	sqlite3.ErrConstraintPrimaryKey: entities.ErrConflicted,
+	sqlite3.ErrConstraintRowID:      entities.ErrConflicted,
+}
+
+// sqliteErrors are the entities errors of the SQLite primary result codes, for the codes not in sqliteExtendedErrors.
+var sqliteErrors = map[sqlite3.ErrNo]error{
+	sqlite3.ErrConstraint: entities.ErrInvalid,
+	sqlite3.ErrTooBig:     entities.ErrInvalid,
+	sqlite3.ErrMismatch:   entities.ErrInvalid,
+	sqlite3.ErrRange:      entities.ErrInvalid,
+	sqlite3.ErrBusy:       entities.ErrLocked,
+	sqlite3.ErrLocked:     entities.ErrLocked,
+	sqlite3.ErrError:      entities.ErrMalformed,
+	sqlite3.ErrInterrupt:  entities.ErrCanceled,
+}
+
+// getDriverError returns the entities error of a SQLite error, nil for the other errors.
+func getDriverError(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return nil
+	}
+
+	if entityErr, ok := sqliteExtendedErrors[sqliteErr.ExtendedCode]; ok {
+		return entityErr
+	}
+
+	if entityErr, ok := sqliteErrors[sqliteErr.Code]; ok {
+		return entityErr
+	}
+
+	return entities.ErrDatabase
 }
 
 func isNotFoundError(err error) bool {
@@ -62,6 +108,14 @@ func getEntityError(err error) error {
 		return entities.ErrCanceled
 	}
 
+	if driverErr := getDriverError(err); driverErr != nil {
+		return driverErr
+	}
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return entities.ErrConflicted
+	}
+
 	if isInvalidInputError(err) {
 		return entities.ErrInvalid
 	}
@@ -88,6 +142,10 @@ func handleTransactionError(err error) error {
 
 	eligibleErr := errors.Is(err, entities.ErrCanceled) ||
 		errors.Is(err, entities.ErrInvalid) ||
+		errors.Is(err, entities.ErrConflicted) ||
+		errors.Is(err, entities.ErrMalformed) ||
+		errors.Is(err, entities.ErrTooManyRequests) ||
+		errors.Is(err, entities.ErrLocked) ||
 		errors.Is(err, entities.ErrNotFound) ||
 		errors.Is(err, entities.ErrDatabase)
 	if eligibleErr {
@@ -109,30 +167,147 @@ func (t *GormTransaction) GetTransaction() any {
 	return t.Tx
 }
 
+const (
+	DefaultBusyTimeout    = 5 * time.Second
+	DefaultReaderPoolSize = 4
+
+	memoryDatabasePath = ":memory:"
+)
+
 type RepositoryConfig struct {
 	DatabasePath string
+	// BusyTimeout is how long a connection waits for the lock of another one, DefaultBusyTimeout when 0
+	BusyTimeout time.Duration
+	// ReaderPoolSize is the number of connections reading outside the transactions, DefaultReaderPoolSize when 0
+	ReaderPoolSize int
+
+	// CreateBatchSize is the maximum number of rows inserted per statement by CreateMany,
+	// as many as the placeholders of a statement allow when 0
+	CreateBatchSize int
+
+	// Logger replaces the default GORM logger when set
+	Logger gormlogger.Interface
+	// Keyring encrypts the tagged fields, they are stored as they are when nil
+	Keyring *encryption.Keyring
 }
 
+// Repository is a SQLite database in WAL mode, written by a single connection and read by a pool of connections.
+// The writes and the transactions go through the writer, which begins its transactions IMMEDIATE, taking the write
+// lock at once instead of failing to upgrade a read lock, so they are serialized by the connection pool within the
+// process and by the busy timeout against the other processes. The reads outside a transaction go through the
+// readers, concurrently with each other and with the writer. An in-memory database is a connection of its own,
+// read and written by the writer.
+//
+// A call made during a transaction must be given the transaction, by tx or ctx, since the writer is taken until its end.
 type Repository struct {
-	sync.RWMutex
-	db *gorm.DB
+	db                *gorm.DB
+	reader            *gorm.DB
+	createBatchSize   int
+	unregisterMetrics []func()
+}
+
+// dsn is the DSN of the database path with the connection parameters of the driver.
+func dsn(path string, params url.Values) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+
+	return path + separator + params.Encode()
+}
+
+// open opens a pool of maxConns connections to the database with the plugins of the repository,
+// the audit plugin only on the writer.
+func open(cfg RepositoryConfig, params url.Values, maxConns int, name string, writer bool) (*gorm.DB, func(), error) {
+	db, err := gorm.Open(sqlite.Open(dsn(cfg.DatabasePath, params)), &gorm.Config{Logger: cfg.Logger})
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w - failed to open database: %w", entities.ErrDatabase, err)
+	}
+
+	dbInstance, err := db.DB()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w - failed to get database instance: %w", entities.ErrDatabase, err)
+	}
+	dbInstance.SetMaxOpenConns(maxConns)
+	// the connections are kept open, an in-memory database lives as long as its connection
+	dbInstance.SetMaxIdleConns(maxConns)
+
+	closeOnError := func(err error) (*gorm.DB, func(), error) {
+		_ = dbInstance.Close()
+		return nil, nil, err
+	}
+
+	if err := dbInstance.Ping(); err != nil {
+		return closeOnError(fmt.Errorf("%w - failed to connect to database: %w", entities.ErrDatabase, err))
+	}
+
+	if err := db.Use(encryption.NewPlugin(cfg.Keyring)); err != nil {
+		return closeOnError(fmt.Errorf("%w - failed to install encryption plugin: %w", entities.ErrDatabase, err))
+	}
+
+	if err := db.Use(tenancy.NewPlugin()); err != nil {
+		return closeOnError(fmt.Errorf("%w - failed to install tenancy plugin: %w", entities.ErrDatabase, err))
+	}
+
+	if writer {
+		if err := db.Use(audit.NewPlugin(maxPlaceholders)); err != nil {
+			return closeOnError(fmt.Errorf("%w - failed to install audit plugin: %w", entities.ErrDatabase, err))
+		}
+	}
+
+	unregisterMetrics, err := dbmetrics.Instrument(db, dbInstance, name, getEntityError)
+	if err != nil {
+		return closeOnError(err)
+	}
+
+	return db, unregisterMetrics, nil
 }
 
 func NewRepository(cfg RepositoryConfig) (*Repository, error) {
-	db, err := gorm.Open(sqlite.Open(cfg.DatabasePath), &gorm.Config{})
+	if cfg.BusyTimeout <= 0 {
+		cfg.BusyTimeout = DefaultBusyTimeout
+	}
+	if cfg.ReaderPoolSize <= 0 {
+		cfg.ReaderPoolSize = DefaultReaderPoolSize
+	}
+
+	busyTimeout := strconv.FormatInt(cfg.BusyTimeout.Milliseconds(), 10)
+	writerParams := url.Values{
+		"_busy_timeout": {busyTimeout},
+		"_foreign_keys": {"1"},
+		"_journal_mode": {"WAL"},
+		"_txlock":       {"immediate"},
+	}
+	db, unregisterWriterMetrics, err := open(cfg, writerParams, 1, fmt.Sprintf("sqlite:%s", cfg.DatabasePath), true)
 	if err != nil {
-		return nil, fmt.Errorf("%w - failed to open database: %w", entities.ErrDatabase, err)
+		return nil, err
 	}
 
-	if err := db.Exec("PRAGMA foreign_keys = ON").Error; err != nil {
-		return nil, fmt.Errorf("%w - failed to enable foreign keys: %w", entities.ErrDatabase, err)
+	repo := &Repository{
+		db:                db,
+		reader:            db,
+		createBatchSize:   cfg.CreateBatchSize,
+		unregisterMetrics: []func(){unregisterWriterMetrics},
+	}
+	if cfg.DatabasePath == memoryDatabasePath {
+		return repo, nil
 	}
 
-	if err := db.Exec("PRAGMA journal_mode = WAL").Error; err != nil {
-		return nil, fmt.Errorf("%w - failed to enable WAL mode: %w", entities.ErrDatabase, err)
+	readerParams := url.Values{
+		"_busy_timeout": {busyTimeout},
+		"_query_only":   {"1"},
 	}
+	reader, unregisterReaderMetrics, err := open(
+		cfg, readerParams, cfg.ReaderPoolSize, fmt.Sprintf("sqlite:%s:reader", cfg.DatabasePath), false,
+	)
+	if err != nil {
+		_ = repo.Stop(context.Background())
+		return nil, err
+	}
+	repo.reader = reader
+	repo.unregisterMetrics = append(repo.unregisterMetrics, unregisterReaderMetrics)
 
-	return &Repository{db: db}, nil
+	return repo, nil
 }
 
 func MustNewRepository(cfg RepositoryConfig) *Repository {
@@ -151,13 +326,33 @@ func (r *Repository) Start(_ context.Context) error {
 
 func (r *Repository) Stop(_ context.Context) error {
 	log.Info("stopping sqlite repository")
-	db, err := r.db.DB()
-	if err != nil {
-		return fmt.Errorf("%w - failed to get database connection: %w", entities.ErrDatabase, err)
+	for _, unregisterMetrics := range r.unregisterMetrics {
+		unregisterMetrics()
+	}
+
+	// the readers first, the last connection to a WAL database checkpoints it
+	pools := []*gorm.DB{r.db}
+	if r.reader != r.db {
+		pools = []*gorm.DB{r.reader, r.db}
 	}
+	for _, pool := range pools {
+		db, err := pool.DB()
+		if err != nil {
+			return fmt.Errorf("%w - failed to get database connection: %w", entities.ErrDatabase, err)
+		}
+
+		if err := db.Close(); err != nil {
+			return fmt.Errorf("%w - failed to close database connection: %w", entities.ErrDatabase, err)
+		}
+	}
+
+	return nil
+}
 
-	if err := db.Close(); err != nil {
-		return fmt.Errorf("%w - failed to close database connection: %w", entities.ErrDatabase, err)
+// Check pings the readers, the writer may be taken by a long transaction.
+func (r *Repository) Check(ctx context.Context) error {
+	if err := r.reader.WithContext(ctx).Exec("SELECT 1").Error; err != nil {
+		return GenerateError("failed to ping database", err)
 	}
 
 	return nil
@@ -167,6 +362,11 @@ func (r *Repository) DB() *gorm.DB {
 	return r.db
 }
 
+// GenerateError is GenerateError, for the callers holding the repository behind an interface.
+func (r *Repository) GenerateError(errStr string, err error) error {
+	return GenerateError(errStr, err)
+}
+
 func (r *Repository) GetTransaction(tx entities.Transaction) *gorm.DB {
 	if tx == nil {
 		return r.db
@@ -180,17 +380,43 @@ func (r *Repository) GetTransaction(tx entities.Transaction) *gorm.DB {
 	return txImpl.(*gorm.DB)
 }
 
+// GetContextTransaction resolves the database handle for a repository call bound to ctx.
+// An explicit tx takes precedence, otherwise the transaction carried by ctx is joined.
+func (r *Repository) GetContextTransaction(ctx context.Context, tx entities.Transaction) *gorm.DB {
+	if tx == nil {
+		tx = entities.GetTransactionFromContext(ctx)
+	}
+
+	return r.GetTransaction(tx).WithContext(ctx)
+}
+
+// GetContextReader resolves the database handle for a read bound to ctx: the transaction of tx or ctx,
+// which reads its own writes, or the readers outside a transaction.
+func (r *Repository) GetContextReader(ctx context.Context, tx entities.Transaction) *gorm.DB {
+	if tx == nil {
+		tx = entities.GetTransactionFromContext(ctx)
+	}
+	if tx == nil || tx.GetTransaction() == nil {
+		return r.reader.WithContext(ctx)
+	}
+
+	return r.GetTransaction(tx).WithContext(ctx)
+}
+
 func (r *Repository) RunTx(ctx context.Context, funcs ...entities.DBTxHandleFunc) error {
 	if len(funcs) == 0 {
 		return fmt.Errorf("%w - input no handler function", entities.ErrInternal)
 	}
 
-	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	ctx, runCommitHooks := entities.WithCommitHooks(ctx)
+	// a transaction already carried by ctx is continued with a nested savepoint
+	err := r.GetContextTransaction(ctx, nil).Transaction(func(tx *gorm.DB) error {
 		txKeeper := NewGormTransaction(tx)
+		txCtx := entities.InjectTransactionToContext(ctx, txKeeper)
 
 		for _, f := range funcs {
 			if f != nil {
-				ferr := f(ctx, txKeeper)
+				ferr := f(txCtx, txKeeper)
 				if ferr != nil {
 					return ferr
 				}
@@ -199,6 +425,7 @@ func (r *Repository) RunTx(ctx context.Context, funcs ...entities.DBTxHandleFunc
 
 		return nil
 	})
+	runCommitHooks(err)
 
 	return handleTransactionError(err)
 }
@@ -4,11 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"path/filepath"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 	"github.com/tuantran1810/go-di-template/internal/entities"
 	"gorm.io/gorm"
 )
@@ -26,8 +30,13 @@ func Test_isInvalidInputError(t *testing.T) {
 			want: false,
 		},
 		{
-			name: "constraint failed",
+			name: "constraint in the message only",
 			err:  errors.New("xxx constraint failed"),
+			want: false,
+		},
+		{
+			name: "foreign key violated",
+			err:  gorm.ErrForeignKeyViolated,
 			want: true,
 		},
 		{
@@ -127,6 +136,69 @@ func Test_isCanceledError(t *testing.T) {
 	}
 }
 
+func Test_getDriverError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{
+			name: "not a driver error",
+			err:  errors.New("xxx constraint failed"),
+			want: nil,
+		},
+		{
+			name: "unique constraint",
+			err:  fmt.Errorf("wrapped: %w", sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}),
+			want: entities.ErrConflicted,
+		},
+		{
+			name: "primary key constraint",
+			err:  sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintPrimaryKey},
+			want: entities.ErrConflicted,
+		},
+		{
+			name: "foreign key constraint",
+			err:  sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintForeignKey},
+			want: entities.ErrInvalid,
+		},
+		{
+			name: "not null constraint",
+			err:  sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintNotNull},
+			want: entities.ErrInvalid,
+		},
+		{
+			name: "busy",
+			err:  sqlite3.Error{Code: sqlite3.ErrBusy},
+			want: entities.ErrLocked,
+		},
+		{
+			name: "sql error",
+			err:  sqlite3.Error{Code: sqlite3.ErrError},
+			want: entities.ErrMalformed,
+		},
+		{
+			name: "interrupted",
+			err:  sqlite3.Error{Code: sqlite3.ErrInterrupt},
+			want: entities.ErrCanceled,
+		},
+		{
+			name: "disk I/O error",
+			err:  sqlite3.Error{Code: sqlite3.ErrIoErr},
+			want: entities.ErrDatabase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := getDriverError(tt.err); got != tt.want {
+				t.Errorf("getDriverError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_getEntityError(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -149,6 +221,11 @@ func Test_getEntityError(t *testing.T) {
 			err:  gorm.ErrInvalidField,
 			want: entities.ErrInvalid,
 		},
+		{
+			name: "duplicated key error",
+			err:  gorm.ErrDuplicatedKey,
+			want: entities.ErrConflicted,
+		},
 		{
 			name: "not found error",
 			err:  gorm.ErrRecordNotFound,
@@ -198,3 +275,193 @@ func TestGormTransaction_GetTransaction(t *testing.T) {
 		})
 	}
 }
+
+func newFileStore(tb testing.TB, cfg RepositoryConfig) *DataStore {
+	tb.Helper()
+
+	if cfg.DatabasePath == "" {
+		cfg.DatabasePath = filepath.Join(tb.TempDir(), "test.db")
+	}
+	r, err := NewRepository(cfg)
+	if err != nil {
+		tb.Fatalf("failed to open repository: %v", err)
+	}
+	tb.Cleanup(func() { _ = r.Stop(context.Background()) })
+	if err := r.db.AutoMigrate(&Data{}); err != nil {
+		tb.Fatalf("failed to migrate: %v", err)
+	}
+
+	return NewGenericRepository(r, entities.NewExtendedDataTransformer(&DataTransformer{}))
+}
+
+func TestRepository_ReadDuringTransaction(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newFileStore(t, RepositoryConfig{})
+	created, err := store.Create(ctx, nil, &DataEntity{UniqueID: "unique-id-1", Key: "key1", Value: "value1"})
+	if err != nil {
+		t.Fatalf("failed to create data: %v", err)
+	}
+
+	err = store.RunTx(ctx, func(ctx context.Context, tx entities.Transaction) error {
+		created.Value = "value2"
+		if err := store.Update(ctx, tx, created); err != nil {
+			return err
+		}
+
+		// the readers are not blocked by the writer and do not see its transaction
+		done := make(chan error, 1)
+		go func() {
+			got, err := store.Get(context.Background(), nil, created.ID)
+			if err == nil && got.Value != "value1" {
+				err = fmt.Errorf("read %q outside the transaction, want value1", got.Value)
+			}
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				return err
+			}
+		case <-time.After(5 * time.Second):
+			return errors.New("read blocked by the transaction")
+		}
+
+		got, err := store.Get(ctx, nil, created.ID)
+		if err != nil {
+			return err
+		}
+		if got.Value != "value2" {
+			return fmt.Errorf("read %q in the transaction, want value2", got.Value)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RunTx() error = %v", err)
+	}
+}
+
+func TestRepository_ConcurrentWriters(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newFileStore(t, RepositoryConfig{})
+
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- store.RunTx(ctx, func(ctx context.Context, tx entities.Transaction) error {
+				// a read-modify-write, which fails to upgrade its read lock in a deferred transaction
+				if _, err := store.Count(ctx, tx, nil); err != nil {
+					return err
+				}
+				_, err := store.Create(ctx, tx, &DataEntity{UniqueID: fmt.Sprintf("unique-id-%d", i)})
+				return err
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("RunTx() error = %v", err)
+		}
+	}
+	if count, err := store.Count(ctx, nil, nil); err != nil || count != writers {
+		t.Errorf("Count() = %d, %v, want %d", count, err, writers)
+	}
+}
+
+// TestRepository_BusyTimeout checks that a writer waits for the writer of another process up to the busy timeout.
+func TestRepository_BusyTimeout(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "test.db")
+	store := newFileStore(t, RepositoryConfig{DatabasePath: path})
+	other := newFileStore(t, RepositoryConfig{DatabasePath: path, BusyTimeout: 100 * time.Millisecond})
+
+	err := store.RunTx(ctx, func(ctx context.Context, tx entities.Transaction) error {
+		if _, err := store.Create(ctx, tx, &DataEntity{UniqueID: "unique-id-1"}); err != nil {
+			return err
+		}
+
+		start := time.Now()
+		_, err := other.Create(context.Background(), nil, &DataEntity{UniqueID: "unique-id-2"})
+		if !errors.Is(err, entities.ErrLocked) {
+			return fmt.Errorf("write of the other process error = %v, want %w", err, entities.ErrLocked)
+		}
+		if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+			return fmt.Errorf("write of the other process failed after %v, before the busy timeout", elapsed)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RunTx() error = %v", err)
+	}
+
+	if _, err := other.Create(ctx, nil, &DataEntity{UniqueID: "unique-id-2"}); err != nil {
+		t.Errorf("write of the other process after the transaction error = %v", err)
+	}
+}
+
+// BenchmarkGenericRepository_ConcurrentGet reads rows from concurrent goroutines with pools of readers of several
+// sizes, alone and along a writer inserting rows in a loop, e.g. go test -bench ConcurrentGet -cpu 8 ./internal/repositories/sqlite/
+func BenchmarkGenericRepository_ConcurrentGet(b *testing.B) {
+	const rows = 1000
+	for _, readers := range []int{1, 2, 4, 8} {
+		for _, writing := range []bool{false, true} {
+			b.Run(fmt.Sprintf("readers=%d/writing=%v", readers, writing), func(b *testing.B) {
+				ctx := context.Background()
+				store := newFileStore(b, RepositoryConfig{ReaderPoolSize: readers})
+				data := make([]DataEntity, rows)
+				for i := range data {
+					data[i] = DataEntity{UniqueID: fmt.Sprintf("unique-id-%d", i), Key: "key", Value: "value"}
+				}
+				if _, err := store.CreateMany(ctx, nil, data); err != nil {
+					b.Fatalf("failed to create data: %v", err)
+				}
+
+				stop := make(chan struct{})
+				var wg sync.WaitGroup
+				if writing {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						for i := 0; ; i++ {
+							select {
+							case <-stop:
+								return
+							default:
+							}
+							if _, err := store.Create(ctx, nil, &DataEntity{UniqueID: fmt.Sprintf("writer-%d", i)}); err != nil {
+								b.Errorf("failed to create data: %v", err)
+								return
+							}
+						}
+					}()
+				}
+
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					id := uint(0)
+					for pb.Next() {
+						id = id%rows + 1
+						if _, err := store.Get(ctx, nil, id); err != nil {
+							b.Errorf("failed to get data: %v", err)
+							return
+						}
+					}
+				})
+				b.StopTimer()
+				close(stop)
+				wg.Wait()
+			})
+		}
+	}
+}
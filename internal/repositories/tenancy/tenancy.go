@@ -0,0 +1,139 @@
+package tenancy
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	pluginName = "tenancy"
+
+	// Column is the column of the models owned by a tenant
+	Column = "tenant_id"
+)
+
+// Resolve returns the tenant the calls made with ctx are scoped to, all is true when they are not scoped.
+func Resolve(ctx context.Context) (tenantID uint, all bool) {
+	if entities.IsAllTenants(ctx) {
+		return 0, true
+	}
+
+	return entities.GetTenantIDFromContext(ctx), false
+}
+
+// Scope restricts a statement to the rows of the tenant.
+func Scope(tenantID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: Column}, Value: tenantID})
+	}
+}
+
+// Plugin is a GORM plugin applying Scope to every statement on a model with a tenant_id column,
+// with the tenant of the statement context, and setting the tenant of the created rows.
+// Raw statements are not scoped.
+type Plugin struct{}
+
+func NewPlugin() *Plugin {
+	return &Plugin{}
+}
+
+func (p *Plugin) Name() string {
+	return pluginName
+}
+
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	registers := []func() error{
+		func() error {
+			return callback.Create().Before("gorm:create").Register("tenancy:create", p.create)
+		},
+		func() error {
+			return callback.Query().Before("gorm:query").Register("tenancy:query", p.scope)
+		},
+		func() error {
+			return callback.Row().Before("gorm:row").Register("tenancy:row", p.scope)
+		},
+		func() error {
+			return callback.Update().Before("gorm:update").Register("tenancy:update", p.update)
+		},
+		func() error {
+			return callback.Delete().Before("gorm:delete").Register("tenancy:delete", p.scope)
+		},
+	}
+	for _, register := range registers {
+		if err := register(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func owned(db *gorm.DB) bool {
+	return db.Error == nil &&
+		db.Statement.Schema != nil &&
+		db.Statement.Schema.LookUpField(Column) != nil
+}
+
+func (p *Plugin) scope(db *gorm.DB) {
+	if !owned(db) {
+		return
+	}
+
+	tenantID, all := Resolve(db.Statement.Context)
+	if all {
+		return
+	}
+
+	Scope(tenantID)(db)
+}
+
+// update scopes the statement and keeps the rows in their tenant.
+func (p *Plugin) update(db *gorm.DB) {
+	if !owned(db) {
+		return
+	}
+
+	db.Statement.Omits = append(db.Statement.Omits, Column)
+	p.scope(db)
+}
+
+// create sets the tenant of the created rows to the one of the context,
+// without a scope a row keeps its tenant, or gets the default one.
+func (p *Plugin) create(db *gorm.DB) {
+	if !owned(db) {
+		return
+	}
+
+	ctx := db.Statement.Context
+	field := db.Statement.Schema.LookUpField(Column)
+	tenantID, all := Resolve(ctx)
+	set := func(row reflect.Value) {
+		if !row.CanAddr() {
+			return
+		}
+		if all {
+			if _, zero := field.ValueOf(ctx, row); !zero {
+				return
+			}
+			tenantID = entities.DefaultTenantID
+		}
+		if err := field.Set(ctx, row, tenantID); err != nil {
+			_ = db.AddError(err)
+		}
+	}
+
+	value := reflect.Indirect(db.Statement.ReflectValue)
+	switch value.Kind() {
+	case reflect.Struct:
+		set(value)
+	case reflect.Slice, reflect.Array:
+		for i := range value.Len() {
+			set(reflect.Indirect(value.Index(i)))
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package tenancy
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type Item struct {
+	gorm.Model
+	TenantID uint
+	Name     string
+}
+
+type Shared struct {
+	ID   uint
+	Name string
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(
+		sqlite.Open(filepath.Join(t.TempDir(), "tenancy.db")),
+		&gorm.Config{Logger: gormlogger.Discard},
+	)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.Use(NewPlugin()); err != nil {
+		t.Fatalf("failed to install tenancy plugin: %v", err)
+	}
+	if err := db.AutoMigrate(&Item{}, &Shared{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	return db
+}
+
+func names(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+
+	var items []Item
+	if err := db.Order("id").Find(&items).Error; err != nil {
+		t.Fatalf("failed to find items: %v", err)
+	}
+
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		out = append(out, item.Name)
+	}
+
+	return out
+}
+
+func TestPlugin(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t)
+	tenant2 := db.WithContext(entities.InjectTenantIDToContext(context.Background(), 2))
+	tenant3 := db.WithContext(entities.InjectTenantIDToContext(context.Background(), 3))
+	all := db.WithContext(entities.WithAllTenants(context.Background()))
+
+	// the tenant of the context wins over the one of the row
+	if err := tenant2.Create(&Item{TenantID: 3, Name: "item1"}).Error; err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+	if err := tenant3.Create([]Item{{Name: "item2"}, {Name: "item3"}}).Error; err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+	// without a scope, the row keeps its tenant or gets the default one
+	if err := all.Create([]Item{{TenantID: 2, Name: "item4"}, {Name: "item5"}}).Error; err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+	if err := db.Create(&Item{Name: "item6"}).Error; err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		db   *gorm.DB
+		want []string
+	}{
+		{name: "tenant 2", db: tenant2, want: []string{"item1", "item4"}},
+		{name: "tenant 3", db: tenant3, want: []string{"item2", "item3"}},
+		{name: "default tenant", db: db, want: []string{"item5", "item6"}},
+		{name: "all tenants", db: all, want: []string{"item1", "item2", "item3", "item4", "item5", "item6"}},
+	}
+	for _, tt := range tests {
+		got := names(t, tt.db)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got items %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got items %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+
+	var count int64
+	if err := tenant3.Model(&Item{}).Count(&count).Error; err != nil || count != 2 {
+		t.Errorf("Count() = %d, %v, want 2", count, err)
+	}
+
+	// another tenant can neither read, update nor delete a row by its id
+	var item Item
+	if err := tenant3.First(&item, 1).Error; err == nil {
+		t.Errorf("tenant 3 reads the item of tenant 2")
+	}
+	result := tenant3.Updates(&Item{Model: gorm.Model{ID: 1}, Name: "updated"})
+	if result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("tenant 3 updates the item of tenant 2: %d rows, %v", result.RowsAffected, result.Error)
+	}
+	result = tenant3.Model(&Item{}).Delete("id = ?", 1)
+	if result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("tenant 3 deletes the item of tenant 2: %d rows, %v", result.RowsAffected, result.Error)
+	}
+
+	// an update does not move a row to another tenant
+	if err := tenant2.Updates(&Item{Model: gorm.Model{ID: 1}, TenantID: 3, Name: "updated"}).Error; err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if err := all.First(&item, 1).Error; err != nil {
+		t.Fatalf("failed to get item: %v", err)
+	}
+	if item.TenantID != 2 || item.Name != "updated" {
+		t.Errorf("unexpected item after update %+v", item)
+	}
+
+	// a model without tenant is not scoped
+	if err := tenant2.Create(&Shared{Name: "shared"}).Error; err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+	var shared []Shared
+	if err := tenant3.Find(&shared).Error; err != nil || len(shared) != 1 {
+		t.Errorf("got %d shared rows, %v, want 1", len(shared), err)
+	}
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"gorm.io/gorm"
+)
+
+// Tenant is not owned by a tenant, so the tenancy plugin does not scope it.
+type Tenant struct {
+	gorm.Model
+	Name string `gorm:"size:128;uniqueIndex"`
+}
+
+type tenantTransformer struct{}
+
+func (t *tenantTransformer) ToEntity(data *Tenant) (*entities.Tenant, error) {
+	var deletedAt *time.Time
+	if data.DeletedAt.Valid {
+		deletedAt = &data.DeletedAt.Time
+	}
+	return &entities.Tenant{
+		ID:        data.ID,
+		CreatedAt: data.CreatedAt,
+		UpdatedAt: data.UpdatedAt,
+		DeletedAt: deletedAt,
+		Name:      data.Name,
+	}, nil
+}
+
+func (t *tenantTransformer) FromEntity(entity *entities.Tenant) (*Tenant, error) {
+	return &Tenant{
+		Model: gorm.Model{
+			ID:        entity.ID,
+			CreatedAt: entity.CreatedAt,
+			UpdatedAt: entity.UpdatedAt,
+		},
+		Name: entity.Name,
+	}, nil
+}
+
+type TenantRepository struct {
+	GenericRepository[Tenant, entities.Tenant]
+}
+
+func NewTenantRepository(repository Database) *TenantRepository {
+	transformer := entities.NewExtendedDataTransformer(&tenantTransformer{})
+	return &TenantRepository{
+		GenericRepository: NewGenericRepository(repository, transformer),
+	}
+}
+
+func (s *TenantRepository) Start(ctx context.Context) error {
+	log.Info("starting tenant store")
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	err := s.AutoMigrate(timeoutCtx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Ping(timeoutCtx); err != nil {
+		return err
+	}
+
+	return s.ensureDefaultTenant(timeoutCtx)
+}
+
+func (s *TenantRepository) Stop(_ context.Context) error {
+	log.Info("stopping tenant store")
+	return nil
+}
+
+// ensureDefaultTenant creates the default tenant in an empty table,
+// it owns the data of the calls without tenant and the data created before the tenants.
+func (s *TenantRepository) ensureDefaultTenant(ctx context.Context) error {
+	_, err := s.Get(ctx, nil, entities.DefaultTenantID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, entities.ErrNotFound) {
+		return err
+	}
+
+	tenant, err := s.Create(ctx, nil, &entities.Tenant{Name: entities.DefaultTenantName})
+	if err != nil {
+		return err
+	}
+	if tenant.ID != entities.DefaultTenantID {
+		return fmt.Errorf("%w - default tenant is created with id %d", entities.ErrDatabase, tenant.ID)
+	}
+
+	return nil
+}
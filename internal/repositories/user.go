@@ -4,19 +4,27 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/tuantran1810/go-di-template/internal/entities"
-	"github.com/tuantran1810/go-di-template/internal/repositories/mysql"
+	"github.com/tuantran1810/go-di-template/internal/repositories/encryption"
 	"gorm.io/gorm"
 )
 
 type User struct {
 	gorm.Model
-	Username string `gorm:"uniqueIndex,size:32"`
-	Password string
-	Uuid     string
-	Name     string
-	Email    sql.NullString
+	// usernames are unique within a tenant
+	TenantID uint           `gorm:"not null;default:1;uniqueIndex:idx_users_tenant_username"`
+	Username string         `gorm:"size:128;uniqueIndex:idx_users_tenant_username"`
+	Password string         `audit:"redact"`
+	Uuid     string         `gorm:"size:36;index"`
+	Name     string         `gorm:"serializer:encrypted" audit:"redact"`
+	Email    sql.NullString `gorm:"serializer:encrypted" audit:"redact"`
+	// EmailIndex is the blind index of the encrypted email, NULL without encryption keyring
+	EmailIndex sql.NullString `gorm:"size:64;index" blindindex:"email"`
+	// Attributes are loaded by the Preload of "Attributes" only, they are not written along with the user.
+	// The association adds no foreign key to user_attributes, which migrates the same whatever the order.
+	Attributes []UserAttribute `gorm:"foreignKey:UserID;constraint:-"`
 }
 
 type userTransformer struct{}
@@ -26,15 +34,33 @@ func (t *userTransformer) ToEntity(data *User) (*entities.User, error) {
 	if data.Email.Valid {
 		email = &data.Email.String
 	}
+	var attributes []entities.UserAttribute
+	if data.Attributes != nil {
+		attributes = make([]entities.UserAttribute, 0, len(data.Attributes))
+		for i := range data.Attributes {
+			attribute, err := (&userAttributeTransformer{}).ToEntity(&data.Attributes[i])
+			if err != nil {
+				return nil, err
+			}
+			attributes = append(attributes, *attribute)
+		}
+	}
+	var deletedAt *time.Time
+	if data.DeletedAt.Valid {
+		deletedAt = &data.DeletedAt.Time
+	}
 	return &entities.User{
-		ID:        data.ID,
-		CreatedAt: data.CreatedAt,
-		UpdatedAt: data.UpdatedAt,
-		Username:  data.Username,
-		Password:  data.Password,
-		Uuid:      data.Uuid,
-		Name:      data.Name,
-		Email:     email,
+		ID:         data.ID,
+		CreatedAt:  data.CreatedAt,
+		UpdatedAt:  data.UpdatedAt,
+		DeletedAt:  deletedAt,
+		TenantID:   data.TenantID,
+		Username:   data.Username,
+		Password:   data.Password,
+		Uuid:       data.Uuid,
+		Name:       data.Name,
+		Email:      email,
+		Attributes: attributes,
 	}, nil
 }
 
@@ -49,6 +75,7 @@ func (t *userTransformer) FromEntity(entity *entities.User) (*User, error) {
 			CreatedAt: entity.CreatedAt,
 			UpdatedAt: entity.UpdatedAt,
 		},
+		TenantID: entity.TenantID,
 		Username: entity.Username,
 		Password: entity.Password,
 		Uuid:     entity.Uuid,
@@ -58,14 +85,14 @@ func (t *userTransformer) FromEntity(entity *entities.User) (*User, error) {
 }
 
 type UserRepository struct {
-	*mysql.GenericRepository[User, entities.User]
+	GenericRepository[User, entities.User]
 	transformer *entities.ExtendedDataTransformer[User, entities.User]
 }
 
-func NewUserRepository(repository *mysql.Repository) *UserRepository {
+func NewUserRepository(repository Database) *UserRepository {
 	transformer := entities.NewExtendedDataTransformer(&userTransformer{})
 	return &UserRepository{
-		GenericRepository: mysql.NewGenericRepository(repository, transformer),
+		GenericRepository: NewGenericRepository(repository, transformer, "created_at"),
 		transformer:       transformer,
 	}
 }
@@ -92,6 +119,7 @@ func (s *UserRepository) FindByUsername(
 	ctx context.Context,
 	tx entities.Transaction,
 	username string,
+	opts ...entities.QueryOption,
 ) (*entities.User, error) {
 	if username == "" {
 		return nil, fmt.Errorf("%w - input username is empty", entities.ErrInvalid)
@@ -105,6 +133,7 @@ func (s *UserRepository) FindByUsername(
 		nil,
 		map[string]any{"username": username},
 		[]string{"id"},
+		opts...,
 	)
 
 	if err != nil {
@@ -113,3 +142,73 @@ func (s *UserRepository) FindByUsername(
 
 	return user, nil
 }
+
+// FindByUsernames finds the users of the usernames, in the order of their ids, leaving out the unknown usernames.
+func (s *UserRepository) FindByUsernames(
+	ctx context.Context,
+	tx entities.Transaction,
+	usernames []string,
+	opts ...entities.QueryOption,
+) ([]entities.User, error) {
+	if len(usernames) == 0 {
+		return nil, fmt.Errorf("%w - input usernames is empty", entities.ErrInvalid)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	return s.GetManyByCriterias(
+		timeoutCtx, tx,
+		nil,
+		map[string]any{"username IN ?": usernames},
+		[]string{"id"},
+		0, len(usernames),
+		opts...,
+	)
+}
+
+// FindByUuids finds the users of the uuids, in the order of their ids, leaving out the unknown uuids.
+func (s *UserRepository) FindByUuids(
+	ctx context.Context,
+	tx entities.Transaction,
+	uuids []string,
+	opts ...entities.QueryOption,
+) ([]entities.User, error) {
+	if len(uuids) == 0 {
+		return nil, fmt.Errorf("%w - input uuids is empty", entities.ErrInvalid)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	return s.GetManyByCriterias(
+		timeoutCtx, tx,
+		nil,
+		map[string]any{"uuid IN ?": uuids},
+		[]string{"id"},
+		0, len(uuids),
+		opts...,
+	)
+}
+
+// FindByEmail finds a user by email, case and surrounding spaces ignored when the emails are encrypted,
+// since they are looked up by their blind index.
+func (s *UserRepository) FindByEmail(
+	ctx context.Context,
+	tx entities.Transaction,
+	email string,
+) (*entities.User, error) {
+	if email == "" {
+		return nil, fmt.Errorf("%w - input email is empty", entities.ErrInvalid)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	criterias := map[string]any{"email": email}
+	if index, ok := encryption.BlindIndex(s.GetContextTransaction(timeoutCtx, tx), email); ok {
+		criterias = map[string]any{"email_index": index}
+	}
+
+	return s.GetByCriterias(timeoutCtx, tx, nil, criterias, []string{"id"})
+}
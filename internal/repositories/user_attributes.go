@@ -4,15 +4,15 @@ import (
 	"context"
 
 	"github.com/tuantran1810/go-di-template/internal/entities"
-	"github.com/tuantran1810/go-di-template/internal/repositories/mysql"
 	"gorm.io/gorm"
 )
 
 type UserAttribute struct {
 	gorm.Model
-	UserID uint `gorm:"index"`
-	Key    string
-	Value  string
+	TenantID uint `gorm:"not null;default:1;index"`
+	UserID   uint `gorm:"index"`
+	Key      string
+	Value    string
 }
 
 type userAttributeTransformer struct{}
@@ -22,6 +22,7 @@ func (t *userAttributeTransformer) ToEntity(data *UserAttribute) (*entities.User
 		ID:        data.ID,
 		CreatedAt: data.CreatedAt,
 		UpdatedAt: data.UpdatedAt,
+		TenantID:  data.TenantID,
 		UserID:    data.UserID,
 		Key:       data.Key,
 		Value:     data.Value,
@@ -35,21 +36,22 @@ func (t *userAttributeTransformer) FromEntity(entity *entities.UserAttribute) (*
 			CreatedAt: entity.CreatedAt,
 			UpdatedAt: entity.UpdatedAt,
 		},
-		UserID: entity.UserID,
-		Key:    entity.Key,
-		Value:  entity.Value,
+		TenantID: entity.TenantID,
+		UserID:   entity.UserID,
+		Key:      entity.Key,
+		Value:    entity.Value,
 	}, nil
 }
 
 type UserAttributeRepository struct {
-	*mysql.GenericRepository[UserAttribute, entities.UserAttribute]
+	GenericRepository[UserAttribute, entities.UserAttribute]
 	transformer *entities.ExtendedDataTransformer[UserAttribute, entities.UserAttribute]
 }
 
-func NewUserAttributeRepository(repository *mysql.Repository) *UserAttributeRepository {
+func NewUserAttributeRepository(repository Database) *UserAttributeRepository {
 	transformer := entities.NewExtendedDataTransformer(&userAttributeTransformer{})
 	return &UserAttributeRepository{
-		GenericRepository: mysql.NewGenericRepository(repository, transformer),
+		GenericRepository: NewGenericRepository(repository, transformer, "key", "value"),
 		transformer:       transformer,
 	}
 }
@@ -98,7 +100,7 @@ func (s *UserAttributeRepository) CountByUserName(
 	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	tx := s.GenericRepository.GetTransaction(dbtx).WithContext(timeoutCtx)
+	tx := s.GetContextTransaction(timeoutCtx, dbtx)
 
 	var count int64
 	if err := tx.
@@ -107,7 +109,7 @@ func (s *UserAttributeRepository) CountByUserName(
 		Where("users.username = ?", userName).
 		Count(&count).
 		Error; err != nil {
-		return 0, mysql.GenerateError("failed to count user attributes", err)
+		return 0, s.GenerateError("failed to count user attributes", err)
 	}
 
 	return count, nil
@@ -121,7 +123,7 @@ func (s *UserAttributeRepository) GetManyByUserName(
 	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	tx := s.GenericRepository.GetTransaction(dbtx).WithContext(timeoutCtx)
+	tx := s.GetContextTransaction(timeoutCtx, dbtx)
 
 	var data []UserAttribute
 	if err := tx.
@@ -129,7 +131,7 @@ func (s *UserAttributeRepository) GetManyByUserName(
 		Where("users.username = ?", userName).
 		Find(&data).
 		Error; err != nil {
-		return nil, mysql.GenerateError("failed to find user attributes", err)
+		return nil, s.GenerateError("failed to find user attributes", err)
 	}
 
 	return s.transformer.ToEntityArray_I2I(data)
@@ -239,6 +239,7 @@ func (s *UserAttributeRepositoryTestSuite) TestUserAttributeRepository_GetByUser
 					ID:        1,
 					CreatedAt: now,
 					UpdatedAt: now,
+					TenantID:  entities.DefaultTenantID,
 					UserID:    1,
 					Key:       "test1",
 					Value:     "test1",
@@ -247,6 +248,7 @@ func (s *UserAttributeRepositoryTestSuite) TestUserAttributeRepository_GetByUser
 					ID:        2,
 					CreatedAt: now,
 					UpdatedAt: now,
+					TenantID:  entities.DefaultTenantID,
 					UserID:    1,
 					Key:       "test2",
 					Value:     "test2",
@@ -262,6 +264,7 @@ func (s *UserAttributeRepositoryTestSuite) TestUserAttributeRepository_GetByUser
 					ID:        3,
 					CreatedAt: now,
 					UpdatedAt: now,
+					TenantID:  entities.DefaultTenantID,
 					UserID:    2,
 					Key:       "test3",
 					Value:     "test3",
@@ -353,6 +356,7 @@ func (s *UserAttributeRepositoryTestSuite) TestUserAttributeRepository_GetManyBy
 					ID:        1,
 					CreatedAt: now,
 					UpdatedAt: now,
+					TenantID:  entities.DefaultTenantID,
 					UserID:    1,
 					Key:       "test1",
 					Value:     "test1",
@@ -361,6 +365,7 @@ func (s *UserAttributeRepositoryTestSuite) TestUserAttributeRepository_GetManyBy
 					ID:        2,
 					CreatedAt: now,
 					UpdatedAt: now,
+					TenantID:  entities.DefaultTenantID,
 					UserID:    1,
 					Key:       "test2",
 					Value:     "test2",
@@ -376,6 +381,7 @@ func (s *UserAttributeRepositoryTestSuite) TestUserAttributeRepository_GetManyBy
 					ID:        3,
 					CreatedAt: now,
 					UpdatedAt: now,
+					TenantID:  entities.DefaultTenantID,
 					UserID:    2,
 					Key:       "test3",
 					Value:     "test3",
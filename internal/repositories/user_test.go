@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
@@ -13,12 +14,14 @@ import (
 	mysqlModule "github.com/testcontainers/testcontainers-go/modules/mysql"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/encryption"
 	"github.com/tuantran1810/go-di-template/internal/repositories/mysql"
 )
 
 func (s *UserRepositoryTestSuite) getTestData(t *testing.T) []entities.User {
 	t.Helper()
 	now := time.Now().UTC().Truncate(time.Second)
+	email := "user2@example.com"
 	return []entities.User{
 		{
 			CreatedAt: now,
@@ -29,6 +32,7 @@ func (s *UserRepositoryTestSuite) getTestData(t *testing.T) []entities.User {
 			CreatedAt: now,
 			UpdatedAt: now,
 			Username:  "user2",
+			Email:     &email,
 		},
 		{
 			CreatedAt: now,
@@ -77,6 +81,17 @@ func (s *UserRepositoryTestSuite) setup(t *testing.T, port int) (*UserRepository
 		MaxIdleConns:           10,
 		ConnMaxLifeTimeSeconds: 1800,
 	}
+
+	keyfile := filepath.Join(t.TempDir(), "keys.json")
+	if _, err := encryption.AddMasterKey(keyfile); err != nil {
+		return nil, err
+	}
+	keyring, err := encryption.LoadKeyring(keyfile)
+	if err != nil {
+		return nil, err
+	}
+	config.Keyring = keyring
+
 	r := mysql.MustNewRepository(config)
 	if err := r.Start(context.Background()); err != nil {
 		return nil, err
@@ -188,6 +203,7 @@ func (s *UserRepositoryTestSuite) TestUserRepository_FindByUsername() {
 				ID:        1,
 				CreatedAt: now,
 				UpdatedAt: now,
+				TenantID:  entities.DefaultTenantID,
 				Username:  "user1",
 			},
 			wantErr: false,
@@ -213,6 +229,55 @@ func (s *UserRepositoryTestSuite) TestUserRepository_FindByUsername() {
 	}
 }
 
+func (s *UserRepositoryTestSuite) TestUserRepository_FindByEmail() {
+	t := s.T()
+
+	tests := []struct {
+		name         string
+		email        string
+		wantUsername string
+		wantErr      bool
+	}{
+		{
+			name:         "user2",
+			email:        "user2@example.com",
+			wantUsername: "user2",
+			wantErr:      false,
+		},
+		{
+			name:         "case and spaces ignored",
+			email:        " User2@Example.com",
+			wantUsername: "user2",
+			wantErr:      false,
+		},
+		{
+			name:    "not found",
+			email:   "user1@example.com",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.store.FindByEmail(context.TODO(), nil, tt.email)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserRepository.FindByEmail() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (got.Username != tt.wantUsername || got.Email == nil || *got.Email != "user2@example.com") {
+				t.Errorf("UserRepository.FindByEmail() = %v, want %v", got, tt.wantUsername)
+			}
+		})
+	}
+
+	var stored string
+	if err := s.store.DB().Raw("SELECT email FROM users WHERE username = ?", "user2").Scan(&stored).Error; err != nil {
+		t.Fatalf("failed to read email: %v", err)
+	}
+	if !encryption.IsEncrypted(stored) {
+		t.Errorf("stored email %q is not encrypted", stored)
+	}
+}
+
 func TestUserRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(UserRepositoryTestSuite))
 }
@@ -0,0 +1,290 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brianvoe/gofakeit/data"
+	"github.com/google/uuid"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/logger"
+)
+
+var log = logger.MustNamedLogger("seed")
+
+const (
+	DefaultBatchSize  = 100
+	DefaultAttributes = "country=4,city=3,company=2,job=2,color=1"
+	MessageKey        = "seed_messages"
+)
+
+type IRepository interface {
+	RunTx(ctx context.Context, funcs ...entities.DBTxHandleFunc) error
+}
+
+type IUserRepository interface {
+	CreateMany(ctx context.Context, tx entities.Transaction, users []entities.User) ([]entities.User, error)
+}
+
+type IUserAttributeRepository interface {
+	CreateMany(ctx context.Context, tx entities.Transaction, userAttributes []entities.UserAttribute) ([]entities.UserAttribute, error)
+}
+
+type IMessageRepository interface {
+	CreateMany(ctx context.Context, tx entities.Transaction, messages []entities.Message) ([]entities.Message, error)
+}
+
+// attributeValues are the values of the known attribute keys, the other keys take lorem words.
+var attributeValues = map[string][]string{
+	"country": data.Address["country"],
+	"city":    data.Address["city"],
+	"company": data.Company["name"],
+	"job":     data.Job["title"],
+	"color":   data.Colors["safe"],
+}
+
+// AttributeWeight is the relative frequency of an attribute key among the attributes of the users.
+type AttributeWeight struct {
+	Key    string
+	Weight int
+}
+
+// ParseAttributeWeights parses the "key=weight,..." list of the attribute keys, a key without weight weighs 1.
+func ParseAttributeWeights(s string) ([]AttributeWeight, error) {
+	var weights []AttributeWeight
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		key, weight, found := strings.Cut(item, "=")
+		w := 1
+		if found {
+			var err error
+			if w, err = strconv.Atoi(weight); err != nil || w <= 0 {
+				return nil, fmt.Errorf("%w - invalid weight of attribute %s: %s", entities.ErrInvalid, key, weight)
+			}
+		}
+		weights = append(weights, AttributeWeight{Key: strings.TrimSpace(key), Weight: w})
+	}
+
+	return weights, nil
+}
+
+type Config struct {
+	Users int
+	// BatchSize is the number of users inserted per transaction, DefaultBatchSize when 0
+	BatchSize int
+	// each user gets between MinAttributes and MaxAttributes attributes, uniformly, with distinct keys
+	MinAttributes int
+	MaxAttributes int
+	// Attributes are the keys the attributes are drawn from, by weight
+	Attributes      []AttributeWeight
+	MessagesPerUser int
+	// the users are created evenly over the Period until Now, time.Now() when zero
+	Period time.Duration
+	Now    time.Time
+	// Seed seeds the generator, the same seed generates the same data on any backend
+	Seed int64
+}
+
+type Result struct {
+	Users      int
+	Attributes int
+	Messages   int
+}
+
+// Seeder populates a database with fake users, their attributes and messages through the repositories.
+type Seeder struct {
+	Config
+	rng                     *rand.Rand
+	repository              IRepository
+	userRepository          IUserRepository
+	userAttributeRepository IUserAttributeRepository
+	messageRepository       IMessageRepository
+}
+
+func NewSeeder(
+	config Config,
+	repository IRepository,
+	userRepository IUserRepository,
+	userAttributeRepository IUserAttributeRepository,
+	messageRepository IMessageRepository,
+) (*Seeder, error) {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.Now.IsZero() {
+		config.Now = time.Now()
+	}
+	if config.Users < 0 || config.MessagesPerUser < 0 || config.Period < 0 {
+		return nil, fmt.Errorf("%w - negative users, messages per user or period", entities.ErrInvalid)
+	}
+	if config.MinAttributes < 0 || config.MinAttributes > config.MaxAttributes {
+		return nil, fmt.Errorf("%w - invalid attributes range [%d, %d]", entities.ErrInvalid, config.MinAttributes, config.MaxAttributes)
+	}
+	if config.MaxAttributes > len(config.Attributes) {
+		return nil, fmt.Errorf(
+			"%w - %d attributes per user need as many attribute keys, got %d",
+			entities.ErrInvalid, config.MaxAttributes, len(config.Attributes),
+		)
+	}
+	for _, attribute := range config.Attributes {
+		if attribute.Key == "" || attribute.Weight <= 0 {
+			return nil, fmt.Errorf("%w - invalid attribute %q of weight %d", entities.ErrInvalid, attribute.Key, attribute.Weight)
+		}
+	}
+
+	return &Seeder{
+		Config:                  config,
+		rng:                     rand.New(rand.NewSource(config.Seed)),
+		repository:              repository,
+		userRepository:          userRepository,
+		userAttributeRepository: userAttributeRepository,
+		messageRepository:       messageRepository,
+	}, nil
+}
+
+// Run inserts the users by batches, each batch with the attributes and messages of its users in a transaction.
+// The data is generated before each batch is inserted, whatever the IDs the database gives, so that a seed always
+// generates the same data. Running the same seed twice on a database conflicts on the usernames.
+func (s *Seeder) Run(ctx context.Context) (Result, error) {
+	var result Result
+	for start := 0; start < s.Users; start += s.BatchSize {
+		users := make([]entities.User, 0, min(s.BatchSize, s.Users-start))
+		attributes := make([][]entities.UserAttribute, 0, cap(users))
+		messages := make([][]entities.Message, 0, cap(users))
+		for i := start; i < start+cap(users); i++ {
+			user := s.user(i)
+			users = append(users, user)
+			attributes = append(attributes, s.attributes(user))
+			messages = append(messages, s.messages(user))
+		}
+
+		var batch Result
+		if err := s.repository.RunTx(ctx, func(ctx context.Context, tx entities.Transaction) error {
+			batch = Result{}
+			created, err := s.userRepository.CreateMany(ctx, tx, users)
+			if err != nil {
+				return fmt.Errorf("failed to create users: %w", err)
+			}
+			batch.Users = len(created)
+
+			var userAttributes []entities.UserAttribute
+			var userMessages []entities.Message
+			for i, user := range created {
+				for _, attribute := range attributes[i] {
+					attribute.UserID = user.ID
+					userAttributes = append(userAttributes, attribute)
+				}
+				userMessages = append(userMessages, messages[i]...)
+			}
+
+			if len(userAttributes) > 0 {
+				if _, err := s.userAttributeRepository.CreateMany(ctx, tx, userAttributes); err != nil {
+					return fmt.Errorf("failed to create user attributes: %w", err)
+				}
+			}
+			batch.Attributes = len(userAttributes)
+
+			if len(userMessages) > 0 {
+				if _, err := s.messageRepository.CreateMany(ctx, tx, userMessages); err != nil {
+					return fmt.Errorf("failed to create messages: %w", err)
+				}
+			}
+			batch.Messages = len(userMessages)
+
+			return nil
+		}); err != nil {
+			return result, err
+		}
+
+		result.Users += batch.Users
+		result.Attributes += batch.Attributes
+		result.Messages += batch.Messages
+		log.Infof("seeded %d/%d users", result.Users, s.Users)
+	}
+
+	return result, nil
+}
+
+func (s *Seeder) pick(values []string) string {
+	return values[s.rng.Intn(len(values))]
+}
+
+// user is the i-th user, created within the i-th slot of the period, the usernames being unique by their index.
+func (s *Seeder) user(i int) entities.User {
+	first := s.pick(data.Person["first"])
+	last := s.pick(data.Person["last"])
+	username := strings.ToLower(fmt.Sprintf("%s.%s.%d", first, last, i))
+	email := username + "@example.com"
+
+	slot := float64(s.Period) / float64(s.Users)
+	createdAt := s.Now.Add(-s.Period).Add(time.Duration((float64(i) + s.rng.Float64()) * slot)).UTC().Truncate(time.Second)
+
+	return entities.User{
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		Username:  username,
+		Password:  strconv.FormatUint(s.rng.Uint64(), 36),
+		Uuid:      uuid.Must(uuid.NewRandomFromReader(s.rng)).String(),
+		Name:      first + " " + last,
+		Email:     &email,
+	}
+}
+
+// attributes draws the keys of the attributes of the user by weight, without replacement.
+func (s *Seeder) attributes(user entities.User) []entities.UserAttribute {
+	count := s.MinAttributes + s.rng.Intn(s.MaxAttributes-s.MinAttributes+1)
+	keys := append([]AttributeWeight(nil), s.Attributes...)
+	attributes := make([]entities.UserAttribute, 0, count)
+	for range count {
+		total := 0
+		for _, key := range keys {
+			total += key.Weight
+		}
+
+		n := s.rng.Intn(total)
+		k := 0
+		for ; n >= keys[k].Weight; k++ {
+			n -= keys[k].Weight
+		}
+
+		values, ok := attributeValues[keys[k].Key]
+		if !ok {
+			values = data.Lorem["word"]
+		}
+		attributes = append(attributes, entities.UserAttribute{
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.CreatedAt,
+			Key:       keys[k].Key,
+			Value:     s.pick(values),
+		})
+		keys = append(keys[:k], keys[k+1:]...)
+	}
+
+	return attributes
+}
+
+func (s *Seeder) messages(user entities.User) []entities.Message {
+	messages := make([]entities.Message, 0, s.MessagesPerUser)
+	for range s.MessagesPerUser {
+		words := make([]string, 5+s.rng.Intn(15))
+		for i := range words {
+			words[i] = s.pick(data.Lorem["word"])
+		}
+		messages = append(messages, entities.Message{
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.CreatedAt,
+			Key:       MessageKey,
+			Value:     user.Username + ": " + strings.Join(words, " "),
+		})
+	}
+
+	return messages
+}
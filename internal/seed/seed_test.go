@@ -0,0 +1,212 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/memory"
+)
+
+func TestParseAttributeWeights(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    []AttributeWeight
+		wantErr error
+	}{
+		{
+			name: "weights",
+			s:    "country=4, city=1",
+			want: []AttributeWeight{{Key: "country", Weight: 4}, {Key: "city", Weight: 1}},
+		},
+		{
+			name: "default weight",
+			s:    "country,job=2,",
+			want: []AttributeWeight{{Key: "country", Weight: 1}, {Key: "job", Weight: 2}},
+		},
+		{
+			name: "empty",
+			s:    "",
+			want: nil,
+		},
+		{
+			name:    "invalid weight",
+			s:       "country=many",
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name:    "zero weight",
+			s:       "country=0",
+			wantErr: entities.ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseAttributeWeights(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseAttributeWeights() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAttributeWeights() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSeeder(t *testing.T) {
+	t.Parallel()
+
+	attributes := []AttributeWeight{{Key: "country", Weight: 1}, {Key: "city", Weight: 1}}
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			config: Config{Users: 10, MinAttributes: 1, MaxAttributes: 2, Attributes: attributes},
+		},
+		{
+			name:    "negative users",
+			config:  Config{Users: -1},
+			wantErr: true,
+		},
+		{
+			name:    "inverted attributes range",
+			config:  Config{Users: 10, MinAttributes: 2, MaxAttributes: 1, Attributes: attributes},
+			wantErr: true,
+		},
+		{
+			name:    "more attributes than keys",
+			config:  Config{Users: 10, MaxAttributes: 3, Attributes: attributes},
+			wantErr: true,
+		},
+		{
+			name:    "invalid weight",
+			config:  Config{Users: 10, MaxAttributes: 1, Attributes: []AttributeWeight{{Key: "country"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewSeeder(tt.config, nil, nil, nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewSeeder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type seeded struct {
+	users      []entities.User
+	attributes []entities.UserAttribute
+	messages   []entities.Message
+}
+
+func runSeeder(t *testing.T, config Config) (Result, seeded) {
+	t.Helper()
+	ctx := context.Background()
+
+	repository := memory.NewRepository()
+	userRepository := memory.NewUserRepository(repository)
+	userAttributeRepository := memory.NewUserAttributeRepository(repository, userRepository)
+	messageRepository := memory.NewMessageRepository(repository)
+
+	seeder, err := NewSeeder(config, repository, userRepository, userAttributeRepository, messageRepository)
+	if err != nil {
+		t.Fatalf("NewSeeder() error = %v", err)
+	}
+	result, err := seeder.Run(ctx)
+	if err != nil {
+		t.Fatalf("Seeder.Run() error = %v", err)
+	}
+
+	var out seeded
+	if out.users, err = userRepository.GetManyByCriterias(ctx, nil, nil, nil, []string{"id"}, 0, 1000); err != nil {
+		t.Fatalf("failed to get users: %v", err)
+	}
+	if out.attributes, err = userAttributeRepository.GetManyByCriterias(ctx, nil, nil, nil, []string{"id"}, 0, 1000); err != nil {
+		t.Fatalf("failed to get user attributes: %v", err)
+	}
+	if out.messages, err = messageRepository.GetManyByCriterias(ctx, nil, nil, nil, []string{"id"}, 0, 1000); err != nil {
+		t.Fatalf("failed to get messages: %v", err)
+	}
+
+	return result, out
+}
+
+func TestSeeder_Run(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		Users:           25,
+		BatchSize:       10,
+		MinAttributes:   1,
+		MaxAttributes:   3,
+		Attributes:      []AttributeWeight{{Key: "country", Weight: 5}, {Key: "job", Weight: 1}, {Key: "hobby", Weight: 1}},
+		MessagesPerUser: 2,
+		Period:          24 * time.Hour,
+		Now:             now,
+		Seed:            42,
+	}
+
+	result, got := runSeeder(t, config)
+	if result.Users != 25 || len(got.users) != 25 {
+		t.Fatalf("Seeder.Run() created %d users, %d stored, want 25", result.Users, len(got.users))
+	}
+	if result.Attributes != len(got.attributes) || result.Messages != 50 || len(got.messages) != 50 {
+		t.Errorf("Seeder.Run() = %+v, stored %d attributes and %d messages", result, len(got.attributes), len(got.messages))
+	}
+
+	keys := make(map[uint]map[string]bool)
+	for _, attribute := range got.attributes {
+		if keys[attribute.UserID] == nil {
+			keys[attribute.UserID] = make(map[string]bool)
+		}
+		if keys[attribute.UserID][attribute.Key] {
+			t.Errorf("user %d has the attribute %s twice", attribute.UserID, attribute.Key)
+		}
+		keys[attribute.UserID][attribute.Key] = true
+	}
+	for _, user := range got.users {
+		if n := len(keys[user.ID]); n < config.MinAttributes || n > config.MaxAttributes {
+			t.Errorf("user %d has %d attributes, want between %d and %d", user.ID, n, config.MinAttributes, config.MaxAttributes)
+		}
+		if user.CreatedAt.Before(now.Add(-config.Period)) || user.CreatedAt.After(now) {
+			t.Errorf("user %d created at %v, out of the period", user.ID, user.CreatedAt)
+		}
+	}
+
+	t.Run("same seed, same data", func(t *testing.T) {
+		t.Parallel()
+
+		_, again := runSeeder(t, config)
+		if !reflect.DeepEqual(again, got) {
+			t.Errorf("Seeder.Run() with the same seed generated different data")
+		}
+	})
+
+	t.Run("other seed, other data", func(t *testing.T) {
+		t.Parallel()
+
+		other := config
+		other.Seed = 43
+		_, again := runSeeder(t, other)
+		if reflect.DeepEqual(again.users, got.users) {
+			t.Errorf("Seeder.Run() with another seed generated the same users")
+		}
+	})
+}
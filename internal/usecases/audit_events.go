@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+const (
+	defaultAuditEventLimit = 100
+	maxAuditEventLimit     = 1000
+)
+
+type AuditEvents struct {
+	auditEventRepository IAuditEventRepository
+}
+
+func NewAuditEventsUsecase(auditEventRepository IAuditEventRepository) *AuditEvents {
+	return &AuditEvents{
+		auditEventRepository: auditEventRepository,
+	}
+}
+
+// ListAuditEvents returns the audit events matching the filter, the most recent first.
+// The time range includes From and excludes To.
+func (a *AuditEvents) ListAuditEvents(ctx context.Context, filter entities.AuditEventFilter) ([]entities.AuditEvent, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	if filter.EntityID != "" && filter.EntityTable == "" {
+		return nil, fmt.Errorf("%w - entity id requires an entity table", entities.ErrInvalid)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("%w - time range is empty", entities.ErrInvalid)
+	}
+	if filter.Offset < 0 || filter.Limit < 0 || filter.Limit > maxAuditEventLimit {
+		return nil, fmt.Errorf("%w - invalid offset %d or limit %d", entities.ErrInvalid, filter.Offset, filter.Limit)
+	}
+
+	criterias := make(map[string]any)
+	if filter.EntityTable != "" {
+		criterias["entity_table = ?"] = filter.EntityTable
+	}
+	if filter.EntityID != "" {
+		criterias["entity_id = ?"] = filter.EntityID
+	}
+	if filter.Actor != "" {
+		criterias["actor = ?"] = filter.Actor
+	}
+	if filter.From != nil {
+		criterias["created_at >= ?"] = *filter.From
+	}
+	if filter.To != nil {
+		criterias["created_at < ?"] = *filter.To
+	}
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = defaultAuditEventLimit
+	}
+
+	events, err := a.auditEventRepository.GetManyByCriterias(
+		timeoutCtx, nil,
+		nil,
+		criterias,
+		[]string{"id DESC"},
+		filter.Offset,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	return events, nil
+}
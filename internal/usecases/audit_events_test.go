@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	mockUsecases "github.com/tuantran1810/go-di-template/mocks/usecases"
+)
+
+func TestAuditEvents_ListAuditEvents(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	from := now.Add(-time.Hour)
+
+	events := []entities.AuditEvent{
+		{
+			ID:          2,
+			CreatedAt:   now,
+			EntityTable: "users",
+			EntityID:    "1",
+			Operation:   entities.AuditOperationUpdate,
+			Actor:       "alice",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T) *AuditEvents
+		filter  entities.AuditEventFilter
+		want    []entities.AuditEvent
+		wantErr error
+	}{
+		{
+			name: "all the filters",
+			setup: func(t *testing.T) *AuditEvents {
+				repository := mockUsecases.NewMockIAuditEventRepository(t)
+				repository.EXPECT().
+					GetManyByCriterias(
+						mock.Anything, mock.Anything,
+						[]string(nil),
+						map[string]any{
+							"entity_table = ?": "users",
+							"entity_id = ?":    "1",
+							"actor = ?":        "alice",
+							"created_at >= ?":  from,
+							"created_at < ?":   now,
+						},
+						[]string{"id DESC"},
+						10, 20,
+					).
+					Return(events, nil)
+				return NewAuditEventsUsecase(repository)
+			},
+			filter: entities.AuditEventFilter{
+				EntityTable: "users",
+				EntityID:    "1",
+				Actor:       "alice",
+				From:        &from,
+				To:          &now,
+				Offset:      10,
+				Limit:       20,
+			},
+			want: events,
+		},
+		{
+			name: "no filter uses the default limit",
+			setup: func(t *testing.T) *AuditEvents {
+				repository := mockUsecases.NewMockIAuditEventRepository(t)
+				repository.EXPECT().
+					GetManyByCriterias(
+						mock.Anything, mock.Anything,
+						[]string(nil),
+						map[string]any{},
+						[]string{"id DESC"},
+						0, defaultAuditEventLimit,
+					).
+					Return(events, nil)
+				return NewAuditEventsUsecase(repository)
+			},
+			want: events,
+		},
+		{
+			name: "repository error",
+			setup: func(t *testing.T) *AuditEvents {
+				repository := mockUsecases.NewMockIAuditEventRepository(t)
+				repository.EXPECT().
+					GetManyByCriterias(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, entities.ErrDatabase)
+				return NewAuditEventsUsecase(repository)
+			},
+			wantErr: entities.ErrDatabase,
+		},
+		{
+			name: "entity id without entity table",
+			setup: func(t *testing.T) *AuditEvents {
+				return NewAuditEventsUsecase(mockUsecases.NewMockIAuditEventRepository(t))
+			},
+			filter:  entities.AuditEventFilter{EntityID: "1"},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name: "empty time range",
+			setup: func(t *testing.T) *AuditEvents {
+				return NewAuditEventsUsecase(mockUsecases.NewMockIAuditEventRepository(t))
+			},
+			filter:  entities.AuditEventFilter{From: &now, To: &from},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name: "limit too large",
+			setup: func(t *testing.T) *AuditEvents {
+				return NewAuditEventsUsecase(mockUsecases.NewMockIAuditEventRepository(t))
+			},
+			filter:  entities.AuditEventFilter{Limit: maxAuditEventLimit + 1},
+			wantErr: entities.ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.setup(t).ListAuditEvents(context.Background(), tt.filter)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AuditEvents.ListAuditEvents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AuditEvents.ListAuditEvents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
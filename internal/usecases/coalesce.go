@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"golang.org/x/sync/singleflight"
+)
+
+var coalescedCalls = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "usecase_coalesced_calls_total",
+		Help: "Number of coalesced usecase calls, by method and result (leader when the call ran, shared when it got the result of a concurrent identical call).",
+	},
+	[]string{"method", "result"},
+)
+
+// coalescer lets the concurrent identical calls of the enabled methods share a single execution.
+type coalescer struct {
+	group   singleflight.Group
+	methods map[string]bool
+}
+
+func newCoalescer(methods []string, known ...string) *coalescer {
+	isKnown := make(map[string]bool, len(known))
+	for _, method := range known {
+		isKnown[method] = true
+	}
+
+	enabled := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		if !isKnown[method] {
+			log.Warnf("cannot coalesce unknown method %s", method)
+			continue
+		}
+		enabled[method] = true
+	}
+
+	return &coalescer{methods: enabled}
+}
+
+// coalesce calls f, or waits for the result of the call of a concurrent caller with the same method and key.
+// The calls are per tenant and the calls in a transaction are never coalesced, since they may read uncommitted data.
+// f runs without the cancellation of the caller, so that a caller giving up does not fail the others,
+// each caller returns once its own context is done. The results are shared, they must not be modified.
+func coalesce[T any](ctx context.Context, c *coalescer, method, key string, f func(ctx context.Context) (T, error)) (T, error) {
+	if c == nil || !c.methods[method] || entities.GetTransactionFromContext(ctx) != nil {
+		return f(ctx)
+	}
+
+	tenant := fmt.Sprint(entities.GetTenantIDFromContext(ctx))
+	if entities.IsAllTenants(ctx) {
+		tenant = "all"
+	}
+
+	leader := false
+	result := c.group.DoChan(method+"\x00"+tenant+"\x00"+key, func() (any, error) {
+		leader = true
+		return f(context.WithoutCancel(ctx))
+	})
+
+	var zero T
+	select {
+	case <-ctx.Done():
+		return zero, fmt.Errorf("%w - %w", entities.ErrCanceled, ctx.Err())
+	case res := <-result:
+		if leader {
+			coalescedCalls.WithLabelValues(method, "leader").Inc()
+		} else {
+			coalescedCalls.WithLabelValues(method, "shared").Inc()
+		}
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	}
+}
@@ -0,0 +1,201 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+// blockingCall counts its calls and blocks them until released.
+type blockingCall struct {
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingCall() *blockingCall {
+	return &blockingCall{
+		started: make(chan struct{}, 10),
+		release: make(chan struct{}),
+	}
+}
+
+func (b *blockingCall) call(ctx context.Context) (int32, error) {
+	n := b.calls.Add(1)
+	b.started <- struct{}{}
+	<-b.release
+	return n, ctx.Err()
+}
+
+func (b *blockingCall) waitStarted(t *testing.T, n int) {
+	t.Helper()
+
+	for range n {
+		select {
+		case <-b.started:
+		case <-time.After(time.Second):
+			t.Fatalf("%d calls started, want %d", b.calls.Load(), n)
+		}
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	t.Parallel()
+
+	c := newCoalescer([]string{"TestCoalesce"}, "TestCoalesce")
+	b := newBlockingCall()
+	leaders := testutil.ToFloat64(coalescedCalls.WithLabelValues("TestCoalesce", "leader"))
+	shared := testutil.ToFloat64(coalescedCalls.WithLabelValues("TestCoalesce", "shared"))
+	results := make([]int32, 5)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := coalesce(context.Background(), c, "TestCoalesce", "key", b.call)
+			if err != nil {
+				t.Errorf("coalesce() error = %v", err)
+			}
+			results[i] = got
+		}()
+		if i == 0 {
+			b.waitStarted(t, 1)
+		}
+	}
+	// lets the other callers join the call in progress
+	time.Sleep(50 * time.Millisecond)
+	close(b.release)
+	wg.Wait()
+
+	if got := b.calls.Load(); got != 1 {
+		t.Errorf("coalesce() ran %d calls, want 1", got)
+	}
+	for i, got := range results {
+		if got != 1 {
+			t.Errorf("caller %d got %d, want the result of the first call", i, got)
+		}
+	}
+	if got := testutil.ToFloat64(coalescedCalls.WithLabelValues("TestCoalesce", "leader")) - leaders; got != 1 {
+		t.Errorf("leader calls = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(coalescedCalls.WithLabelValues("TestCoalesce", "shared")) - shared; got != 4 {
+		t.Errorf("shared calls = %v, want 4", got)
+	}
+}
+
+func TestCoalesce_NotShared(t *testing.T) {
+	t.Parallel()
+
+	tenant2 := entities.InjectTenantIDToContext(context.Background(), 2)
+	inTx := entities.InjectTransactionToContext(context.Background(), &fakeTransaction{name: "tx"})
+	tests := []struct {
+		name   string
+		method string
+		first  context.Context
+		second context.Context
+		keys   [2]string
+	}{
+		{
+			name:   "disabled method",
+			method: "Disabled",
+			first:  context.Background(),
+			second: context.Background(),
+		},
+		{
+			name:   "other keys",
+			method: "TestCoalesce_NotShared",
+			first:  context.Background(),
+			second: context.Background(),
+			keys:   [2]string{"key1", "key2"},
+		},
+		{
+			name:   "other tenants",
+			method: "TestCoalesce_NotShared",
+			first:  context.Background(),
+			second: tenant2,
+		},
+		{
+			name:   "all tenants",
+			method: "TestCoalesce_NotShared",
+			first:  context.Background(),
+			second: entities.WithAllTenants(context.Background()),
+		},
+		{
+			name:   "in transaction",
+			method: "TestCoalesce_NotShared",
+			first:  inTx,
+			second: inTx,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newCoalescer([]string{"TestCoalesce_NotShared"}, "TestCoalesce_NotShared", "Disabled")
+			b := newBlockingCall()
+			var wg sync.WaitGroup
+			for i, ctx := range []context.Context{tt.first, tt.second} {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := coalesce(ctx, c, tt.method, tt.keys[i], b.call); err != nil {
+						t.Errorf("coalesce() error = %v", err)
+					}
+				}()
+			}
+			// both calls run, a shared one would never start the second
+			b.waitStarted(t, 2)
+			close(b.release)
+			wg.Wait()
+		})
+	}
+}
+
+func TestCoalesce_Canceled(t *testing.T) {
+	t.Parallel()
+
+	c := newCoalescer([]string{"TestCoalesce_Canceled"}, "TestCoalesce_Canceled")
+	b := newBlockingCall()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := coalesce(ctx, c, "TestCoalesce_Canceled", "key", b.call)
+		done <- err
+	}()
+	b.waitStarted(t, 1)
+
+	shared := make(chan error)
+	go func() {
+		_, err := coalesce(context.Background(), c, "TestCoalesce_Canceled", "key", b.call)
+		shared <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// the caller giving up returns at once, the call goes on for the other one
+	cancel()
+	if err := <-done; !errors.Is(err, entities.ErrCanceled) {
+		t.Errorf("coalesce() of the canceled caller error = %v, want %v", err, entities.ErrCanceled)
+	}
+	close(b.release)
+	if err := <-shared; err != nil {
+		t.Errorf("coalesce() of the other caller error = %v", err)
+	}
+	if got := b.calls.Load(); got != 1 {
+		t.Errorf("coalesce() ran %d calls, want 1", got)
+	}
+}
+
+func TestNewCoalescer(t *testing.T) {
+	t.Parallel()
+
+	c := newCoalescer([]string{"Known", "Unknown"}, "Known", "Other")
+	if !c.methods["Known"] || c.methods["Unknown"] || c.methods["Other"] {
+		t.Errorf("newCoalescer() methods = %v, want Known only", c.methods)
+	}
+}
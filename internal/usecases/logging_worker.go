@@ -12,6 +12,8 @@ import (
 type LoggingWorkerConfig struct {
 	BufferCapacity int
 	FlushInterval  time.Duration
+	// MaxBacklog is the number of buffered messages over which the worker is unhealthy, 10 times BufferCapacity when 0
+	MaxBacklog int
 }
 
 type LoggingWorker struct {
@@ -30,7 +32,8 @@ func NewLoggingWorker(
 	store IMessageRepository,
 	client IClient,
 ) *LoggingWorker {
-	cancelCtx, cancel := context.WithCancel(context.Background())
+	// the buffer holds the messages of every tenant, each of them keeps its own
+	cancelCtx, cancel := context.WithCancel(entities.WithAllTenants(context.Background()))
 	return &LoggingWorker{
 		LoggingWorkerConfig: config,
 		lock:                sync.Mutex{},
@@ -101,6 +104,24 @@ func (w *LoggingWorker) Stop(ctx context.Context) error {
 	return nil
 }
 
+// Check fails when the messages pile up in the buffer, because the flushes fail or are too slow.
+func (w *LoggingWorker) Check(_ context.Context) error {
+	maxBacklog := w.MaxBacklog
+	if maxBacklog <= 0 {
+		maxBacklog = 10 * w.BufferCapacity
+	}
+
+	w.lock.Lock()
+	backlog := len(w.buffer)
+	w.lock.Unlock()
+
+	if backlog > maxBacklog {
+		return fmt.Errorf("backlog of %d messages exceeds %d", backlog, maxBacklog)
+	}
+
+	return nil
+}
+
 func (w *LoggingWorker) Inject(msg entities.Message) {
 	w.lock.Lock()
 	defer w.lock.Unlock()
@@ -112,6 +133,9 @@ func (w *LoggingWorker) Inject(msg entities.Message) {
 }
 
 func (w *LoggingWorker) LogAndSend(ctx context.Context, msg entities.Message) error {
+	if msg.TenantID == 0 {
+		msg.TenantID = entities.GetTenantIDFromContext(ctx)
+	}
 	w.Inject(msg)
 	if err := w.client.Send(ctx, &msg); err != nil {
 		return fmt.Errorf("failed to send message: %w", err)
@@ -243,15 +243,17 @@ func TestLoggingWorker_LogAndSend(t *testing.T) {
 	mockClient := mockUsecases.NewMockIClient(t)
 	mockClient.EXPECT().
 		Send(mock.Anything, &entities.Message{
-			Key:   "test",
-			Value: "test",
+			TenantID: entities.DefaultTenantID,
+			Key:      "test",
+			Value:    "test",
 		}).
 		Return(nil)
 
 	mockClient.EXPECT().
 		Send(mock.Anything, &entities.Message{
-			Key:   "test_failed",
-			Value: "test_failed",
+			TenantID: entities.DefaultTenantID,
+			Key:      "test_failed",
+			Value:    "test_failed",
 		}).
 		Return(errors.New("fake error"))
 
@@ -295,3 +297,48 @@ func TestLoggingWorker_LogAndSend(t *testing.T) {
 		})
 	}
 }
+
+func TestLoggingWorker_Check(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		maxBacklog int
+		backlog    int
+		wantErr    bool
+	}{
+		{
+			name:    "default max backlog",
+			backlog: 100,
+			wantErr: false,
+		},
+		{
+			name:    "over the default max backlog",
+			backlog: 101,
+			wantErr: true,
+		},
+		{
+			name:       "over the configured max backlog",
+			maxBacklog: 5,
+			backlog:    6,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := &LoggingWorker{
+				LoggingWorkerConfig: LoggingWorkerConfig{
+					BufferCapacity: 10,
+					MaxBacklog:     tt.maxBacklog,
+				},
+				buffer: make([]entities.Message, tt.backlog),
+			}
+			if err := w.Check(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("LoggingWorker.Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+const (
+	defaultOutboxBatchSize    = 100
+	defaultOutboxPollInterval = time.Second
+	defaultOutboxMaxAttempts  = 10
+)
+
+var deadOutboxMessages = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "outbox_dead_messages_total",
+	Help: "Number of outbox messages given up by the relay after their max attempts.",
+})
+
+type OutboxRelayConfig struct {
+	BatchSize    int
+	PollInterval time.Duration
+	// MaxAttempts is the number of failed sends after which a message is given up and skipped
+	MaxAttempts uint
+}
+
+// OutboxRelay publishes the pending outbox messages through the client and marks them as sent.
+// A message is only marked after the client accepted it, so the delivery is at-least-once.
+type OutboxRelay struct {
+	OutboxRelayConfig
+	txManager         *TxManager
+	outboxRepository  IOutboxRepository
+	messageRepository IMessageRepository
+	client            IClient
+	cancelCtx         context.Context
+	cancelFunc        context.CancelFunc
+	doneChan          chan struct{}
+}
+
+func NewOutboxRelay(
+	config OutboxRelayConfig,
+	repository IRepository,
+	outboxRepository IOutboxRepository,
+	messageRepository IMessageRepository,
+	client IClient,
+) *OutboxRelay {
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultOutboxBatchSize
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultOutboxPollInterval
+	}
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = defaultOutboxMaxAttempts
+	}
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	return &OutboxRelay{
+		OutboxRelayConfig: config,
+		txManager:         NewTxManager(repository),
+		outboxRepository:  outboxRepository,
+		messageRepository: messageRepository,
+		client:            client,
+		cancelCtx:         cancelCtx,
+		cancelFunc:        cancel,
+		doneChan:          make(chan struct{}),
+	}
+}
+
+// relay publishes one batch of pending messages of every tenant and returns how many of them were sent.
+// It stops at the first failed message to keep the publishing order, unless the message failed its max attempts:
+// it is given up then, reported and skipped, so that it does not block the messages behind it forever.
+func (r *OutboxRelay) relay(ctx context.Context) (int, error) {
+	ctx = entities.WithAllTenants(ctx)
+	pending, err := r.outboxRepository.GetPending(ctx, nil, r.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending outbox messages: %w", err)
+	}
+
+	sentIDs := make([]uint, 0, len(pending))
+	sentMessages := make([]entities.Message, 0, len(pending))
+	var sendErr error
+	for _, outboxMessage := range pending {
+		msg := entities.Message{
+			TenantID: outboxMessage.TenantID,
+			Key:      outboxMessage.Key,
+			Value:    outboxMessage.Value,
+		}
+		if err := r.client.Send(ctx, &msg); err != nil {
+			dead := outboxMessage.Attempts+1 >= r.MaxAttempts
+			if ferr := r.outboxRepository.MarkFailed(ctx, nil, outboxMessage.ID, err.Error(), dead); ferr != nil {
+				log.Error("failed to mark outbox message as failed", "id", outboxMessage.ID, "error", ferr)
+				dead = false
+			}
+			if dead {
+				deadOutboxMessages.Inc()
+				log.Error(
+					"gave up outbox message after its max attempts",
+					"id", outboxMessage.ID, "tenant_id", outboxMessage.TenantID, "key", outboxMessage.Key, "error", err,
+				)
+				continue
+			}
+			sendErr = fmt.Errorf("failed to send outbox message %d: %w", outboxMessage.ID, err)
+			break
+		}
+		sentIDs = append(sentIDs, outboxMessage.ID)
+		sentMessages = append(sentMessages, msg)
+	}
+
+	if len(sentIDs) == 0 {
+		return 0, sendErr
+	}
+
+	if err := r.txManager.RunInTx(ctx, func(ictx context.Context) error {
+		if _, err := r.messageRepository.CreateMany(ictx, nil, sentMessages); err != nil {
+			return fmt.Errorf("failed to log sent messages: %w", err)
+		}
+		if err := r.outboxRepository.MarkSent(ictx, nil, sentIDs); err != nil {
+			return fmt.Errorf("failed to mark outbox messages as sent: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	return len(sentIDs), sendErr
+}
+
+func (r *OutboxRelay) worker() {
+	defer close(r.doneChan)
+
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.cancelCtx.Done():
+			return
+		case <-ticker.C:
+			// drain the backlog before waiting for the next tick
+			for {
+				sent, err := r.relay(r.cancelCtx)
+				if err != nil {
+					log.Error("failed to relay outbox messages", "error", err)
+					break
+				}
+				if sent < r.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (r *OutboxRelay) Start(_ context.Context) error {
+	log.Info("starting outbox relay")
+	go r.worker()
+	return nil
+}
+
+func (r *OutboxRelay) Stop(ctx context.Context) error {
+	log.Info("stopping outbox relay")
+	r.cancelFunc()
+
+	select {
+	case <-r.doneChan:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to wait for outbox relay: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,304 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	mockUsecases "github.com/tuantran1810/go-di-template/mocks/usecases"
+)
+
+func newTestRepository(t *testing.T) *mockUsecases.MockIRepository {
+	t.Helper()
+	mockRepository := mockUsecases.NewMockIRepository(t)
+	mockRepository.On("RunTx", mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, funcs ...entities.DBTxHandleFunc) error {
+			for _, f := range funcs {
+				if err := f(ctx, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	).Maybe()
+	return mockRepository
+}
+
+func TestOutboxRelay_relay(t *testing.T) {
+	t.Parallel()
+
+	pending := []entities.OutboxMessage{
+		{
+			ID:    1,
+			Key:   "user_created",
+			Value: "user_id: 1, username: test1",
+		},
+		{
+			ID:       2,
+			TenantID: 2,
+			Key:      "user_created",
+			Value:    "user_id: 2, username: test2",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T) *OutboxRelay
+		wantSent int
+		wantErr  bool
+	}{
+		{
+			name: "nothing pending",
+			setup: func(t *testing.T) *OutboxRelay {
+				t.Helper()
+				mockOutboxRepository := mockUsecases.NewMockIOutboxRepository(t)
+				mockOutboxRepository.EXPECT().
+					GetPending(mock.MatchedBy(entities.IsAllTenants), mock.Anything, 10).
+					Return([]entities.OutboxMessage{}, nil)
+				return NewOutboxRelay(
+					OutboxRelayConfig{BatchSize: 10},
+					newTestRepository(t),
+					mockOutboxRepository,
+					mockUsecases.NewMockIMessageRepository(t),
+					mockUsecases.NewMockIClient(t),
+				)
+			},
+			wantSent: 0,
+			wantErr:  false,
+		},
+		{
+			name: "failed to get pending messages",
+			setup: func(t *testing.T) *OutboxRelay {
+				t.Helper()
+				mockOutboxRepository := mockUsecases.NewMockIOutboxRepository(t)
+				mockOutboxRepository.EXPECT().
+					GetPending(mock.MatchedBy(entities.IsAllTenants), mock.Anything, 10).
+					Return(nil, errors.New("fake error"))
+				return NewOutboxRelay(
+					OutboxRelayConfig{BatchSize: 10},
+					newTestRepository(t),
+					mockOutboxRepository,
+					mockUsecases.NewMockIMessageRepository(t),
+					mockUsecases.NewMockIClient(t),
+				)
+			},
+			wantSent: 0,
+			wantErr:  true,
+		},
+		{
+			name: "all messages sent",
+			setup: func(t *testing.T) *OutboxRelay {
+				t.Helper()
+				mockOutboxRepository := mockUsecases.NewMockIOutboxRepository(t)
+				mockOutboxRepository.EXPECT().
+					GetPending(mock.MatchedBy(entities.IsAllTenants), mock.Anything, 10).
+					Return(pending, nil)
+				mockOutboxRepository.EXPECT().
+					MarkSent(mock.Anything, mock.Anything, []uint{1, 2}).
+					Return(nil)
+				mockClient := mockUsecases.NewMockIClient(t)
+				mockClient.EXPECT().
+					Send(mock.Anything, mock.Anything).
+					Return(nil).
+					Times(2)
+				mockMessageRepository := mockUsecases.NewMockIMessageRepository(t)
+				mockMessageRepository.EXPECT().
+					CreateMany(mock.Anything, mock.Anything, []entities.Message{
+						{
+							Key:   "user_created",
+							Value: "user_id: 1, username: test1",
+						},
+						{
+							TenantID: 2,
+							Key:      "user_created",
+							Value:    "user_id: 2, username: test2",
+						},
+					}).
+					Return([]entities.Message{}, nil)
+				return NewOutboxRelay(
+					OutboxRelayConfig{BatchSize: 10},
+					newTestRepository(t),
+					mockOutboxRepository,
+					mockMessageRepository,
+					mockClient,
+				)
+			},
+			wantSent: 2,
+			wantErr:  false,
+		},
+		{
+			name: "stop at the first failed message",
+			setup: func(t *testing.T) *OutboxRelay {
+				t.Helper()
+				mockOutboxRepository := mockUsecases.NewMockIOutboxRepository(t)
+				mockOutboxRepository.EXPECT().
+					GetPending(mock.MatchedBy(entities.IsAllTenants), mock.Anything, 10).
+					Return(pending, nil)
+				mockOutboxRepository.EXPECT().
+					MarkFailed(mock.Anything, mock.Anything, uint(2), "fake error", false).
+					Return(nil)
+				mockOutboxRepository.EXPECT().
+					MarkSent(mock.Anything, mock.Anything, []uint{1}).
+					Return(nil)
+				mockClient := mockUsecases.NewMockIClient(t)
+				mockClient.EXPECT().
+					Send(mock.Anything, &entities.Message{
+						Key:   "user_created",
+						Value: "user_id: 1, username: test1",
+					}).
+					Return(nil)
+				mockClient.EXPECT().
+					Send(mock.Anything, &entities.Message{
+						TenantID: 2,
+						Key:      "user_created",
+						Value:    "user_id: 2, username: test2",
+					}).
+					Return(errors.New("fake error"))
+				mockMessageRepository := mockUsecases.NewMockIMessageRepository(t)
+				mockMessageRepository.EXPECT().
+					CreateMany(mock.Anything, mock.Anything, []entities.Message{
+						{
+							Key:   "user_created",
+							Value: "user_id: 1, username: test1",
+						},
+					}).
+					Return([]entities.Message{}, nil)
+				return NewOutboxRelay(
+					OutboxRelayConfig{BatchSize: 10},
+					newTestRepository(t),
+					mockOutboxRepository,
+					mockMessageRepository,
+					mockClient,
+				)
+			},
+			wantSent: 1,
+			wantErr:  true,
+		},
+		{
+			name: "give up a message after its max attempts and relay the next ones",
+			setup: func(t *testing.T) *OutboxRelay {
+				t.Helper()
+				mockOutboxRepository := mockUsecases.NewMockIOutboxRepository(t)
+				exhausted := append([]entities.OutboxMessage{}, pending...)
+				exhausted[0].Attempts = 2
+				mockOutboxRepository.EXPECT().
+					GetPending(mock.MatchedBy(entities.IsAllTenants), mock.Anything, 10).
+					Return(exhausted, nil)
+				mockOutboxRepository.EXPECT().
+					MarkFailed(mock.Anything, mock.Anything, uint(1), "fake error", true).
+					Return(nil)
+				mockOutboxRepository.EXPECT().
+					MarkSent(mock.Anything, mock.Anything, []uint{2}).
+					Return(nil)
+				mockClient := mockUsecases.NewMockIClient(t)
+				mockClient.EXPECT().
+					Send(mock.Anything, &entities.Message{
+						Key:   "user_created",
+						Value: "user_id: 1, username: test1",
+					}).
+					Return(errors.New("fake error"))
+				mockClient.EXPECT().
+					Send(mock.Anything, &entities.Message{
+						TenantID: 2,
+						Key:      "user_created",
+						Value:    "user_id: 2, username: test2",
+					}).
+					Return(nil)
+				mockMessageRepository := mockUsecases.NewMockIMessageRepository(t)
+				mockMessageRepository.EXPECT().
+					CreateMany(mock.Anything, mock.Anything, []entities.Message{
+						{
+							TenantID: 2,
+							Key:      "user_created",
+							Value:    "user_id: 2, username: test2",
+						},
+					}).
+					Return([]entities.Message{}, nil)
+				return NewOutboxRelay(
+					OutboxRelayConfig{BatchSize: 10, MaxAttempts: 3},
+					newTestRepository(t),
+					mockOutboxRepository,
+					mockMessageRepository,
+					mockClient,
+				)
+			},
+			wantSent: 1,
+			wantErr:  false,
+		},
+		{
+			name: "failed to mark messages as sent",
+			setup: func(t *testing.T) *OutboxRelay {
+				t.Helper()
+				mockOutboxRepository := mockUsecases.NewMockIOutboxRepository(t)
+				mockOutboxRepository.EXPECT().
+					GetPending(mock.MatchedBy(entities.IsAllTenants), mock.Anything, 10).
+					Return(pending[:1], nil)
+				mockOutboxRepository.EXPECT().
+					MarkSent(mock.Anything, mock.Anything, []uint{1}).
+					Return(errors.New("fake error"))
+				mockClient := mockUsecases.NewMockIClient(t)
+				mockClient.EXPECT().
+					Send(mock.Anything, mock.Anything).
+					Return(nil)
+				mockMessageRepository := mockUsecases.NewMockIMessageRepository(t)
+				mockMessageRepository.EXPECT().
+					CreateMany(mock.Anything, mock.Anything, mock.Anything).
+					Return([]entities.Message{}, nil)
+				return NewOutboxRelay(
+					OutboxRelayConfig{BatchSize: 10},
+					newTestRepository(t),
+					mockOutboxRepository,
+					mockMessageRepository,
+					mockClient,
+				)
+			},
+			wantSent: 0,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := tt.setup(t)
+			sent, err := r.relay(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("OutboxRelay.relay() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if sent != tt.wantSent {
+				t.Errorf("OutboxRelay.relay() sent = %v, want %v", sent, tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestOutboxRelay_StartAndStop(t *testing.T) {
+	t.Parallel()
+
+	mockOutboxRepository := mockUsecases.NewMockIOutboxRepository(t)
+	mockOutboxRepository.EXPECT().
+		GetPending(mock.Anything, mock.Anything, defaultOutboxBatchSize).
+		Return([]entities.OutboxMessage{}, nil)
+
+	r := NewOutboxRelay(
+		OutboxRelayConfig{PollInterval: 10 * time.Millisecond},
+		newTestRepository(t),
+		mockOutboxRepository,
+		mockUsecases.NewMockIMessageRepository(t),
+		mockUsecases.NewMockIClient(t),
+	)
+
+	if err := r.Start(context.Background()); err != nil {
+		t.Errorf("failed to start outbox relay: %v", err)
+		return
+	}
+
+	time.Sleep(55 * time.Millisecond)
+
+	if err := r.Stop(context.Background()); err != nil {
+		t.Errorf("failed to stop outbox relay: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+const (
+	defaultTenantLimit    = 100
+	maxTenantLimit        = 1000
+	defaultKnownTenantTTL = time.Minute
+)
+
+type TenantsConfig struct {
+	// KnownTenantTTL is how long CheckTenant trusts a tenant it found, without looking it up again
+	KnownTenantTTL time.Duration
+}
+
+type Tenants struct {
+	TenantsConfig
+	tenantRepository ITenantRepository
+	mutex            sync.RWMutex
+	// knownTenants are the expiries of the tenants found by CheckTenant
+	knownTenants map[uint]time.Time
+}
+
+func NewTenantsUsecase(config TenantsConfig, tenantRepository ITenantRepository) *Tenants {
+	if config.KnownTenantTTL <= 0 {
+		config.KnownTenantTTL = defaultKnownTenantTTL
+	}
+
+	return &Tenants{
+		TenantsConfig:    config,
+		tenantRepository: tenantRepository,
+		knownTenants:     make(map[uint]time.Time),
+	}
+}
+
+// CheckTenant returns an ErrInvalid error when the tenant does not exist, or is deleted.
+// The tenants found are trusted for KnownTenantTTL, a deleted tenant may then be accepted for that long.
+func (u *Tenants) CheckTenant(ctx context.Context, tenantID uint) error {
+	if tenantID == entities.DefaultTenantID {
+		return nil
+	}
+
+	now := time.Now()
+	u.mutex.RLock()
+	expiry, ok := u.knownTenants[tenantID]
+	u.mutex.RUnlock()
+	if ok && now.Before(expiry) {
+		return nil
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	if _, err := u.tenantRepository.Get(timeoutCtx, nil, tenantID); err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return fmt.Errorf("%w - unknown tenant %d", entities.ErrInvalid, tenantID)
+		}
+		return fmt.Errorf("failed to check tenant %d: %w", tenantID, err)
+	}
+
+	u.mutex.Lock()
+	u.knownTenants[tenantID] = now.Add(u.KnownTenantTTL)
+	u.mutex.Unlock()
+	return nil
+}
+
+func (u *Tenants) CreateTenant(ctx context.Context, tenant *entities.Tenant) (*entities.Tenant, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	if tenant == nil || tenant.Name == "" {
+		return nil, fmt.Errorf("%w - tenant name is empty", entities.ErrInvalid)
+	}
+
+	out, err := u.tenantRepository.Create(timeoutCtx, nil, &entities.Tenant{Name: tenant.Name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tenant: %w", err)
+	}
+
+	return out, nil
+}
+
+// ListTenants returns the tenants in creation order, the soft deleted ones too when showDeleted is set.
+func (u *Tenants) ListTenants(ctx context.Context, offset, limit int, showDeleted bool) ([]entities.Tenant, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	if offset < 0 || limit < 0 || limit > maxTenantLimit {
+		return nil, fmt.Errorf("%w - invalid offset %d or limit %d", entities.ErrInvalid, offset, limit)
+	}
+	if limit == 0 {
+		limit = defaultTenantLimit
+	}
+	var opts []entities.QueryOption
+	if showDeleted {
+		opts = append(opts, entities.WithDeleted())
+	}
+
+	tenants, err := u.tenantRepository.GetManyByCriterias(
+		timeoutCtx, nil,
+		nil,
+		nil,
+		[]string{"id"},
+		offset,
+		limit,
+		opts...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	return tenants, nil
+}
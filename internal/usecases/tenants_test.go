@@ -0,0 +1,252 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	mockUsecases "github.com/tuantran1810/go-di-template/mocks/usecases"
+)
+
+func TestTenants_CreateTenant(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	created := &entities.Tenant{ID: 2, CreatedAt: now, UpdatedAt: now, Name: "tenant2"}
+
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T) *Tenants
+		tenant  *entities.Tenant
+		want    *entities.Tenant
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(t *testing.T) *Tenants {
+				repository := mockUsecases.NewMockITenantRepository(t)
+				repository.EXPECT().
+					Create(mock.Anything, mock.Anything, &entities.Tenant{Name: "tenant2"}).
+					Return(created, nil)
+				return NewTenantsUsecase(TenantsConfig{}, repository)
+			},
+			tenant: &entities.Tenant{ID: 10, Name: "tenant2"},
+			want:   created,
+		},
+		{
+			name: "repository error",
+			setup: func(t *testing.T) *Tenants {
+				repository := mockUsecases.NewMockITenantRepository(t)
+				repository.EXPECT().
+					Create(mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, entities.ErrDatabase)
+				return NewTenantsUsecase(TenantsConfig{}, repository)
+			},
+			tenant:  &entities.Tenant{Name: "tenant2"},
+			wantErr: entities.ErrDatabase,
+		},
+		{
+			name: "empty name",
+			setup: func(t *testing.T) *Tenants {
+				return NewTenantsUsecase(TenantsConfig{}, mockUsecases.NewMockITenantRepository(t))
+			},
+			tenant:  &entities.Tenant{},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name: "nil tenant",
+			setup: func(t *testing.T) *Tenants {
+				return NewTenantsUsecase(TenantsConfig{}, mockUsecases.NewMockITenantRepository(t))
+			},
+			wantErr: entities.ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.setup(t).CreateTenant(context.Background(), tt.tenant)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Tenants.CreateTenant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tenants.CreateTenant() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTenants_ListTenants(t *testing.T) {
+	t.Parallel()
+	tenants := []entities.Tenant{
+		{ID: 1, Name: entities.DefaultTenantName},
+		{ID: 2, Name: "tenant2"},
+	}
+
+	tests := []struct {
+		name        string
+		setup       func(t *testing.T) *Tenants
+		offset      int
+		limit       int
+		showDeleted bool
+		want        []entities.Tenant
+		wantErr     error
+	}{
+		{
+			name: "default limit",
+			setup: func(t *testing.T) *Tenants {
+				repository := mockUsecases.NewMockITenantRepository(t)
+				repository.EXPECT().
+					GetManyByCriterias(
+						mock.Anything, mock.Anything,
+						[]string(nil),
+						map[string]any(nil),
+						[]string{"id"},
+						0, defaultTenantLimit,
+					).
+					Return(tenants, nil)
+				return NewTenantsUsecase(TenantsConfig{}, repository)
+			},
+			want: tenants,
+		},
+		{
+			name: "offset and limit",
+			setup: func(t *testing.T) *Tenants {
+				repository := mockUsecases.NewMockITenantRepository(t)
+				repository.EXPECT().
+					GetManyByCriterias(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, 1, 1).
+					Return(tenants[1:], nil)
+				return NewTenantsUsecase(TenantsConfig{}, repository)
+			},
+			offset: 1,
+			limit:  1,
+			want:   tenants[1:],
+		},
+		{
+			name: "show deleted",
+			setup: func(t *testing.T) *Tenants {
+				repository := mockUsecases.NewMockITenantRepository(t)
+				repository.EXPECT().
+					GetManyByCriterias(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, 0, defaultTenantLimit, mock.Anything).
+					RunAndReturn(func(
+						_ context.Context, _ entities.Transaction, _ []string, _ map[string]any, _ []string, _, _ int,
+						opts ...entities.QueryOption,
+					) ([]entities.Tenant, error) {
+						if entities.NewQueryOptions(opts...).Deleted != entities.DeletedScopeInclude {
+							return nil, entities.ErrInvalid
+						}
+						return tenants, nil
+					})
+				return NewTenantsUsecase(TenantsConfig{}, repository)
+			},
+			showDeleted: true,
+			want:        tenants,
+		},
+		{
+			name: "repository error",
+			setup: func(t *testing.T) *Tenants {
+				repository := mockUsecases.NewMockITenantRepository(t)
+				repository.EXPECT().
+					GetManyByCriterias(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, entities.ErrDatabase)
+				return NewTenantsUsecase(TenantsConfig{}, repository)
+			},
+			wantErr: entities.ErrDatabase,
+		},
+		{
+			name: "limit too large",
+			setup: func(t *testing.T) *Tenants {
+				return NewTenantsUsecase(TenantsConfig{}, mockUsecases.NewMockITenantRepository(t))
+			},
+			limit:   maxTenantLimit + 1,
+			wantErr: entities.ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.setup(t).ListTenants(context.Background(), tt.offset, tt.limit, tt.showDeleted)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Tenants.ListTenants() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tenants.ListTenants() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTenants_CheckTenant(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T) *Tenants
+		tenantID uint
+		wantErr  error
+	}{
+		{
+			name: "default tenant",
+			setup: func(t *testing.T) *Tenants {
+				return NewTenantsUsecase(TenantsConfig{}, mockUsecases.NewMockITenantRepository(t))
+			},
+			tenantID: entities.DefaultTenantID,
+		},
+		{
+			name: "existing tenant is looked up once",
+			setup: func(t *testing.T) *Tenants {
+				repository := mockUsecases.NewMockITenantRepository(t)
+				repository.EXPECT().
+					Get(mock.Anything, mock.Anything, uint(2)).
+					Return(&entities.Tenant{ID: 2, Name: "tenant2"}, nil).
+					Once()
+				u := NewTenantsUsecase(TenantsConfig{}, repository)
+				if err := u.CheckTenant(context.Background(), 2); err != nil {
+					t.Fatalf("Tenants.CheckTenant() error = %v", err)
+				}
+				return u
+			},
+			tenantID: 2,
+		},
+		{
+			name: "unknown tenant",
+			setup: func(t *testing.T) *Tenants {
+				repository := mockUsecases.NewMockITenantRepository(t)
+				repository.EXPECT().
+					Get(mock.Anything, mock.Anything, uint(999)).
+					Return(nil, entities.ErrNotFound)
+				return NewTenantsUsecase(TenantsConfig{}, repository)
+			},
+			tenantID: 999,
+			wantErr:  entities.ErrInvalid,
+		},
+		{
+			name: "repository error",
+			setup: func(t *testing.T) *Tenants {
+				repository := mockUsecases.NewMockITenantRepository(t)
+				repository.EXPECT().
+					Get(mock.Anything, mock.Anything, uint(2)).
+					Return(nil, entities.ErrDatabase)
+				return NewTenantsUsecase(TenantsConfig{}, repository)
+			},
+			tenantID: 2,
+			wantErr:  entities.ErrDatabase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := tt.setup(t).CheckTenant(context.Background(), tt.tenantID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Tenants.CheckTenant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+// TxManager is the unit of work of the usecase layer. It keeps the running
+// transaction in the context, so repository calls made with that context join
+// it without passing entities.Transaction around.
+type TxManager struct {
+	repository IRepository
+}
+
+func NewTxManager(repository IRepository) *TxManager {
+	return &TxManager{
+		repository: repository,
+	}
+}
+
+// RunInTx runs fn inside a transaction. If ctx already carries a transaction, fn joins it,
+// otherwise a new one is started and committed when fn returns nil, or rolled back on error.
+func (m *TxManager) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if entities.GetTransactionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	return m.repository.RunTx(
+		ctx,
+		func(ictx context.Context, dbtx entities.Transaction) error {
+			return fn(entities.InjectTransactionToContext(ictx, dbtx))
+		},
+	)
+}
@@ -0,0 +1,92 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	mockUsecases "github.com/tuantran1810/go-di-template/mocks/usecases"
+)
+
+type fakeTransaction struct {
+	name string
+}
+
+func (t *fakeTransaction) GetTransaction() any {
+	return t.name
+}
+
+func TestTxManager_RunInTx(t *testing.T) {
+	t.Parallel()
+
+	newTx := &fakeTransaction{name: "new"}
+	existingTx := &fakeTransaction{name: "existing"}
+
+	mockRepository := mockUsecases.NewMockIRepository(t)
+	mockRepository.On("RunTx", mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, funcs ...entities.DBTxHandleFunc) error {
+			for _, f := range funcs {
+				if err := f(ctx, newTx); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+
+	m := NewTxManager(mockRepository)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		fnErr   error
+		wantTx  entities.Transaction
+		wantErr bool
+	}{
+		{
+			name:    "start a new transaction",
+			ctx:     context.Background(),
+			wantTx:  newTx,
+			wantErr: false,
+		},
+		{
+			name:    "join the transaction in context",
+			ctx:     entities.InjectTransactionToContext(context.Background(), existingTx),
+			wantTx:  existingTx,
+			wantErr: false,
+		},
+		{
+			name:    "error from handler",
+			ctx:     context.Background(),
+			fnErr:   errors.New("fake error"),
+			wantTx:  newTx,
+			wantErr: true,
+		},
+		{
+			name:    "error from handler in joined transaction",
+			ctx:     entities.InjectTransactionToContext(context.Background(), existingTx),
+			fnErr:   errors.New("fake error"),
+			wantTx:  existingTx,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var gotTx entities.Transaction
+			err := m.RunInTx(tt.ctx, func(ctx context.Context) error {
+				gotTx = entities.GetTransactionFromContext(ctx)
+				return tt.fnErr
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TxManager.RunInTx() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotTx != tt.wantTx {
+				t.Errorf("TxManager.RunInTx() transaction = %v, want %v", gotTx, tt.wantTx)
+			}
+		})
+	}
+}
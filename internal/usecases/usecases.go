@@ -12,22 +12,64 @@ var log = logger.MustNamedLogger("usecases")
 
 const defaultTimeout = 10 * time.Second
 
+type IRepository interface {
+	RunTx(ctx context.Context, funcs ...entities.DBTxHandleFunc) error
+}
+
 type IUserRepository interface {
 	Create(ctx context.Context, tx entities.Transaction, user *entities.User) (*entities.User, error)
-	FindByUsername(ctx context.Context, tx entities.Transaction, username string) (*entities.User, error)
-	RunTx(ctx context.Context, funcs ...entities.DBTxHandleFunc) error
+	FindByUsername(ctx context.Context, tx entities.Transaction, username string, opts ...entities.QueryOption) (*entities.User, error)
+	FindByUsernames(ctx context.Context, tx entities.Transaction, usernames []string, opts ...entities.QueryOption) ([]entities.User, error)
+	FindByUuids(ctx context.Context, tx entities.Transaction, uuids []string, opts ...entities.QueryOption) ([]entities.User, error)
+	Aggregate(ctx context.Context, tx entities.Transaction, query entities.AggregateQuery) ([]entities.AggregateRow, error)
 }
 
 type IUserAttributeRepository interface {
 	CreateMany(ctx context.Context, tx entities.Transaction, userAttributes []entities.UserAttribute) ([]entities.UserAttribute, error)
 	GetByUserID(ctx context.Context, tx entities.Transaction, userID uint) ([]entities.UserAttribute, error)
 	GetManyByUserName(ctx context.Context, tx entities.Transaction, userName string) ([]entities.UserAttribute, error)
+	Aggregate(ctx context.Context, tx entities.Transaction, query entities.AggregateQuery) ([]entities.AggregateRow, error)
 }
 
 type IMessageRepository interface {
 	CreateMany(ctx context.Context, tx entities.Transaction, messages []entities.Message) ([]entities.Message, error)
 }
 
+type IOutboxRepository interface {
+	Create(ctx context.Context, tx entities.Transaction, message *entities.OutboxMessage) (*entities.OutboxMessage, error)
+	GetPending(ctx context.Context, tx entities.Transaction, limit int) ([]entities.OutboxMessage, error)
+	MarkSent(ctx context.Context, tx entities.Transaction, ids []uint) error
+	MarkFailed(ctx context.Context, tx entities.Transaction, id uint, reason string, dead bool) error
+}
+
+type IAuditEventRepository interface {
+	GetManyByCriterias(
+		ctx context.Context,
+		tx entities.Transaction,
+		fields []string,
+		criterias map[string]any,
+		orderBys []string,
+		offset int,
+		limit int,
+		opts ...entities.QueryOption,
+	) ([]entities.AuditEvent, error)
+}
+
+type ITenantRepository interface {
+	Create(ctx context.Context, tx entities.Transaction, tenant *entities.Tenant) (*entities.Tenant, error)
+	Get(ctx context.Context, tx entities.Transaction, id uint, opts ...entities.QueryOption) (*entities.Tenant, error)
+	GetManyByCriterias(
+		ctx context.Context,
+		tx entities.Transaction,
+		fields []string,
+		criterias map[string]any,
+		orderBys []string,
+		offset int,
+		limit int,
+		opts ...entities.QueryOption,
+	) ([]entities.Tenant, error)
+}
+
 type IClient interface {
 	Send(ctx context.Context, msg *entities.Message) error
 }
@@ -3,39 +3,86 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/tuantran1810/go-di-template/internal/entities"
 	"github.com/tuantran1810/go-di-template/libs/utils"
 )
 
+// defaultBatchGetMaxKeys is the number of usernames and uuids of a batch get unless configured
+const defaultBatchGetMaxKeys = 100
+
+// the read methods of Users whose concurrent identical calls can be coalesced
+const (
+	methodGetUserByUsername       = "GetUserByUsername"
+	methodBatchGetUsers           = "BatchGetUsers"
+	methodGetAttributesByUsername = "GetAttributesByUsername"
+	methodGetUserStats            = "GetUserStats"
+)
+
+type UsersConfig struct {
+	// BatchGetMaxKeys is the number of usernames and uuids a batch get looks up at most
+	BatchGetMaxKeys int
+	// CoalescedMethods are the read methods whose concurrent calls with the same arguments share a single call
+	// to the repositories, e.g. GetUserByUsername, none when empty
+	CoalescedMethods []string
+}
+
 type Users struct {
+	UsersConfig
+	txManager               *TxManager
 	userRepository          IUserRepository
 	userAttributeRepository IUserAttributeRepository
+	outboxRepository        IOutboxRepository
 	uuidGenerator           IUUIDGenerator
+	coalescer               *coalescer
 }
 
 func NewUsersUsecase(
+	config UsersConfig,
+	repository IRepository,
 	userRepository IUserRepository,
 	userAttributeRepository IUserAttributeRepository,
+	outboxRepository IOutboxRepository,
 ) *Users {
+	if config.BatchGetMaxKeys <= 0 {
+		config.BatchGetMaxKeys = defaultBatchGetMaxKeys
+	}
+
 	return &Users{
+		UsersConfig:             config,
+		txManager:               NewTxManager(repository),
 		userRepository:          userRepository,
 		userAttributeRepository: userAttributeRepository,
+		outboxRepository:        outboxRepository,
 		uuidGenerator:           &utils.UUIDGenerator{},
+		coalescer: newCoalescer(
+			config.CoalescedMethods,
+			methodGetUserByUsername, methodBatchGetUsers, methodGetAttributesByUsername, methodGetUserStats,
+		),
 	}
 }
 
 func (u *Users) createUserImpl(
 	ctx context.Context,
-	dbtx entities.Transaction,
 	user *entities.User,
 	attributes []entities.KeyValuePair,
 ) (*entities.User, []entities.UserAttribute, error) {
-	outUser, err := u.userRepository.Create(ctx, dbtx, user)
+	outUser, err := u.userRepository.Create(ctx, nil, user)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	// the event is committed together with the user, the outbox relay publishes it later
+	if _, err := u.outboxRepository.Create(ctx, nil, &entities.OutboxMessage{
+		Key:   "user_created",
+		Value: fmt.Sprintf("user_id: %d, username: %s", outUser.ID, outUser.Username),
+	}); err != nil {
+		return nil, nil, fmt.Errorf("failed to create outbox message: %w", err)
+	}
+
 	atts := make([]entities.UserAttribute, len(attributes))
 	if len(attributes) == 0 {
 		return outUser, atts, nil
@@ -49,7 +96,7 @@ func (u *Users) createUserImpl(
 		}
 	}
 
-	outAttributes, err := u.userAttributeRepository.CreateMany(ctx, dbtx, atts)
+	outAttributes, err := u.userAttributeRepository.CreateMany(ctx, nil, atts)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create user attributes: %w", err)
 	}
@@ -66,11 +113,11 @@ func (u *Users) CreateUser(ctx context.Context, user *entities.User, attributes
 	var outUser *entities.User
 	outAttributes := make([]entities.UserAttribute, 0)
 
-	if err := u.userRepository.RunTx(
+	if err := u.txManager.RunInTx(
 		timeoutCtx,
-		func(ictx context.Context, dbtx entities.Transaction) error {
+		func(ictx context.Context) error {
 			var ierr error
-			outUser, outAttributes, ierr = u.createUserImpl(ictx, dbtx, user, attributes)
+			outUser, outAttributes, ierr = u.createUserImpl(ictx, user, attributes)
 			if ierr != nil {
 				return fmt.Errorf("failed to create user: %w", ierr)
 			}
@@ -83,28 +130,182 @@ func (u *Users) CreateUser(ctx context.Context, user *entities.User, attributes
 	return outUser, outAttributes, nil
 }
 
-func (u *Users) GetUserByUsername(ctx context.Context, username string) (*entities.User, []entities.UserAttribute, error) {
+// userQueryOptions are the options of the lookups of the users with their attributes,
+// reading the soft deleted ones too when showDeleted is set.
+func userQueryOptions(showDeleted bool) []entities.QueryOption {
+	opts := []entities.QueryOption{entities.Preload("Attributes")}
+	if showDeleted {
+		opts = append(opts, entities.WithDeleted())
+	}
+
+	return opts
+}
+
+func (u *Users) getUserByUsernameImpl(ctx context.Context, username string, showDeleted bool) (*entities.User, error) {
 	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	if username == "" {
-		return nil, nil, fmt.Errorf("%w - input username is empty", entities.ErrInvalid)
+		return nil, fmt.Errorf("%w - input username is empty", entities.ErrInvalid)
+	}
+
+	if showDeleted {
+		user, err := u.userRepository.FindByUsername(timeoutCtx, nil, username, userQueryOptions(showDeleted)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user by username: %w", err)
+		}
+		return user, nil
 	}
 
+	// the plain lookups are served by the read-through cache, a lookup with a preload is not
 	user, err := u.userRepository.FindByUsername(timeoutCtx, nil, username)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to find user by username: %w", err)
+		return nil, fmt.Errorf("failed to find user by username: %w", err)
+	}
+	attributes, err := u.userAttributeRepository.GetByUserID(timeoutCtx, nil, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attributes of user: %w", err)
 	}
+	user.Attributes = attributes
 
-	atts, err := u.userAttributeRepository.GetByUserID(timeoutCtx, nil, user.ID)
+	return user, nil
+}
+
+// GetUserByUsername gets a user with its attributes, by the cached lookups of the user and of its attributes.
+// showDeleted gets a soft deleted user too, with its soft deleted attributes, preloaded by the lookup of the user.
+func (u *Users) GetUserByUsername(
+	ctx context.Context,
+	username string,
+	showDeleted bool,
+) (*entities.User, []entities.UserAttribute, error) {
+	key := strconv.FormatBool(showDeleted) + "\x00" + username
+	user, err := coalesce(ctx, u.coalescer, methodGetUserByUsername, key,
+		func(ctx context.Context) (*entities.User, error) {
+			return u.getUserByUsernameImpl(ctx, username, showDeleted)
+		},
+	)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get user attributes: %w", err)
+		return nil, nil, err
+	}
+
+	atts := user.Attributes
+	if atts == nil {
+		atts = make([]entities.UserAttribute, 0)
 	}
 
 	return user, atts, nil
 }
 
+// BatchGetUsers gets the users of the usernames and uuids with their attributes, by a query for the users of each kind
+// of key and a query for their attributes whatever the number of keys. The users are in the order of the first of their
+// keys, the usernames before the uuids, and the keys of no user are returned as missing, in the order of the input.
+// showDeleted gets the soft deleted users too, with their soft deleted attributes.
+func (u *Users) BatchGetUsers(
+	ctx context.Context,
+	usernames, uuids []string,
+	showDeleted bool,
+) ([]entities.User, []string, error) {
+	if len(usernames) == 0 && len(uuids) == 0 {
+		return nil, nil, fmt.Errorf("%w - input usernames and uuids are empty", entities.ErrInvalid)
+	}
+	if len(usernames)+len(uuids) > u.BatchGetMaxKeys {
+		return nil, nil, fmt.Errorf(
+			"%w - %d usernames and uuids, more than %d", entities.ErrInvalid, len(usernames)+len(uuids), u.BatchGetMaxKeys,
+		)
+	}
+
+	key := strconv.FormatBool(showDeleted) + "\x01" + strings.Join(usernames, "\x00") + "\x01" + strings.Join(uuids, "\x00")
+	result, err := coalesce(ctx, u.coalescer, methodBatchGetUsers, key,
+		func(ctx context.Context) (batchGetResult, error) {
+			return u.batchGetUsersImpl(ctx, usernames, uuids, showDeleted)
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result.users, result.missing, nil
+}
+
+type batchGetResult struct {
+	users   []entities.User
+	missing []string
+}
+
+func (u *Users) batchGetUsersImpl(ctx context.Context, usernames, uuids []string, showDeleted bool) (batchGetResult, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	users := make([]entities.User, 0, len(usernames)+len(uuids))
+	missing := make([]string, 0)
+	found := make(map[uint]bool)
+	lookups := []struct {
+		keys []string
+		find func(ctx context.Context, tx entities.Transaction, keys []string, opts ...entities.QueryOption) ([]entities.User, error)
+		key  func(user *entities.User) string
+	}{
+		{keys: usernames, find: u.userRepository.FindByUsernames, key: func(user *entities.User) string { return user.Username }},
+		{keys: uuids, find: u.userRepository.FindByUuids, key: func(user *entities.User) string { return user.Uuid }},
+	}
+	for _, lookup := range lookups {
+		keys := uniqueKeys(lookup.keys)
+		if len(keys) == 0 {
+			continue
+		}
+
+		outUsers, err := lookup.find(timeoutCtx, nil, keys, userQueryOptions(showDeleted)...)
+		if err != nil {
+			return batchGetResult{}, fmt.Errorf("failed to find users: %w", err)
+		}
+
+		byKey := make(map[string]*entities.User, len(outUsers))
+		for i := range outUsers {
+			byKey[lookup.key(&outUsers[i])] = &outUsers[i]
+		}
+		for _, key := range keys {
+			user, ok := byKey[key]
+			if !ok {
+				missing = append(missing, key)
+				continue
+			}
+			if found[user.ID] {
+				continue
+			}
+			found[user.ID] = true
+			out := *user
+			if out.Attributes == nil {
+				out.Attributes = make([]entities.UserAttribute, 0)
+			}
+			users = append(users, out)
+		}
+	}
+
+	return batchGetResult{users: users, missing: missing}, nil
+}
+
+// uniqueKeys returns the keys without their repetitions, in their order.
+func uniqueKeys(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	unique := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+
+	return unique
+}
+
 func (u *Users) GetAttributesByUsername(ctx context.Context, username string) ([]entities.UserAttribute, error) {
+	return coalesce(ctx, u.coalescer, methodGetAttributesByUsername, username,
+		func(ctx context.Context) ([]entities.UserAttribute, error) {
+			return u.getAttributesByUsernameImpl(ctx, username)
+		},
+	)
+}
+
+func (u *Users) getAttributesByUsernameImpl(ctx context.Context, username string) ([]entities.UserAttribute, error) {
 	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
@@ -119,3 +320,105 @@ func (u *Users) GetAttributesByUsername(ctx context.Context, username string) ([
 
 	return atts, nil
 }
+
+const (
+	// defaultStatsBuckets is the number of time buckets of the user stats when the query has no start
+	defaultStatsBuckets     = 30
+	maxStatsBuckets         = 366
+	maxStatsAttributeValues = 100
+)
+
+// GetUserStats counts the users created per time bucket, by day unless set, over the 30 last buckets unless set,
+// and the values of the attributes of the key of the query.
+func (u *Users) GetUserStats(ctx context.Context, query entities.UserStatsQuery) (*entities.UserStats, error) {
+	// the query before its defaults, so that the calls with the same query share the result of the first one
+	key := strings.Join([]string{
+		string(query.Bucket), query.From.Format(time.RFC3339Nano), query.To.Format(time.RFC3339Nano), query.AttributeKey,
+	}, "\x00")
+	return coalesce(ctx, u.coalescer, methodGetUserStats, key,
+		func(ctx context.Context) (*entities.UserStats, error) {
+			return u.getUserStatsImpl(ctx, query)
+		},
+	)
+}
+
+func (u *Users) getUserStatsImpl(ctx context.Context, query entities.UserStatsQuery) (*entities.UserStats, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	if query.Bucket == "" {
+		query.Bucket = entities.TimeBucketDay
+	}
+	if !query.Bucket.Valid() {
+		return nil, fmt.Errorf("%w - unknown time bucket %q", entities.ErrInvalid, query.Bucket)
+	}
+	if query.To.IsZero() {
+		query.To = time.Now()
+	}
+	if query.From.IsZero() {
+		query.From = query.Bucket.Add(query.Bucket.Truncate(query.To), 1-defaultStatsBuckets)
+	}
+	if !query.From.Before(query.To) {
+		return nil, fmt.Errorf("%w - stats start %v is not before end %v", entities.ErrInvalid, query.From, query.To)
+	}
+
+	buckets := make([]time.Time, 0)
+	for start := query.Bucket.Truncate(query.From); start.Before(query.To); start = query.Bucket.Add(start, 1) {
+		if len(buckets) == maxStatsBuckets {
+			return nil, fmt.Errorf("%w - more than %d time buckets", entities.ErrInvalid, maxStatsBuckets)
+		}
+		buckets = append(buckets, start)
+	}
+
+	rows, err := u.userRepository.Aggregate(timeoutCtx, nil, entities.AggregateQuery{
+		GroupBys:   []entities.GroupBy{{Column: "created_at", Bucket: query.Bucket}},
+		Aggregates: []entities.Aggregate{{Func: entities.AggregateCount}},
+		Criterias: map[string]any{
+			"created_at >= ?": query.From,
+			"created_at < ?":  query.To,
+		},
+		Limit: maxStatsBuckets,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count signups: %w", err)
+	}
+
+	counts := make(map[time.Time]uint64, len(rows))
+	for _, row := range rows {
+		if start, ok := row.Groups[0].(time.Time); ok {
+			counts[start] = uint64(row.Values[0])
+		}
+	}
+	stats := &entities.UserStats{
+		Signups:         make([]entities.SignupCount, 0, len(buckets)),
+		AttributeValues: make([]entities.AttributeValueCount, 0),
+	}
+	for _, start := range buckets {
+		stats.Signups = append(stats.Signups, entities.SignupCount{BucketStart: start, Count: counts[start]})
+	}
+
+	if query.AttributeKey == "" {
+		return stats, nil
+	}
+
+	rows, err = u.userAttributeRepository.Aggregate(timeoutCtx, nil, entities.AggregateQuery{
+		GroupBys:     []entities.GroupBy{{Column: "value"}},
+		Aggregates:   []entities.Aggregate{{Func: entities.AggregateCount}},
+		Criterias:    map[string]any{"key": query.AttributeKey},
+		OrderByValue: true,
+		Limit:        maxStatsAttributeValues,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count attribute values: %w", err)
+	}
+
+	for _, row := range rows {
+		value, _ := row.Groups[0].(string)
+		stats.AttributeValues = append(stats.AttributeValues, entities.AttributeValueCount{
+			Value: value,
+			Count: uint64(row.Values[0]),
+		})
+	}
+
+	return stats, nil
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/internal/repositories/cache"
+	"github.com/tuantran1810/go-di-template/internal/repositories/memory"
 	mockUsecases "github.com/tuantran1810/go-di-template/mocks/usecases"
 )
 
@@ -99,9 +102,41 @@ func TestUsers_createUserImpl(t *testing.T) {
 		}).
 		Return(nil, errors.New("fake error"))
 
+	mockUserRepository.EXPECT().
+		Create(mock.Anything, mock.Anything, &entities.User{
+			Username: "test_outbox_failed",
+		}).
+		Return(&entities.User{
+			ID:        2,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Username:  "test_outbox_failed",
+		}, nil)
+
+	mockOutboxRepository := mockUsecases.NewMockIOutboxRepository(t)
+	mockOutboxRepository.EXPECT().
+		Create(mock.Anything, mock.Anything, &entities.OutboxMessage{
+			Key:   "user_created",
+			Value: "user_id: 1, username: test1",
+		}).
+		Return(&entities.OutboxMessage{
+			ID:        1,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Key:       "user_created",
+			Value:     "user_id: 1, username: test1",
+		}, nil)
+	mockOutboxRepository.EXPECT().
+		Create(mock.Anything, mock.Anything, &entities.OutboxMessage{
+			Key:   "user_created",
+			Value: "user_id: 2, username: test_outbox_failed",
+		}).
+		Return(nil, errors.New("fake error"))
+
 	u := &Users{
 		userRepository:          mockUserRepository,
 		userAttributeRepository: mockUserAttributeRepository,
+		outboxRepository:        mockOutboxRepository,
 	}
 
 	tests := []struct {
@@ -230,11 +265,26 @@ func TestUsers_createUserImpl(t *testing.T) {
 			wantAttributes: nil,
 			wantErr:        true,
 		},
+		{
+			name: "failed to create outbox message",
+			user: &entities.User{
+				Username: "test_outbox_failed",
+			},
+			attributes: []entities.KeyValuePair{
+				{
+					Key:   "key1",
+					Value: "value1",
+				},
+			},
+			wantUser:       nil,
+			wantAttributes: nil,
+			wantErr:        true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			gotUser, gotAttributes, err := u.createUserImpl(context.TODO(), nil, tt.user, tt.attributes)
+			gotUser, gotAttributes, err := u.createUserImpl(context.TODO(), tt.user, tt.attributes)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Users.createUserImpl() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -252,8 +302,8 @@ func TestUsers_CreateUser(t *testing.T) {
 	t.Parallel()
 	now := time.Now()
 
-	mockUserRepository := mockUsecases.NewMockIUserRepository(t)
-	mockUserRepository.On("RunTx", mock.Anything, mock.Anything).Return(
+	mockRepository := mockUsecases.NewMockIRepository(t)
+	mockRepository.On("RunTx", mock.Anything, mock.Anything).Return(
 		func(ctx context.Context, funcs ...entities.DBTxHandleFunc) error {
 			for _, f := range funcs {
 				if f != nil {
@@ -265,6 +315,7 @@ func TestUsers_CreateUser(t *testing.T) {
 			return nil
 		},
 	)
+	mockUserRepository := mockUsecases.NewMockIUserRepository(t)
 	mockUserAttributeRepository := mockUsecases.NewMockIUserAttributeRepository(t)
 	mockUUIDGenerator := mockUsecases.NewMockIUUIDGenerator(t)
 
@@ -353,9 +404,25 @@ func TestUsers_CreateUser(t *testing.T) {
 		}).
 		Return(nil, errors.New("fake error"))
 
+	mockOutboxRepository := mockUsecases.NewMockIOutboxRepository(t)
+	mockOutboxRepository.EXPECT().
+		Create(mock.Anything, mock.Anything, &entities.OutboxMessage{
+			Key:   "user_created",
+			Value: "user_id: 1, username: test1",
+		}).
+		Return(&entities.OutboxMessage{
+			ID:        1,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Key:       "user_created",
+			Value:     "user_id: 1, username: test1",
+		}, nil)
+
 	u := &Users{
+		txManager:               NewTxManager(mockRepository),
 		userRepository:          mockUserRepository,
 		userAttributeRepository: mockUserAttributeRepository,
+		outboxRepository:        mockOutboxRepository,
 		uuidGenerator:           mockUUIDGenerator,
 	}
 
@@ -504,8 +571,25 @@ func TestUsers_GetUserByUsername(t *testing.T) {
 	t.Parallel()
 	now := time.Now()
 
+	attributes := []entities.UserAttribute{
+		{
+			ID:        1,
+			CreatedAt: now,
+			UpdatedAt: now,
+			UserID:    1,
+			Key:       "key1",
+			Value:     "value1",
+		},
+		{
+			ID:        2,
+			CreatedAt: now,
+			UpdatedAt: now,
+			UserID:    1,
+			Key:       "key2",
+			Value:     "value2",
+		},
+	}
 	mockUserRepository := mockUsecases.NewMockIUserRepository(t)
-	mockUserAttributeRepository := mockUsecases.NewMockIUserAttributeRepository(t)
 
 	mockUserRepository.EXPECT().
 		FindByUsername(mock.Anything, mock.Anything, "test1").
@@ -525,13 +609,9 @@ func TestUsers_GetUserByUsername(t *testing.T) {
 		Return(nil, errors.New("fake error"))
 
 	mockUserRepository.EXPECT().
-		FindByUsername(mock.Anything, mock.Anything, "failed_atts").
-		Return(&entities.User{
-			ID:        2,
-			CreatedAt: now,
-			UpdatedAt: now,
-			Username:  "failed_atts",
-		}, nil)
+		FindByUsername(mock.Anything, mock.Anything, "attributes_failed").
+		Return(&entities.User{ID: 2, Username: "attributes_failed"}, nil)
+
 	mockUserRepository.EXPECT().
 		FindByUsername(mock.Anything, mock.Anything, "no_atts").
 		Return(&entities.User{
@@ -545,30 +625,27 @@ func TestUsers_GetUserByUsername(t *testing.T) {
 			Email:     &[]string{"no_atts@test.com"}[0],
 		}, nil)
 
+	deletedAt := now.Add(-time.Hour)
+	deleted := &entities.User{ID: 4, Username: "deleted", DeletedAt: &deletedAt, Attributes: attributes}
+	mockUserRepository.EXPECT().
+		FindByUsername(mock.Anything, mock.Anything, "deleted", mock.Anything).
+		RunAndReturn(func(_ context.Context, _ entities.Transaction, _ string, opts ...entities.QueryOption) (*entities.User, error) {
+			if options := entities.NewQueryOptions(opts...); options.Deleted != entities.DeletedScopeInclude {
+				return nil, entities.ErrNotFound
+			}
+			return deleted, nil
+		})
+	mockUserRepository.EXPECT().
+		FindByUsername(mock.Anything, mock.Anything, "deleted").
+		Return(nil, entities.ErrNotFound)
+
+	mockUserAttributeRepository := mockUsecases.NewMockIUserAttributeRepository(t)
 	mockUserAttributeRepository.EXPECT().
 		GetByUserID(mock.Anything, mock.Anything, uint(1)).
-		Return([]entities.UserAttribute{
-			{
-				ID:        1,
-				CreatedAt: now,
-				UpdatedAt: now,
-				UserID:    1,
-				Key:       "key1",
-				Value:     "value1",
-			},
-			{
-				ID:        2,
-				CreatedAt: now,
-				UpdatedAt: now,
-				UserID:    1,
-				Key:       "key2",
-				Value:     "value2",
-			},
-		}, nil)
+		Return(attributes, nil)
 	mockUserAttributeRepository.EXPECT().
 		GetByUserID(mock.Anything, mock.Anything, uint(2)).
 		Return(nil, errors.New("fake error"))
-
 	mockUserAttributeRepository.EXPECT().
 		GetByUserID(mock.Anything, mock.Anything, uint(3)).
 		Return([]entities.UserAttribute{}, nil)
@@ -579,43 +656,28 @@ func TestUsers_GetUserByUsername(t *testing.T) {
 	}
 
 	tests := []struct {
-		name     string
-		username string
-		want     *entities.User
-		want1    []entities.UserAttribute
-		wantErr  bool
+		name        string
+		username    string
+		showDeleted bool
+		want        *entities.User
+		want1       []entities.UserAttribute
+		wantErr     bool
 	}{
 		{
 			name:     "success",
 			username: "test1",
 			want: &entities.User{
-				ID:        1,
-				CreatedAt: now,
-				UpdatedAt: now,
-				Username:  "test1",
-				Password:  "test1",
-				Uuid:      "test1",
-				Name:      "test1",
-				Email:     &[]string{"test1@test.com"}[0],
-			},
-			want1: []entities.UserAttribute{
-				{
-					ID:        1,
-					CreatedAt: now,
-					UpdatedAt: now,
-					UserID:    1,
-					Key:       "key1",
-					Value:     "value1",
-				},
-				{
-					ID:        2,
-					CreatedAt: now,
-					UpdatedAt: now,
-					UserID:    1,
-					Key:       "key2",
-					Value:     "value2",
-				},
+				ID:         1,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+				Username:   "test1",
+				Password:   "test1",
+				Uuid:       "test1",
+				Name:       "test1",
+				Email:      &[]string{"test1@test.com"}[0],
+				Attributes: attributes,
 			},
+			want1: attributes,
 		},
 		{
 			name:     "failed to find user",
@@ -625,8 +687,8 @@ func TestUsers_GetUserByUsername(t *testing.T) {
 			wantErr:  true,
 		},
 		{
-			name:     "failed to get user attributes",
-			username: "failed_atts",
+			name:     "failed to get attributes",
+			username: "attributes_failed",
 			want:     nil,
 			want1:    nil,
 			wantErr:  true,
@@ -635,14 +697,15 @@ func TestUsers_GetUserByUsername(t *testing.T) {
 			name:     "no attributes",
 			username: "no_atts",
 			want: &entities.User{
-				ID:        3,
-				CreatedAt: now,
-				UpdatedAt: now,
-				Username:  "no_atts",
-				Password:  "no_atts",
-				Uuid:      "no_atts",
-				Name:      "no_atts",
-				Email:     &[]string{"no_atts@test.com"}[0],
+				ID:         3,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+				Username:   "no_atts",
+				Password:   "no_atts",
+				Uuid:       "no_atts",
+				Name:       "no_atts",
+				Email:      &[]string{"no_atts@test.com"}[0],
+				Attributes: []entities.UserAttribute{},
 			},
 			want1:   []entities.UserAttribute{},
 			wantErr: false,
@@ -654,11 +717,24 @@ func TestUsers_GetUserByUsername(t *testing.T) {
 			want1:    nil,
 			wantErr:  true,
 		},
+		{
+			name:        "deleted user shown",
+			username:    "deleted",
+			showDeleted: true,
+			want:        deleted,
+			want1:       attributes,
+		},
+		{
+			name:     "deleted user hidden",
+			username: "deleted",
+			wantErr:  true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got, got1, err := u.GetUserByUsername(context.TODO(), tt.username)
+
+			got, got1, err := u.GetUserByUsername(context.TODO(), tt.username, tt.showDeleted)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Users.GetUserByUsername() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -673,6 +749,209 @@ func TestUsers_GetUserByUsername(t *testing.T) {
 	}
 }
 
+func TestUsers_GetUserByUsername_Coalesced(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mockUserRepository := mockUsecases.NewMockIUserRepository(t)
+	mockUserRepository.EXPECT().
+		FindByUsername(mock.Anything, mock.Anything, "test1").
+		RunAndReturn(func(context.Context, entities.Transaction, string, ...entities.QueryOption) (*entities.User, error) {
+			close(started)
+			<-release
+			return &entities.User{ID: 1, Username: "test1"}, nil
+		}).
+		Once()
+	mockUserAttributeRepository := mockUsecases.NewMockIUserAttributeRepository(t)
+	mockUserAttributeRepository.EXPECT().
+		GetByUserID(mock.Anything, mock.Anything, uint(1)).
+		Return(nil, nil).
+		Once()
+
+	u := NewUsersUsecase(
+		UsersConfig{CoalescedMethods: []string{"GetUserByUsername"}},
+		nil, mockUserRepository, mockUserAttributeRepository, nil,
+	)
+
+	var wg sync.WaitGroup
+	for i := range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user, atts, err := u.GetUserByUsername(context.TODO(), "test1", false)
+			if err != nil || user.ID != 1 || atts == nil {
+				t.Errorf("Users.GetUserByUsername() = %v, %v, %v", user, atts, err)
+			}
+		}()
+		if i == 0 {
+			<-started
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+}
+
+type countingUserRepository struct {
+	*memory.UserRepository
+	finds int
+}
+
+func (s *countingUserRepository) FindByUsername(
+	ctx context.Context,
+	tx entities.Transaction,
+	username string,
+	opts ...entities.QueryOption,
+) (*entities.User, error) {
+	s.finds++
+	return s.UserRepository.FindByUsername(ctx, tx, username, opts...)
+}
+
+type countingUserAttributeRepository struct {
+	*memory.UserAttributeRepository
+	gets int
+}
+
+func (s *countingUserAttributeRepository) GetByUserID(
+	ctx context.Context,
+	tx entities.Transaction,
+	userID uint,
+) ([]entities.UserAttribute, error) {
+	s.gets++
+	return s.UserAttributeRepository.GetByUserID(ctx, tx, userID)
+}
+
+// TestUsers_GetUserByUsername_Cached checks that a user read again is served by the read-through cache,
+// until its attributes are written.
+func TestUsers_GetUserByUsername_Cached(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := memory.NewRepository()
+	userRepository := &countingUserRepository{UserRepository: memory.NewUserRepository(r)}
+	userAttributeRepository := &countingUserAttributeRepository{
+		UserAttributeRepository: memory.NewUserAttributeRepository(r, userRepository.UserRepository),
+	}
+	backend := cache.NewLRUBackend(10)
+	cachedUserRepository := cache.NewUserRepository(userRepository, backend, cache.Config{})
+	cachedUserAttributeRepository := cache.NewUserAttributeRepository(userAttributeRepository, backend, cache.Config{})
+	u := NewUsersUsecase(UsersConfig{}, r, cachedUserRepository, cachedUserAttributeRepository, nil)
+
+	user, err := cachedUserRepository.Create(ctx, nil, &entities.User{Username: "user1", Name: "name1"})
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if _, err := cachedUserAttributeRepository.CreateMany(ctx, nil, []entities.UserAttribute{
+		{UserID: user.ID, Key: "key1", Value: "value1"},
+	}); err != nil {
+		t.Fatalf("failed to create user attributes: %v", err)
+	}
+
+	for range 2 {
+		got, atts, err := u.GetUserByUsername(ctx, "user1", false)
+		if err != nil || got.ID != user.ID || len(atts) != 1 {
+			t.Fatalf("Users.GetUserByUsername() = %v, %v, %v", got, atts, err)
+		}
+	}
+	if userRepository.finds != 1 || userAttributeRepository.gets != 1 {
+		t.Errorf("repository reads = %d users, %d attributes, want 1 and 1 with a cache hit",
+			userRepository.finds, userAttributeRepository.gets)
+	}
+
+	if _, err := cachedUserAttributeRepository.CreateMany(ctx, nil, []entities.UserAttribute{
+		{UserID: user.ID, Key: "key2", Value: "value2"},
+	}); err != nil {
+		t.Fatalf("failed to create user attributes: %v", err)
+	}
+	if _, atts, err := u.GetUserByUsername(ctx, "user1", false); err != nil || len(atts) != 2 {
+		t.Errorf("Users.GetUserByUsername() after an attribute write = %v, %v, want 2 attributes", atts, err)
+	}
+	if userRepository.finds != 1 || userAttributeRepository.gets != 2 {
+		t.Errorf("repository reads = %d users, %d attributes, want 1 and 2", userRepository.finds, userAttributeRepository.gets)
+	}
+}
+
+func TestUsers_BatchGetUsers(t *testing.T) {
+	t.Parallel()
+
+	attributes := []entities.UserAttribute{{ID: 1, UserID: 1, Key: "key1", Value: "value1"}}
+	mockUserRepository := mockUsecases.NewMockIUserRepository(t)
+	mockUserRepository.EXPECT().
+		FindByUsernames(mock.Anything, mock.Anything, []string{"test1", "test2", "unknown"}, mock.Anything).
+		Return([]entities.User{
+			{ID: 1, Username: "test1", Uuid: "uuid1", Attributes: attributes},
+			{ID: 2, Username: "test2", Uuid: "uuid2", Attributes: []entities.UserAttribute{}},
+		}, nil)
+	mockUserRepository.EXPECT().
+		FindByUuids(mock.Anything, mock.Anything, []string{"uuid3", "uuid2", "uuid9"}, mock.Anything).
+		Return([]entities.User{
+			{ID: 2, Username: "test2", Uuid: "uuid2", Attributes: []entities.UserAttribute{}},
+			{ID: 3, Username: "test3", Uuid: "uuid3"},
+		}, nil)
+	mockUserRepository.EXPECT().
+		FindByUsernames(mock.Anything, mock.Anything, []string{"test_failed"}, mock.Anything).
+		Return(nil, errors.New("fake error"))
+
+	u := &Users{
+		UsersConfig:    UsersConfig{BatchGetMaxKeys: 7},
+		userRepository: mockUserRepository,
+	}
+
+	tests := []struct {
+		name        string
+		usernames   []string
+		uuids       []string
+		want        []entities.User
+		wantMissing []string
+		wantErr     bool
+	}{
+		{
+			name:      "success",
+			usernames: []string{"test1", "test2", "unknown", "test1"},
+			uuids:     []string{"uuid3", "uuid2", "uuid9"},
+			want: []entities.User{
+				{ID: 1, Username: "test1", Uuid: "uuid1", Attributes: attributes},
+				{ID: 2, Username: "test2", Uuid: "uuid2", Attributes: []entities.UserAttribute{}},
+				{ID: 3, Username: "test3", Uuid: "uuid3", Attributes: []entities.UserAttribute{}},
+			},
+			wantMissing: []string{"unknown", "uuid9"},
+		},
+		{
+			name:      "failed to find users",
+			usernames: []string{"test_failed"},
+			wantErr:   true,
+		},
+		{
+			name:      "too many keys",
+			usernames: []string{"test1", "test2", "test3", "test4"},
+			uuids:     []string{"uuid1", "uuid2", "uuid3", "uuid4"},
+			wantErr:   true,
+		},
+		{
+			name:    "no keys",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, gotMissing, err := u.BatchGetUsers(context.TODO(), tt.usernames, tt.uuids, false)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Users.BatchGetUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Users.BatchGetUsers() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotMissing, tt.wantMissing) {
+				t.Errorf("Users.BatchGetUsers() missing = %v, want %v", gotMissing, tt.wantMissing)
+			}
+		})
+	}
+}
+
 func TestUsers_GetAttributesByUsername(t *testing.T) {
 	t.Parallel()
 	now := time.Now()
@@ -774,3 +1053,119 @@ func TestUsers_GetAttributesByUsername(t *testing.T) {
 		})
 	}
 }
+
+func TestUsers_GetUserStats(t *testing.T) {
+	t.Parallel()
+	day := func(d int) time.Time { return time.Date(2025, time.March, d, 0, 0, 0, 0, time.UTC) }
+
+	mockUserRepository := mockUsecases.NewMockIUserRepository(t)
+	mockUserAttributeRepository := mockUsecases.NewMockIUserAttributeRepository(t)
+
+	mockUserRepository.EXPECT().
+		Aggregate(mock.Anything, mock.Anything, mock.MatchedBy(func(q entities.AggregateQuery) bool {
+			return q.Criterias["created_at >= ?"] == day(3)
+		})).
+		Return([]entities.AggregateRow{
+			{Groups: []any{day(3)}, Values: []float64{2}},
+			{Groups: []any{day(5)}, Values: []float64{1}},
+		}, nil)
+	mockUserRepository.EXPECT().
+		Aggregate(mock.Anything, mock.Anything, mock.MatchedBy(func(q entities.AggregateQuery) bool {
+			return q.Criterias["created_at >= ?"] == day(10)
+		})).
+		Return(nil, errors.New("fake error"))
+
+	mockUserAttributeRepository.EXPECT().
+		Aggregate(mock.Anything, mock.Anything, mock.MatchedBy(func(q entities.AggregateQuery) bool {
+			return q.Criterias["key"] == "plan"
+		})).
+		Return([]entities.AggregateRow{
+			{Groups: []any{"free"}, Values: []float64{5}},
+			{Groups: []any{"pro"}, Values: []float64{2}},
+		}, nil)
+	mockUserAttributeRepository.EXPECT().
+		Aggregate(mock.Anything, mock.Anything, mock.MatchedBy(func(q entities.AggregateQuery) bool {
+			return q.Criterias["key"] == "failed"
+		})).
+		Return(nil, errors.New("fake error"))
+
+	u := &Users{
+		userRepository:          mockUserRepository,
+		userAttributeRepository: mockUserAttributeRepository,
+	}
+
+	signups := []entities.SignupCount{
+		{BucketStart: day(3), Count: 2},
+		{BucketStart: day(4), Count: 0},
+		{BucketStart: day(5), Count: 1},
+	}
+
+	tests := []struct {
+		name    string
+		query   entities.UserStatsQuery
+		want    *entities.UserStats
+		wantErr error
+	}{
+		{
+			name:  "signups filled per day",
+			query: entities.UserStatsQuery{From: day(3), To: day(6)},
+			want: &entities.UserStats{
+				Signups:         signups,
+				AttributeValues: []entities.AttributeValueCount{},
+			},
+		},
+		{
+			name:  "signups and attribute values",
+			query: entities.UserStatsQuery{From: day(3), To: day(6), Bucket: entities.TimeBucketDay, AttributeKey: "plan"},
+			want: &entities.UserStats{
+				Signups: signups,
+				AttributeValues: []entities.AttributeValueCount{
+					{Value: "free", Count: 5},
+					{Value: "pro", Count: 2},
+				},
+			},
+		},
+		{
+			name:    "unknown bucket",
+			query:   entities.UserStatsQuery{From: day(3), To: day(6), Bucket: "year"},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name:    "from after to",
+			query:   entities.UserStatsQuery{From: day(6), To: day(3)},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name:    "too many buckets",
+			query:   entities.UserStatsQuery{From: day(1), To: day(1).AddDate(2, 0, 0)},
+			wantErr: entities.ErrInvalid,
+		},
+		{
+			name:    "failed to count signups",
+			query:   entities.UserStatsQuery{From: day(10), To: day(11)},
+			wantErr: errors.New("fake error"),
+		},
+		{
+			name:    "failed to count attribute values",
+			query:   entities.UserStatsQuery{From: day(3), To: day(6), AttributeKey: "failed"},
+			wantErr: errors.New("fake error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := u.GetUserStats(context.TODO(), tt.query)
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("Users.GetUserStats() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if errors.Is(tt.wantErr, entities.ErrInvalid) && !errors.Is(err, entities.ErrInvalid) {
+				t.Errorf("Users.GetUserStats() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Users.GetUserStats() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
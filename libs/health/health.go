@@ -0,0 +1,275 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/libs/logger"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+var log = logger.MustNamedLogger("health")
+
+const (
+	DefaultInterval = 5 * time.Second
+	DefaultTimeout  = 2 * time.Second
+)
+
+// Check is a check of a dependency of the server.
+type Check struct {
+	Name string
+	// Check returns nil when the dependency is healthy, it is canceled after Timeout
+	Check   func(ctx context.Context) error
+	Timeout time.Duration
+	// Critical checks make the server and their Services not ready when they fail, the others are only reported
+	Critical bool
+	// Services are the gRPC services depending on the check, besides the whole server
+	Services []string
+}
+
+// Result is the outcome of the last run of a check.
+type Result struct {
+	Healthy   bool      `json:"healthy"`
+	Critical  bool      `json:"critical"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is the readiness of the server with the results of its checks.
+type Report struct {
+	Ready  bool              `json:"ready"`
+	Checks map[string]Result `json:"checks"`
+}
+
+type Config struct {
+	// Interval is the period of the checks, DefaultInterval when 0
+	Interval time.Duration
+	// Timeout is the timeout of the checks without one, DefaultTimeout when 0
+	Timeout time.Duration
+}
+
+// Registry runs the checks of the dependencies registered by the components of the server periodically,
+// and reports them through the gRPC health service, per service, and the /livez and /readyz HTTP endpoints.
+type Registry struct {
+	Config
+	lock     sync.RWMutex
+	checks   []Check
+	results  map[string]Result
+	services map[string]struct{}
+	server   *health.Server
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func NewRegistry(config Config) *Registry {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+
+	server := health.NewServer()
+	// not ready until the checks ran once
+	server.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	return &Registry{
+		Config:   config,
+		results:  make(map[string]Result),
+		services: make(map[string]struct{}),
+		server:   server,
+	}
+}
+
+// Register adds a check, it must be called before Start.
+func (r *Registry) Register(check Check) {
+	if check.Timeout <= 0 {
+		check.Timeout = r.Timeout
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.checks = append(r.checks, check)
+	for _, service := range check.Services {
+		if _, ok := r.services[service]; !ok {
+			r.services[service] = struct{}{}
+			r.server.SetServingStatus(service, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+		}
+	}
+}
+
+// HealthServer is the gRPC health service reporting the statuses of the last run of the checks.
+func (r *Registry) HealthServer() grpc_health_v1.HealthServer {
+	return r.server
+}
+
+// Run runs all the checks concurrently and updates the gRPC statuses with their results.
+func (r *Registry) Run(ctx context.Context) Report {
+	r.lock.RLock()
+	checks := r.checks
+	r.lock.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ready := true
+	notServing := make(map[string]bool, len(r.services))
+	for i, check := range checks {
+		r.results[check.Name] = results[i]
+		if results[i].Healthy {
+			continue
+		}
+
+		log.Warnw("health check failed", "check", check.Name, "critical", check.Critical, "error", results[i].Error)
+		if !check.Critical {
+			continue
+		}
+
+		ready = false
+		for _, service := range check.Services {
+			notServing[service] = true
+		}
+	}
+
+	r.server.SetServingStatus("", servingStatus(ready))
+	for service := range r.services {
+		r.server.SetServingStatus(service, servingStatus(!notServing[service]))
+	}
+
+	return r.report()
+}
+
+func run(ctx context.Context, check Check) Result {
+	timeoutCtx, cancel := context.WithTimeout(ctx, check.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(timeoutCtx)
+	if err == nil && timeoutCtx.Err() != nil {
+		err = fmt.Errorf("timed out after %v", check.Timeout)
+	}
+
+	result := Result{
+		Healthy:   err == nil,
+		Critical:  check.Critical,
+		Duration:  time.Since(start).String(),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func servingStatus(serving bool) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if serving {
+		return grpc_health_v1.HealthCheckResponse_SERVING
+	}
+
+	return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+}
+
+// report must be called with the lock held.
+func (r *Registry) report() Report {
+	report := Report{Ready: len(r.results) == len(r.checks), Checks: make(map[string]Result, len(r.results))}
+	for _, check := range r.checks {
+		result, ok := r.results[check.Name]
+		if !ok {
+			continue
+		}
+		report.Checks[check.Name] = result
+		if !result.Healthy && check.Critical {
+			report.Ready = false
+		}
+	}
+
+	return report
+}
+
+// Report returns the results of the last run of the checks, not ready before the first one.
+func (r *Registry) Report() Report {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.report()
+}
+
+func (r *Registry) Start(_ context.Context) error {
+	log.Info("starting health registry")
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.Interval)
+		defer ticker.Stop()
+
+		for {
+			r.Run(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops the checks and reports the server as not serving, so that it is drained while shutting down.
+func (r *Registry) Stop(_ context.Context) error {
+	log.Info("stopping health registry")
+	if r.cancel != nil {
+		r.cancel()
+		<-r.done
+	}
+	r.server.Shutdown()
+
+	return nil
+}
+
+// LivenessHandler answers 200 as long as the server can handle a request, whatever its dependencies.
+func (r *Registry) LivenessHandler(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "alive"})
+}
+
+// ReadinessHandler answers the last report of the checks, with 200 when ready and 503 otherwise.
+func (r *Registry) ReadinessHandler(w http.ResponseWriter, _ *http.Request) {
+	report := r.Report()
+	status := http.StatusOK
+	if !report.Ready {
+		status = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, status, report)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Errorw("failed to write health response", "error", err)
+	}
+}
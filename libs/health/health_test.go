@@ -0,0 +1,213 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func healthy(_ context.Context) error {
+	return nil
+}
+
+func failing(_ context.Context) error {
+	return errors.New("unreachable")
+}
+
+func blocking(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func servingStatusOf(t *testing.T, r *Registry, service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := r.HealthServer().Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Check(%q) error = %v", service, err)
+	}
+
+	return resp.Status
+}
+
+func TestRegistry_Run(t *testing.T) {
+	t.Parallel()
+
+	serving := grpc_health_v1.HealthCheckResponse_SERVING
+	notServing := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+
+	tests := []struct {
+		name         string
+		checks       []Check
+		wantReady    bool
+		wantHealthy  map[string]bool
+		wantStatuses map[string]grpc_health_v1.HealthCheckResponse_ServingStatus
+	}{
+		{
+			name: "all healthy",
+			checks: []Check{
+				{Name: "database", Check: healthy, Critical: true, Services: []string{"users", "tenants"}},
+				{Name: "client", Check: healthy},
+			},
+			wantReady:    true,
+			wantHealthy:  map[string]bool{"database": true, "client": true},
+			wantStatuses: map[string]grpc_health_v1.HealthCheckResponse_ServingStatus{"": serving, "users": serving, "tenants": serving},
+		},
+		{
+			name: "non critical failure",
+			checks: []Check{
+				{Name: "database", Check: healthy, Critical: true, Services: []string{"users"}},
+				{Name: "client", Check: failing, Services: []string{"tenants"}},
+			},
+			wantReady:    true,
+			wantHealthy:  map[string]bool{"database": true, "client": false},
+			wantStatuses: map[string]grpc_health_v1.HealthCheckResponse_ServingStatus{"": serving, "users": serving, "tenants": serving},
+		},
+		{
+			name: "critical failure",
+			checks: []Check{
+				{Name: "database", Check: failing, Critical: true, Services: []string{"users"}},
+				{Name: "cache", Check: healthy, Critical: true, Services: []string{"tenants"}},
+			},
+			wantReady:    false,
+			wantHealthy:  map[string]bool{"database": false, "cache": true},
+			wantStatuses: map[string]grpc_health_v1.HealthCheckResponse_ServingStatus{"": notServing, "users": notServing, "tenants": serving},
+		},
+		{
+			name: "critical timeout",
+			checks: []Check{
+				{Name: "database", Check: blocking, Timeout: 10 * time.Millisecond, Critical: true, Services: []string{"users"}},
+			},
+			wantReady:    false,
+			wantHealthy:  map[string]bool{"database": false},
+			wantStatuses: map[string]grpc_health_v1.HealthCheckResponse_ServingStatus{"": notServing, "users": notServing},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewRegistry(Config{})
+			for _, check := range tt.checks {
+				r.Register(check)
+			}
+
+			report := r.Run(context.Background())
+			if report.Ready != tt.wantReady {
+				t.Errorf("Run().Ready = %v, want %v", report.Ready, tt.wantReady)
+			}
+			for name, want := range tt.wantHealthy {
+				if got := report.Checks[name]; got.Healthy != want || (got.Error == "") != want {
+					t.Errorf("Run().Checks[%q] = %+v, want healthy %v", name, got, want)
+				}
+			}
+			for service, want := range tt.wantStatuses {
+				if got := servingStatusOf(t, r, service); got != want {
+					t.Errorf("status of %q = %v, want %v", service, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistry_NotReadyBeforeRun(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(Config{})
+	r.Register(Check{Name: "database", Check: healthy, Critical: true, Services: []string{"users"}})
+
+	if r.Report().Ready {
+		t.Errorf("Report().Ready = true before the checks ran, want false")
+	}
+	for _, service := range []string{"", "users"} {
+		if got := servingStatusOf(t, r, service); got != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("status of %q = %v, want NOT_SERVING", service, got)
+		}
+	}
+}
+
+func TestRegistry_StartStop(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(Config{Interval: 10 * time.Millisecond})
+	r.Register(Check{Name: "database", Check: healthy, Critical: true})
+
+	if err := r.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !r.Report().Ready {
+		if time.Now().After(deadline) {
+			t.Fatalf("Report().Ready = false after the first run, want true")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := r.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if got := servingStatusOf(t, r, ""); got != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("status after Stop() = %v, want NOT_SERVING", got)
+	}
+}
+
+func TestRegistry_Handlers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		check         func(context.Context) error
+		wantLiveness  int
+		wantReadiness int
+	}{
+		{
+			name:          "healthy",
+			check:         healthy,
+			wantLiveness:  http.StatusOK,
+			wantReadiness: http.StatusOK,
+		},
+		{
+			name:          "critical failure",
+			check:         failing,
+			wantLiveness:  http.StatusOK,
+			wantReadiness: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewRegistry(Config{})
+			r.Register(Check{Name: "database", Check: tt.check, Critical: true})
+			r.Run(context.Background())
+
+			liveness := httptest.NewRecorder()
+			r.LivenessHandler(liveness, httptest.NewRequest(http.MethodGet, "/livez", nil))
+			if liveness.Code != tt.wantLiveness {
+				t.Errorf("LivenessHandler() code = %d, want %d", liveness.Code, tt.wantLiveness)
+			}
+
+			readiness := httptest.NewRecorder()
+			r.ReadinessHandler(readiness, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if readiness.Code != tt.wantReadiness {
+				t.Errorf("ReadinessHandler() code = %d, want %d", readiness.Code, tt.wantReadiness)
+			}
+
+			var report Report
+			if err := json.NewDecoder(readiness.Body).Decode(&report); err != nil {
+				t.Fatalf("failed to decode the readiness report: %v", err)
+			}
+			if _, ok := report.Checks["database"]; !ok || report.Ready != (tt.wantReadiness == http.StatusOK) {
+				t.Errorf("ReadinessHandler() report = %+v", report)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	gormutils "gorm.io/gorm/utils"
+)
+
+type GormLoggerConfig struct {
+	// LogLevel is the GORM log level: errors are logged from Error, slow queries from Warn and every query at Info
+	LogLevel gormlogger.LogLevel
+	// SlowThreshold is the duration above which a query is logged as slow, zero disables the detection
+	SlowThreshold time.Duration
+	// IgnoreRecordNotFoundError skips the error log of queries failing with gorm.ErrRecordNotFound
+	IgnoreRecordNotFoundError bool
+	// ShowParams logs the SQL with the parameter values, they are replaced by placeholders otherwise
+	ShowParams bool
+}
+
+// ParseGormLogLevel parses a GORM log level from silent, error, warn or info.
+func ParseGormLogLevel(level string) (gormlogger.LogLevel, error) {
+	switch strings.ToLower(level) {
+	case "silent":
+		return gormlogger.Silent, nil
+	case "error":
+		return gormlogger.Error, nil
+	case "warn":
+		return gormlogger.Warn, nil
+	case "info":
+		return gormlogger.Info, nil
+	default:
+		return 0, fmt.Errorf("unknown gorm log level: %s", level)
+	}
+}
+
+// GormLogger is a GORM logger writing to zap, tagging each statement with the correlation ID of its context.
+type GormLogger struct {
+	GormLoggerConfig
+	log *zap.SugaredLogger
+}
+
+func NewGormLogger(log *zap.SugaredLogger, config GormLoggerConfig) *GormLogger {
+	return &GormLogger{
+		GormLoggerConfig: config,
+		log:              log,
+	}
+}
+
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	newLogger := *l
+	newLogger.LogLevel = level
+	return &newLogger
+}
+
+func (l *GormLogger) withContext(ctx context.Context) *zap.SugaredLogger {
+	if correlationID, ok := utils.LookupCorrelationID(ctx); ok {
+		return l.log.With("correlation_id", correlationID)
+	}
+
+	return l.log
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...any) {
+	if l.LogLevel >= gormlogger.Info {
+		l.withContext(ctx).Infof(msg, data...)
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...any) {
+	if l.LogLevel >= gormlogger.Warn {
+		l.withContext(ctx).Warnf(msg, data...)
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...any) {
+	if l.LogLevel >= gormlogger.Error {
+		l.withContext(ctx).Errorf(msg, data...)
+	}
+}
+
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.LogLevel <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	keysAndValues := func() []any {
+		sql, rows := fc()
+		return []any{
+			"sql", sql,
+			"rows", rows,
+			"elapsed_ms", float64(elapsed.Nanoseconds()) / 1e6,
+			"source", gormutils.FileWithLineNum(),
+		}
+	}
+
+	switch {
+	case err != nil && l.LogLevel >= gormlogger.Error &&
+		(!errors.Is(err, gorm.ErrRecordNotFound) || !l.IgnoreRecordNotFoundError):
+		l.withContext(ctx).Errorw("query failed", append(keysAndValues(), "error", err.Error())...)
+	case l.SlowThreshold > 0 && elapsed > l.SlowThreshold && l.LogLevel >= gormlogger.Warn:
+		l.withContext(ctx).Warnw("slow query", append(keysAndValues(), "slow_threshold_ms", l.SlowThreshold.Milliseconds())...)
+	case l.LogLevel >= gormlogger.Info:
+		l.withContext(ctx).Infow("query", keysAndValues()...)
+	}
+}
+
+// ParamsFilter is called by GORM before rendering a statement for the logs,
+// dropping the parameters keeps their placeholders in the logged SQL.
+func (l *GormLogger) ParamsFilter(_ context.Context, sql string, params ...any) (string, []any) {
+	if l.ShowParams {
+		return sql, params
+	}
+
+	return sql, nil
+}
@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type secret struct {
+	ID    uint
+	Value string
+}
+
+func TestGormLogger_Trace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		config    GormLoggerConfig
+		query     func(db *gorm.DB) error
+		wantLevel zapcore.Level
+		wantMsg   string
+		wantParam bool
+	}{
+		{
+			name:      "redacted query",
+			config:    GormLoggerConfig{LogLevel: gormlogger.Info},
+			query:     func(db *gorm.DB) error { return db.Create(&secret{Value: "top-secret"}).Error },
+			wantLevel: zapcore.InfoLevel,
+			wantMsg:   "query",
+			wantParam: false,
+		},
+		{
+			name:      "query with params",
+			config:    GormLoggerConfig{LogLevel: gormlogger.Info, ShowParams: true},
+			query:     func(db *gorm.DB) error { return db.Create(&secret{Value: "top-secret"}).Error },
+			wantLevel: zapcore.InfoLevel,
+			wantMsg:   "query",
+			wantParam: true,
+		},
+		{
+			name:      "slow query",
+			config:    GormLoggerConfig{LogLevel: gormlogger.Warn, SlowThreshold: time.Nanosecond},
+			query:     func(db *gorm.DB) error { return db.Create(&secret{Value: "top-secret"}).Error },
+			wantLevel: zapcore.WarnLevel,
+			wantMsg:   "slow query",
+			wantParam: false,
+		},
+		{
+			name:   "failed query",
+			config: GormLoggerConfig{LogLevel: gormlogger.Warn},
+			query: func(db *gorm.DB) error {
+				_ = db.Create(&secret{ID: 1, Value: "top-secret"}).Error
+				return nil
+			},
+			wantLevel: zapcore.ErrorLevel,
+			wantMsg:   "query failed",
+			wantParam: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormlogger.Discard})
+			if err != nil {
+				t.Fatalf("failed to open database: %v", err)
+			}
+			if err := db.AutoMigrate(&secret{}); err != nil {
+				t.Fatalf("failed to migrate: %v", err)
+			}
+			if err := db.Create(&secret{ID: 1, Value: "existing"}).Error; err != nil {
+				t.Fatalf("failed to create data: %v", err)
+			}
+
+			core, logs := observer.New(zapcore.DebugLevel)
+			db.Logger = NewGormLogger(zap.New(core).Sugar(), tt.config)
+			ctx := utils.InjectCorrelationIDToContext(context.Background(), "test-correlation-id")
+			if err := tt.query(db.WithContext(ctx)); err != nil {
+				t.Fatalf("failed to run query: %v", err)
+			}
+
+			entries := logs.FilterMessage(tt.wantMsg).All()
+			if len(entries) != 1 {
+				t.Fatalf("got %d %q logs, want 1: %v", len(entries), tt.wantMsg, logs.All())
+			}
+			entry := entries[0]
+			if entry.Level != tt.wantLevel {
+				t.Errorf("log level = %v, want %v", entry.Level, tt.wantLevel)
+			}
+
+			fields := entry.ContextMap()
+			if fields["correlation_id"] != "test-correlation-id" {
+				t.Errorf("correlation_id = %v, want test-correlation-id", fields["correlation_id"])
+			}
+			sql, _ := fields["sql"].(string)
+			if got := strings.Contains(sql, "top-secret"); got != tt.wantParam {
+				t.Errorf("sql = %q, contains param = %v, want %v", sql, got, tt.wantParam)
+			}
+		})
+	}
+}
+
+func TestGormLogger_Silent(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := NewGormLogger(zap.New(core).Sugar(), GormLoggerConfig{LogLevel: gormlogger.Info}).LogMode(gormlogger.Silent)
+	l.Trace(context.Background(), time.Now(), func() (string, int64) { return "SELECT 1", 1 }, nil)
+	l.Error(context.Background(), "error %d", 1)
+
+	if logs.Len() != 0 {
+		t.Errorf("got %d logs in silent mode, want 0", logs.Len())
+	}
+}
+
+func TestParseGormLogLevel(t *testing.T) {
+	t.Parallel()
+
+	for level, want := range map[string]gormlogger.LogLevel{
+		"silent": gormlogger.Silent,
+		"error":  gormlogger.Error,
+		"WARN":   gormlogger.Warn,
+		"info":   gormlogger.Info,
+	} {
+		if got, err := ParseGormLogLevel(level); err != nil || got != want {
+			t.Errorf("ParseGormLogLevel(%s) = %v, %v, want %v", level, got, err, want)
+		}
+	}
+
+	if _, err := ParseGormLogLevel("verbose"); err == nil {
+		t.Errorf("ParseGormLogLevel(verbose) error = nil, want error")
+	}
+}
@@ -0,0 +1,57 @@
+// Package admin marks the callers allowed to use the administration APIs in the request context: the actors,
+// authenticated by the auth middleware, listed in its configuration. It must run after the auth middleware.
+package admin
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	"google.golang.org/grpc"
+)
+
+type Config struct {
+	// Actors are the administrators, no caller is one when empty
+	Actors []string
+}
+
+type Admins struct {
+	actors map[string]struct{}
+}
+
+func NewAdmins(config Config) *Admins {
+	actors := make(map[string]struct{}, len(config.Actors))
+	for _, actor := range config.Actors {
+		if actor != "" {
+			actors[actor] = struct{}{}
+		}
+	}
+
+	return &Admins{
+		actors: actors,
+	}
+}
+
+func (a *Admins) inject(ctx context.Context) context.Context {
+	actor, ok := utils.LookupActor(ctx)
+	if !ok {
+		return ctx
+	}
+	if _, admin := a.actors[actor]; !admin {
+		return ctx
+	}
+
+	return utils.InjectAdminToContext(ctx)
+}
+
+func (a *Admins) InjectAdmin(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(a.inject(ctx), req)
+}
+
+// HTTPMiddleware does the same as InjectAdmin for the requests served by the in-process gateway,
+// which do not go through the gRPC interceptors.
+func (a *Admins) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(a.inject(r.Context())))
+	})
+}
@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tuantran1810/go-di-template/libs/utils"
+)
+
+func TestAdmins_InjectAdmin(t *testing.T) {
+	t.Parallel()
+
+	admins := NewAdmins(Config{Actors: []string{"alice", ""}})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want bool
+	}{
+		{
+			name: "admin actor",
+			ctx:  utils.InjectActorToContext(context.Background(), "alice"),
+			want: true,
+		},
+		{
+			name: "other actor",
+			ctx:  utils.InjectActorToContext(context.Background(), "bob"),
+			want: false,
+		},
+		{
+			name: "anonymous caller",
+			ctx:  context.Background(),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got bool
+			_, err := admins.InjectAdmin(tt.ctx, nil, nil, func(ctx context.Context, _ any) (any, error) {
+				got = utils.IsAdmin(ctx)
+				return nil, nil
+			})
+			if err != nil {
+				t.Fatalf("InjectAdmin() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsAdmin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdmins_HTTPMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var got bool
+	handler := NewAdmins(Config{Actors: []string{"alice"}}).HTTPMiddleware(
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			got = utils.IsAdmin(r.Context())
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(utils.InjectActorToContext(req.Context(), "alice")))
+	if !got {
+		t.Errorf("IsAdmin() = %v, want true", got)
+	}
+}
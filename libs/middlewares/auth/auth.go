@@ -0,0 +1,171 @@
+// Package auth authenticates the caller of a request and carries its identity, the actor and the tenant,
+// into the request context, for the tenant and admin middlewares and the handlers to use.
+// How the identity is verified is left to the configured IAuthenticator: the x-actor and x-tenant-id
+// gRPC metadata / HTTP headers are never trusted as is, since any caller can set them.
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// XTenantID is the gRPC metadata key and the HTTP header of the tenant id claimed by the caller.
+	XTenantID = "x-tenant-id"
+	// XProxyToken is the gRPC metadata key and the HTTP header of the secret shared with the trusted proxy.
+	XProxyToken = "x-proxy-token"
+)
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	// Actor is the caller, empty for an anonymous one
+	Actor string
+	// TenantID is the tenant of the caller, 0 for the default tenant
+	TenantID uint
+}
+
+type IAuthenticator interface {
+	// Authenticate returns the identity of the request whose gRPC metadata or HTTP headers are read by get.
+	// It returns an ErrUnauthorized error when the credential is invalid, and an ErrInvalid error when
+	// the identity it carries is malformed.
+	Authenticate(ctx context.Context, get func(key string) string) (Identity, error)
+}
+
+// claimed reports whether the request carries an identity in the headers of the trusted proxy.
+func claimed(get func(key string) string) bool {
+	return get(string(utils.XActor)) != "" || get(XTenantID) != ""
+}
+
+// Anonymous authenticates every request as an anonymous caller of the default tenant.
+// It is used when no authentication is configured: the requests claiming an identity are rejected,
+// rather than served as anonymous ones with the data of the default tenant.
+type Anonymous struct{}
+
+func (Anonymous) Authenticate(_ context.Context, get func(key string) string) (Identity, error) {
+	if claimed(get) {
+		return Identity{}, fmt.Errorf(
+			"%w - %s and %s are only accepted from a trusted proxy", entities.ErrUnauthorized, utils.XActor, XTenantID,
+		)
+	}
+
+	return Identity{}, nil
+}
+
+// TrustedProxy authenticates the requests forwarded by the authentication proxy in front of the service,
+// which verified the credential of the caller and passes its identity in the x-actor and x-tenant-id headers.
+// The headers are only trusted along with the x-proxy-token secret shared with the proxy.
+type TrustedProxy struct {
+	token []byte
+}
+
+func NewTrustedProxy(token string) *TrustedProxy {
+	return &TrustedProxy{
+		token: []byte(token),
+	}
+}
+
+func parseTenantID(value string) (uint, error) {
+	tenantID, err := strconv.ParseUint(value, 10, 0)
+	if err != nil || tenantID == 0 {
+		return 0, fmt.Errorf("%w - invalid %s: %q", entities.ErrInvalid, XTenantID, value)
+	}
+
+	return uint(tenantID), nil
+}
+
+func (p *TrustedProxy) Authenticate(ctx context.Context, get func(key string) string) (Identity, error) {
+	token := get(XProxyToken)
+	if token == "" {
+		return Anonymous{}.Authenticate(ctx, get)
+	}
+	if len(p.token) == 0 || subtle.ConstantTimeCompare([]byte(token), p.token) != 1 {
+		return Identity{}, fmt.Errorf("%w - invalid %s", entities.ErrUnauthorized, XProxyToken)
+	}
+
+	identity := Identity{
+		Actor: get(string(utils.XActor)),
+	}
+	if value := get(XTenantID); value != "" {
+		tenantID, err := parseTenantID(value)
+		if err != nil {
+			return Identity{}, err
+		}
+		identity.TenantID = tenantID
+	}
+
+	return identity, nil
+}
+
+type Authenticator struct {
+	authenticator IAuthenticator
+}
+
+func NewAuthenticator(authenticator IAuthenticator) *Authenticator {
+	return &Authenticator{
+		authenticator: authenticator,
+	}
+}
+
+func inject(ctx context.Context, identity Identity) context.Context {
+	if identity.Actor != "" {
+		ctx = utils.InjectActorToContext(ctx, identity.Actor)
+	}
+	if identity.TenantID != 0 {
+		ctx = entities.InjectTenantIDToContext(ctx, identity.TenantID)
+	}
+
+	return ctx
+}
+
+func fromMetadata(ctx context.Context) func(key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return func(key string) string {
+		values := md.Get(key)
+		if len(values) == 0 {
+			return ""
+		}
+
+		return values[0]
+	}
+}
+
+func (a *Authenticator) Authenticate(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	identity, err := a.authenticator.Authenticate(ctx, fromMetadata(ctx))
+	if err != nil {
+		if errors.Is(err, entities.ErrInvalid) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return handler(inject(ctx, identity), req)
+}
+
+// HTTPMiddleware does the same as Authenticate for the requests served by the in-process gateway,
+// which do not go through the gRPC interceptors.
+func (a *Authenticator) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, err := a.authenticator.Authenticate(r.Context(), r.Header.Get)
+		if err != nil {
+			if errors.Is(err, entities.ErrInvalid) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			} else {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+			}
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(inject(r.Context(), identity)))
+	})
+}
@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"github.com/tuantran1810/go-di-template/libs/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		authenticator IAuthenticator
+		md            metadata.MD
+		wantActor     string
+		wantTenantID  uint
+		wantCode      codes.Code
+	}{
+		{
+			name:          "anonymous caller",
+			authenticator: Anonymous{},
+			md:            metadata.Pairs("x-correlation-id", "id"),
+			wantTenantID:  entities.DefaultTenantID,
+			wantCode:      codes.OK,
+		},
+		{
+			name:          "forged actor without authentication",
+			authenticator: Anonymous{},
+			md:            metadata.Pairs("x-actor", "alice"),
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:          "forged tenant without authentication",
+			authenticator: Anonymous{},
+			md:            metadata.Pairs("x-tenant-id", "2"),
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:          "identity from the trusted proxy",
+			authenticator: NewTrustedProxy("secret"),
+			md:            metadata.Pairs("x-proxy-token", "secret", "x-actor", "alice", "x-tenant-id", "2"),
+			wantActor:     "alice",
+			wantTenantID:  2,
+			wantCode:      codes.OK,
+		},
+		{
+			name:          "anonymous caller of the trusted proxy",
+			authenticator: NewTrustedProxy("secret"),
+			md:            metadata.Pairs("x-proxy-token", "secret"),
+			wantTenantID:  entities.DefaultTenantID,
+			wantCode:      codes.OK,
+		},
+		{
+			name:          "forged identity without the proxy token",
+			authenticator: NewTrustedProxy("secret"),
+			md:            metadata.Pairs("x-actor", "alice", "x-tenant-id", "2"),
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:          "forged identity with a wrong proxy token",
+			authenticator: NewTrustedProxy("secret"),
+			md:            metadata.Pairs("x-proxy-token", "guess", "x-actor", "alice"),
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:          "proxy token without a configured one",
+			authenticator: NewTrustedProxy(""),
+			md:            metadata.Pairs("x-proxy-token", "guess", "x-actor", "alice"),
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:          "invalid tenant from the trusted proxy",
+			authenticator: NewTrustedProxy("secret"),
+			md:            metadata.Pairs("x-proxy-token", "secret", "x-tenant-id", "0"),
+			wantCode:      codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var actor string
+			var tenantID uint
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			_, err := NewAuthenticator(tt.authenticator).Authenticate(ctx, nil, nil, func(ctx context.Context, _ any) (any, error) {
+				actor, _ = utils.LookupActor(ctx)
+				tenantID = entities.GetTenantIDFromContext(ctx)
+				return nil, nil
+			})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("Authenticate() code = %v, want %v", code, tt.wantCode)
+			}
+			if tt.wantCode != codes.OK {
+				return
+			}
+			if actor != tt.wantActor {
+				t.Errorf("LookupActor() = %q, want %q", actor, tt.wantActor)
+			}
+			if tenantID != tt.wantTenantID {
+				t.Errorf("GetTenantIDFromContext() = %d, want %d", tenantID, tt.wantTenantID)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_HTTPMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var actor string
+	var tenantID uint
+	handler := NewAuthenticator(NewTrustedProxy("secret")).HTTPMiddleware(
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			actor, _ = utils.LookupActor(r.Context())
+			tenantID = entities.GetTenantIDFromContext(r.Context())
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Proxy-Token", "secret")
+	req.Header.Set("X-Actor", "alice")
+	req.Header.Set("X-Tenant-Id", "3")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if actor != "alice" || tenantID != 3 {
+		t.Errorf("identity = (%q, %d), want (%q, %d)", actor, tenantID, "alice", 3)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Actor", "alice")
+	req.Header.Set("X-Tenant-Id", "3")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("status of a forged identity = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Proxy-Token", "secret")
+	req.Header.Set("X-Tenant-Id", "-1")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("status of an invalid tenant = %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+}
@@ -22,6 +22,12 @@ func HandleError(err error) error {
 		return status.Error(codes.Canceled, errString)
 	case errors.Is(err, entities.ErrTooManyRequests):
 		return status.Error(codes.ResourceExhausted, errString)
+	case errors.Is(err, entities.ErrLocked):
+		return status.Error(codes.Aborted, errString)
+	case errors.Is(err, entities.ErrConflicted):
+		return status.Error(codes.AlreadyExists, errString)
+	case errors.Is(err, entities.ErrMalformed):
+		return status.Error(codes.Internal, errString)
 	case errors.Is(err, entities.ErrDatabase):
 		return status.Error(codes.Unavailable, errString)
 	case errors.Is(err, entities.ErrInvalid):
@@ -30,6 +36,8 @@ func HandleError(err error) error {
 		return status.Error(codes.NotFound, errString)
 	case errors.Is(err, entities.ErrUnauthorized):
 		return status.Error(codes.Unauthenticated, errString)
+	case errors.Is(err, entities.ErrForbidden):
+		return status.Error(codes.PermissionDenied, errString)
 	case errors.Is(err, entities.ErrInternal):
 		return status.Error(codes.Internal, errString)
 	default:
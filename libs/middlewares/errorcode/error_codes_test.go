@@ -27,6 +27,18 @@ func Test_HandleError(t *testing.T) {
 			errType: entities.ErrTooManyRequests,
 			outErr:  status.Error(codes.ResourceExhausted, "too many requests - test err"),
 		},
+		{
+			errType: entities.ErrLocked,
+			outErr:  status.Error(codes.Aborted, "locked - test err"),
+		},
+		{
+			errType: entities.ErrConflicted,
+			outErr:  status.Error(codes.AlreadyExists, "conflicted - test err"),
+		},
+		{
+			errType: entities.ErrMalformed,
+			outErr:  status.Error(codes.Internal, "malformed - test err"),
+		},
 		{
 			errType: entities.ErrDatabase,
 			outErr:  status.Error(codes.Unavailable, "database error - test err"),
@@ -43,6 +55,10 @@ func Test_HandleError(t *testing.T) {
 			errType: entities.ErrUnauthorized,
 			outErr:  status.Error(codes.Unauthenticated, "unauthorized - test err"),
 		},
+		{
+			errType: entities.ErrForbidden,
+			outErr:  status.Error(codes.PermissionDenied, "forbidden - test err"),
+		},
 		{
 			errType: entities.ErrInternal,
 			outErr:  status.Error(codes.Internal, "internal error - test err"),
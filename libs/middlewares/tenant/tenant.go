@@ -0,0 +1,68 @@
+// Package tenant rejects the requests of the tenants which do not exist, so that no data is written for them.
+// The tenant of the caller is set in the request context by the auth middleware, it must run after it.
+// A request without one belongs to the default tenant, which always exists.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type IChecker interface {
+	// CheckTenant returns an ErrInvalid error when the tenant does not exist.
+	CheckTenant(ctx context.Context, tenantID uint) error
+}
+
+type Tenants struct {
+	checker IChecker
+}
+
+func NewTenants(checker IChecker) *Tenants {
+	return &Tenants{
+		checker: checker,
+	}
+}
+
+// check checks that the tenant of the caller exists.
+func (t *Tenants) check(ctx context.Context) error {
+	tenantID := entities.GetTenantIDFromContext(ctx)
+	if tenantID == entities.DefaultTenantID {
+		return nil
+	}
+
+	return t.checker.CheckTenant(ctx, tenantID)
+}
+
+func (t *Tenants) CheckTenant(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := t.check(ctx); err != nil {
+		if errors.Is(err, entities.ErrInvalid) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return handler(ctx, req)
+}
+
+// HTTPMiddleware does the same as CheckTenant for the requests served by the in-process gateway,
+// which do not go through the gRPC interceptors.
+func (t *Tenants) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := t.check(r.Context()); err != nil {
+			if errors.Is(err, entities.ErrInvalid) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			} else {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			}
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
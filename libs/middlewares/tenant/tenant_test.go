@@ -0,0 +1,108 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tuantran1810/go-di-template/internal/entities"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeChecker knows the tenants 1 to 3, and fails to look up the tenant 500.
+type fakeChecker struct{}
+
+func (fakeChecker) CheckTenant(_ context.Context, tenantID uint) error {
+	switch {
+	case tenantID == 500:
+		return entities.ErrDatabase
+	case tenantID > 3:
+		return fmt.Errorf("%w - unknown tenant %d", entities.ErrInvalid, tenantID)
+	default:
+		return nil
+	}
+}
+
+func TestTenants_CheckTenant(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		want     uint
+		wantCode codes.Code
+	}{
+		{
+			name:     "known tenant",
+			ctx:      entities.InjectTenantIDToContext(context.Background(), 2),
+			want:     2,
+			wantCode: codes.OK,
+		},
+		{
+			name:     "no tenant",
+			ctx:      context.Background(),
+			want:     entities.DefaultTenantID,
+			wantCode: codes.OK,
+		},
+		{
+			name:     "unknown tenant",
+			ctx:      entities.InjectTenantIDToContext(context.Background(), 999),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "failed to check the tenant",
+			ctx:      entities.InjectTenantIDToContext(context.Background(), 500),
+			wantCode: codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got uint
+			_, err := NewTenants(fakeChecker{}).CheckTenant(tt.ctx, nil, nil, func(ctx context.Context, _ any) (any, error) {
+				got = entities.GetTenantIDFromContext(ctx)
+				return nil, nil
+			})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("CheckTenant() code = %v, want %v", code, tt.wantCode)
+			}
+			if got != tt.want {
+				t.Errorf("GetTenantIDFromContext() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTenants_HTTPMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var got uint
+	handler := NewTenants(fakeChecker{}).HTTPMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = entities.GetTenantIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(entities.InjectTenantIDToContext(req.Context(), 3)))
+	if got != 3 {
+		t.Errorf("GetTenantIDFromContext() = %d, want 3", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req.WithContext(entities.InjectTenantIDToContext(req.Context(), 999)))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("status of an unknown tenant = %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req.WithContext(entities.InjectTenantIDToContext(req.Context(), 500)))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("status of a failed check = %d, want %d", recorder.Code, http.StatusServiceUnavailable)
+	}
+}
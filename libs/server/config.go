@@ -6,6 +6,7 @@ import (
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	grpc_logger "github.com/tuantran1810/go-di-template/libs/middlewares/logger"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type config struct {
@@ -99,6 +100,18 @@ func (c *config) RegisterHTTP(handler func(mux *runtime.ServeMux, conn *grpc.Cli
 	return c
 }
 
+// SetHealthServer replaces the gRPC health service, which is always serving by default.
+func (c *config) SetHealthServer(server grpc_health_v1.HealthServer) *config {
+	c.grpc.healthServer = server
+	return c
+}
+
+// HandleHTTPPath serves a plain HTTP handler at the path, besides the gateway routes.
+func (c *config) HandleHTTPPath(method string, path string, handler http.HandlerFunc) *config {
+	c.http.handlers = append(c.http.handlers, httpHandler{method: method, path: path, handler: handler})
+	return c
+}
+
 func (c *config) AddMiddleware(middlewares ...func(http.Handler) http.Handler) *config {
 	c.http.middlewares = append(c.http.middlewares, middlewares...)
 	return c
@@ -28,6 +28,7 @@ type grpcServerConfig struct {
 	serverOptions []grpc.ServerOption
 	loggerOptions grpc_logger.Options
 	reflection    bool
+	healthServer  grpc_health_v1.HealthServer
 }
 
 func NewGRPCServer(conf *config) (*GrpcServer, error) {
@@ -70,7 +71,11 @@ func NewGRPCServer(conf *config) (*GrpcServer, error) {
 
 	// setup server
 	server := grpc.NewServer(options...)
-	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	healthServer := conf.grpc.healthServer
+	if healthServer == nil {
+		healthServer = health.NewServer()
+	}
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
 	grpc_prometheus.Register(server)
 	grpc_prometheus.EnableHandlingTimeHistogram()
 	conf.grpc.registerFunc(server)
@@ -22,6 +22,13 @@ type httpServerConfig struct {
 	registerFunc    func(mux *runtime.ServeMux, conn *grpc.ClientConn)
 	serveMuxOptions []runtime.ServeMuxOption
 	middlewares     []func(http.Handler) http.Handler
+	handlers        []httpHandler
+}
+
+type httpHandler struct {
+	method  string
+	path    string
+	handler http.HandlerFunc
 }
 
 func NewHTTPServer(conf *config) (*HttpServer, error) {
@@ -52,6 +59,14 @@ func NewHTTPServer(conf *config) (*HttpServer, error) {
 		return nil, fmt.Errorf("register prometheus handler / %w", err)
 	}
 
+	for _, h := range conf.http.handlers {
+		if err := mux.HandlePath(h.method, h.path, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+			h.handler(w, r)
+		}); err != nil {
+			return nil, fmt.Errorf("register handler of %s / %w", h.path, err)
+		}
+	}
+
 	handler := http.Handler(mux)
 
 	if len(conf.http.middlewares) > 0 {
@@ -0,0 +1,41 @@
+package utils
+
+import "context"
+
+// XActor is the context key, and the gRPC metadata / HTTP header name, of the authenticated caller.
+const XActor ContextKey = "x-actor"
+
+func InjectActorToContext(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, XActor, actor)
+}
+
+// LookupActor returns the actor carried by ctx, ok is false for an anonymous call.
+func LookupActor(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+
+	actor, ok := ctx.Value(XActor).(string)
+	if !ok || actor == "" {
+		return "", false
+	}
+
+	return actor, true
+}
+
+// xAdmin is the context key marking the callers allowed to use the administration APIs.
+const xAdmin ContextKey = "x-admin"
+
+func InjectAdminToContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, xAdmin, true)
+}
+
+// IsAdmin reports whether the caller of ctx is an administrator.
+func IsAdmin(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+
+	admin, _ := ctx.Value(xAdmin).(bool)
+	return admin
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLookupActor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "returns the actor injected to the context",
+			ctx:    InjectActorToContext(context.Background(), "alice"),
+			want:   "alice",
+			wantOk: true,
+		},
+		{
+			name:   "not found when the context has no actor",
+			ctx:    context.Background(),
+			want:   "",
+			wantOk: false,
+		},
+		{
+			name:   "not found when the actor is empty",
+			ctx:    InjectActorToContext(context.Background(), ""),
+			want:   "",
+			wantOk: false,
+		},
+		{
+			name:   "not found when the context value is not a string",
+			ctx:    context.WithValue(context.Background(), XActor, 42),
+			want:   "",
+			wantOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := LookupActor(tt.ctx)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("LookupActor() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestIsAdmin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want bool
+	}{
+		{
+			name: "admin injected to the context",
+			ctx:  InjectAdminToContext(context.Background()),
+			want: true,
+		},
+		{
+			name: "not an admin when the context has no mark",
+			ctx:  InjectActorToContext(context.Background(), "alice"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsAdmin(tt.ctx); got != tt.want {
+				t.Errorf("IsAdmin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -32,3 +32,17 @@ func GetCorrelationID(ctx context.Context) string {
 func InjectCorrelationIDToContext(ctx context.Context, correlationID string) context.Context {
 	return context.WithValue(ctx, XCorrelationID, correlationID)
 }
+
+// LookupCorrelationID returns the correlation ID carried by ctx, without generating one when it is missing.
+func LookupCorrelationID(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+
+	id, ok := ctx.Value(XCorrelationID).(string)
+	if !ok || id == "" {
+		return "", false
+	}
+
+	return id, true
+}
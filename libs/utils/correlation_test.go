@@ -190,3 +190,50 @@ func TestInjectCorrelationIDToContext(t *testing.T) {
 		})
 	}
 }
+
+func TestLookupCorrelationID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "returns correlation ID from context when present",
+			ctx:    InjectCorrelationIDToContext(context.Background(), "test-id-123"),
+			want:   "test-id-123",
+			wantOk: true,
+		},
+		{
+			name:   "does not generate correlation ID when context is empty",
+			ctx:    context.Background(),
+			want:   "",
+			wantOk: false,
+		},
+		{
+			name:   "ignores context value which is not string",
+			ctx:    context.WithValue(context.Background(), XCorrelationID, 12345),
+			want:   "",
+			wantOk: false,
+		},
+		{
+			name:   "ignores empty correlation ID",
+			ctx:    InjectCorrelationIDToContext(context.Background(), ""),
+			want:   "",
+			wantOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := LookupCorrelationID(tt.ctx)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("LookupCorrelationID() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
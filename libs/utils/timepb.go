@@ -16,3 +16,20 @@ func FromTimepb(t *timestamppb.Timestamp) time.Time {
 	}
 	return t.AsTime()
 }
+
+// ToOptionalTimepb is ToTimepb of an optional time, nil when t is nil.
+func ToOptionalTimepb(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// FromOptionalTimepb is FromTimepb of an optional time, nil when t is nil.
+func FromOptionalTimepb(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+	out := t.AsTime()
+	return &out
+}
@@ -44,3 +44,18 @@ func TestFromTimepb(t *testing.T) {
 		}
 	})
 }
+
+func TestOptionalTimepb(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	if got := FromOptionalTimepb(ToOptionalTimepb(&now)); got == nil || !got.Equal(now) {
+		t.Errorf("FromOptionalTimepb(ToOptionalTimepb()) = %v, want %v", got, now)
+	}
+	if got := ToOptionalTimepb(nil); got != nil {
+		t.Errorf("ToOptionalTimepb() = %v, want nil", got)
+	}
+	if got := FromOptionalTimepb(nil); got != nil {
+		t.Errorf("FromOptionalTimepb() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,96 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package controllers
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+// NewMockIAuditEventUsecase creates a new instance of MockIAuditEventUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIAuditEventUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIAuditEventUsecase {
+	mock := &MockIAuditEventUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIAuditEventUsecase is an autogenerated mock type for the IAuditEventUsecase type
+type MockIAuditEventUsecase struct {
+	mock.Mock
+}
+
+type MockIAuditEventUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIAuditEventUsecase) EXPECT() *MockIAuditEventUsecase_Expecter {
+	return &MockIAuditEventUsecase_Expecter{mock: &_m.Mock}
+}
+
+// ListAuditEvents provides a mock function for the type MockIAuditEventUsecase
+func (_mock *MockIAuditEventUsecase) ListAuditEvents(ctx context.Context, filter entities.AuditEventFilter) ([]entities.AuditEvent, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAuditEvents")
+	}
+
+	var r0 []entities.AuditEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.AuditEventFilter) ([]entities.AuditEvent, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.AuditEventFilter) []entities.AuditEvent); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.AuditEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, entities.AuditEventFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAuditEventUsecase_ListAuditEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAuditEvents'
+type MockIAuditEventUsecase_ListAuditEvents_Call struct {
+	*mock.Call
+}
+
+// ListAuditEvents is a helper method to define mock.On call
+//   - ctx
+//   - filter
+func (_e *MockIAuditEventUsecase_Expecter) ListAuditEvents(ctx interface{}, filter interface{}) *MockIAuditEventUsecase_ListAuditEvents_Call {
+	return &MockIAuditEventUsecase_ListAuditEvents_Call{Call: _e.mock.On("ListAuditEvents", ctx, filter)}
+}
+
+func (_c *MockIAuditEventUsecase_ListAuditEvents_Call) Run(run func(ctx context.Context, filter entities.AuditEventFilter)) *MockIAuditEventUsecase_ListAuditEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(entities.AuditEventFilter))
+	})
+	return _c
+}
+
+func (_c *MockIAuditEventUsecase_ListAuditEvents_Call) Return(auditEvents []entities.AuditEvent, err error) *MockIAuditEventUsecase_ListAuditEvents_Call {
+	_c.Call.Return(auditEvents, err)
+	return _c
+}
+
+func (_c *MockIAuditEventUsecase_ListAuditEvents_Call) RunAndReturn(run func(ctx context.Context, filter entities.AuditEventFilter) ([]entities.AuditEvent, error)) *MockIAuditEventUsecase_ListAuditEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
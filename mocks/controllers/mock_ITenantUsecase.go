@@ -0,0 +1,155 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package controllers
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+// NewMockITenantUsecase creates a new instance of MockITenantUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockITenantUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockITenantUsecase {
+	mock := &MockITenantUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockITenantUsecase is an autogenerated mock type for the ITenantUsecase type
+type MockITenantUsecase struct {
+	mock.Mock
+}
+
+type MockITenantUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockITenantUsecase) EXPECT() *MockITenantUsecase_Expecter {
+	return &MockITenantUsecase_Expecter{mock: &_m.Mock}
+}
+
+// CreateTenant provides a mock function for the type MockITenantUsecase
+func (_mock *MockITenantUsecase) CreateTenant(ctx context.Context, tenant *entities.Tenant) (*entities.Tenant, error) {
+	ret := _mock.Called(ctx, tenant)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTenant")
+	}
+
+	var r0 *entities.Tenant
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *entities.Tenant) (*entities.Tenant, error)); ok {
+		return returnFunc(ctx, tenant)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *entities.Tenant) *entities.Tenant); ok {
+		r0 = returnFunc(ctx, tenant)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Tenant)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *entities.Tenant) error); ok {
+		r1 = returnFunc(ctx, tenant)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITenantUsecase_CreateTenant_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTenant'
+type MockITenantUsecase_CreateTenant_Call struct {
+	*mock.Call
+}
+
+// CreateTenant is a helper method to define mock.On call
+//   - ctx
+//   - tenant
+func (_e *MockITenantUsecase_Expecter) CreateTenant(ctx interface{}, tenant interface{}) *MockITenantUsecase_CreateTenant_Call {
+	return &MockITenantUsecase_CreateTenant_Call{Call: _e.mock.On("CreateTenant", ctx, tenant)}
+}
+
+func (_c *MockITenantUsecase_CreateTenant_Call) Run(run func(ctx context.Context, tenant *entities.Tenant)) *MockITenantUsecase_CreateTenant_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entities.Tenant))
+	})
+	return _c
+}
+
+func (_c *MockITenantUsecase_CreateTenant_Call) Return(tenant1 *entities.Tenant, err error) *MockITenantUsecase_CreateTenant_Call {
+	_c.Call.Return(tenant1, err)
+	return _c
+}
+
+func (_c *MockITenantUsecase_CreateTenant_Call) RunAndReturn(run func(ctx context.Context, tenant *entities.Tenant) (*entities.Tenant, error)) *MockITenantUsecase_CreateTenant_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTenants provides a mock function for the type MockITenantUsecase
+func (_mock *MockITenantUsecase) ListTenants(ctx context.Context, offset int, limit int, showDeleted bool) ([]entities.Tenant, error) {
+	ret := _mock.Called(ctx, offset, limit, showDeleted)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTenants")
+	}
+
+	var r0 []entities.Tenant
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, bool) ([]entities.Tenant, error)); ok {
+		return returnFunc(ctx, offset, limit, showDeleted)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, bool) []entities.Tenant); ok {
+		r0 = returnFunc(ctx, offset, limit, showDeleted)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Tenant)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, bool) error); ok {
+		r1 = returnFunc(ctx, offset, limit, showDeleted)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITenantUsecase_ListTenants_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTenants'
+type MockITenantUsecase_ListTenants_Call struct {
+	*mock.Call
+}
+
+// ListTenants is a helper method to define mock.On call
+//   - ctx
+//   - offset
+//   - limit
+//   - showDeleted
+func (_e *MockITenantUsecase_Expecter) ListTenants(ctx interface{}, offset interface{}, limit interface{}, showDeleted interface{}) *MockITenantUsecase_ListTenants_Call {
+	return &MockITenantUsecase_ListTenants_Call{Call: _e.mock.On("ListTenants", ctx, offset, limit, showDeleted)}
+}
+
+func (_c *MockITenantUsecase_ListTenants_Call) Run(run func(ctx context.Context, offset int, limit int, showDeleted bool)) *MockITenantUsecase_ListTenants_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(bool))
+	})
+	return _c
+}
+
+func (_c *MockITenantUsecase_ListTenants_Call) Return(tenants []entities.Tenant, err error) *MockITenantUsecase_ListTenants_Call {
+	_c.Call.Return(tenants, err)
+	return _c
+}
+
+func (_c *MockITenantUsecase_ListTenants_Call) RunAndReturn(run func(ctx context.Context, offset int, limit int, showDeleted bool) ([]entities.Tenant, error)) *MockITenantUsecase_ListTenants_Call {
+	_c.Call.Return(run)
+	return _c
+}
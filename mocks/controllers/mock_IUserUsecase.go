@@ -38,6 +38,73 @@ func (_m *MockIUserUsecase) EXPECT() *MockIUserUsecase_Expecter {
 	return &MockIUserUsecase_Expecter{mock: &_m.Mock}
 }
 
+// BatchGetUsers provides a mock function for the type MockIUserUsecase
+func (_mock *MockIUserUsecase) BatchGetUsers(ctx context.Context, usernames []string, uuids []string, showDeleted bool) ([]entities.User, []string, error) {
+	ret := _mock.Called(ctx, usernames, uuids, showDeleted)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BatchGetUsers")
+	}
+
+	var r0 []entities.User
+	var r1 []string
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, []string, bool) ([]entities.User, []string, error)); ok {
+		return returnFunc(ctx, usernames, uuids, showDeleted)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, []string, bool) []entities.User); ok {
+		r0 = returnFunc(ctx, usernames, uuids, showDeleted)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string, []string, bool) []string); ok {
+		r1 = returnFunc(ctx, usernames, uuids, showDeleted)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, []string, []string, bool) error); ok {
+		r2 = returnFunc(ctx, usernames, uuids, showDeleted)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockIUserUsecase_BatchGetUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BatchGetUsers'
+type MockIUserUsecase_BatchGetUsers_Call struct {
+	*mock.Call
+}
+
+// BatchGetUsers is a helper method to define mock.On call
+//   - ctx
+//   - usernames
+//   - uuids
+//   - showDeleted
+func (_e *MockIUserUsecase_Expecter) BatchGetUsers(ctx interface{}, usernames interface{}, uuids interface{}, showDeleted interface{}) *MockIUserUsecase_BatchGetUsers_Call {
+	return &MockIUserUsecase_BatchGetUsers_Call{Call: _e.mock.On("BatchGetUsers", ctx, usernames, uuids, showDeleted)}
+}
+
+func (_c *MockIUserUsecase_BatchGetUsers_Call) Run(run func(ctx context.Context, usernames []string, uuids []string, showDeleted bool)) *MockIUserUsecase_BatchGetUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string), args[2].([]string), args[3].(bool))
+	})
+	return _c
+}
+
+func (_c *MockIUserUsecase_BatchGetUsers_Call) Return(users []entities.User, strings []string, err error) *MockIUserUsecase_BatchGetUsers_Call {
+	_c.Call.Return(users, strings, err)
+	return _c
+}
+
+func (_c *MockIUserUsecase_BatchGetUsers_Call) RunAndReturn(run func(ctx context.Context, usernames []string, uuids []string, showDeleted bool) ([]entities.User, []string, error)) *MockIUserUsecase_BatchGetUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateUser provides a mock function for the type MockIUserUsecase
 func (_mock *MockIUserUsecase) CreateUser(ctx context.Context, user *entities.User, attributes []entities.KeyValuePair) (*entities.User, []entities.UserAttribute, error) {
 	ret := _mock.Called(ctx, user, attributes)
@@ -162,8 +229,8 @@ func (_c *MockIUserUsecase_GetAttributesByUsername_Call) RunAndReturn(run func(c
 }
 
 // GetUserByUsername provides a mock function for the type MockIUserUsecase
-func (_mock *MockIUserUsecase) GetUserByUsername(ctx context.Context, username string) (*entities.User, []entities.UserAttribute, error) {
-	ret := _mock.Called(ctx, username)
+func (_mock *MockIUserUsecase) GetUserByUsername(ctx context.Context, username string, showDeleted bool) (*entities.User, []entities.UserAttribute, error) {
+	ret := _mock.Called(ctx, username, showDeleted)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserByUsername")
@@ -172,25 +239,25 @@ func (_mock *MockIUserUsecase) GetUserByUsername(ctx context.Context, username s
 	var r0 *entities.User
 	var r1 []entities.UserAttribute
 	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*entities.User, []entities.UserAttribute, error)); ok {
-		return returnFunc(ctx, username)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, bool) (*entities.User, []entities.UserAttribute, error)); ok {
+		return returnFunc(ctx, username, showDeleted)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *entities.User); ok {
-		r0 = returnFunc(ctx, username)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, bool) *entities.User); ok {
+		r0 = returnFunc(ctx, username, showDeleted)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entities.User)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) []entities.UserAttribute); ok {
-		r1 = returnFunc(ctx, username)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, bool) []entities.UserAttribute); ok {
+		r1 = returnFunc(ctx, username, showDeleted)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).([]entities.UserAttribute)
 		}
 	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, string) error); ok {
-		r2 = returnFunc(ctx, username)
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, bool) error); ok {
+		r2 = returnFunc(ctx, username, showDeleted)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -205,13 +272,14 @@ type MockIUserUsecase_GetUserByUsername_Call struct {
 // GetUserByUsername is a helper method to define mock.On call
 //   - ctx
 //   - username
-func (_e *MockIUserUsecase_Expecter) GetUserByUsername(ctx interface{}, username interface{}) *MockIUserUsecase_GetUserByUsername_Call {
-	return &MockIUserUsecase_GetUserByUsername_Call{Call: _e.mock.On("GetUserByUsername", ctx, username)}
+//   - showDeleted
+func (_e *MockIUserUsecase_Expecter) GetUserByUsername(ctx interface{}, username interface{}, showDeleted interface{}) *MockIUserUsecase_GetUserByUsername_Call {
+	return &MockIUserUsecase_GetUserByUsername_Call{Call: _e.mock.On("GetUserByUsername", ctx, username, showDeleted)}
 }
 
-func (_c *MockIUserUsecase_GetUserByUsername_Call) Run(run func(ctx context.Context, username string)) *MockIUserUsecase_GetUserByUsername_Call {
+func (_c *MockIUserUsecase_GetUserByUsername_Call) Run(run func(ctx context.Context, username string, showDeleted bool)) *MockIUserUsecase_GetUserByUsername_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(bool))
 	})
 	return _c
 }
@@ -221,7 +289,64 @@ func (_c *MockIUserUsecase_GetUserByUsername_Call) Return(user *entities.User, u
 	return _c
 }
 
-func (_c *MockIUserUsecase_GetUserByUsername_Call) RunAndReturn(run func(ctx context.Context, username string) (*entities.User, []entities.UserAttribute, error)) *MockIUserUsecase_GetUserByUsername_Call {
+func (_c *MockIUserUsecase_GetUserByUsername_Call) RunAndReturn(run func(ctx context.Context, username string, showDeleted bool) (*entities.User, []entities.UserAttribute, error)) *MockIUserUsecase_GetUserByUsername_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserStats provides a mock function for the type MockIUserUsecase
+func (_mock *MockIUserUsecase) GetUserStats(ctx context.Context, query entities.UserStatsQuery) (*entities.UserStats, error) {
+	ret := _mock.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserStats")
+	}
+
+	var r0 *entities.UserStats
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.UserStatsQuery) (*entities.UserStats, error)); ok {
+		return returnFunc(ctx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.UserStatsQuery) *entities.UserStats); ok {
+		r0 = returnFunc(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.UserStats)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, entities.UserStatsQuery) error); ok {
+		r1 = returnFunc(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserUsecase_GetUserStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserStats'
+type MockIUserUsecase_GetUserStats_Call struct {
+	*mock.Call
+}
+
+// GetUserStats is a helper method to define mock.On call
+//   - ctx
+//   - query
+func (_e *MockIUserUsecase_Expecter) GetUserStats(ctx interface{}, query interface{}) *MockIUserUsecase_GetUserStats_Call {
+	return &MockIUserUsecase_GetUserStats_Call{Call: _e.mock.On("GetUserStats", ctx, query)}
+}
+
+func (_c *MockIUserUsecase_GetUserStats_Call) Run(run func(ctx context.Context, query entities.UserStatsQuery)) *MockIUserUsecase_GetUserStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(entities.UserStatsQuery))
+	})
+	return _c
+}
+
+func (_c *MockIUserUsecase_GetUserStats_Call) Return(userStats *entities.UserStats, err error) *MockIUserUsecase_GetUserStats_Call {
+	_c.Call.Return(userStats, err)
+	return _c
+}
+
+func (_c *MockIUserUsecase_GetUserStats_Call) RunAndReturn(run func(ctx context.Context, query entities.UserStatsQuery) (*entities.UserStats, error)) *MockIUserUsecase_GetUserStats_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,110 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package usecases
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+// NewMockIAuditEventRepository creates a new instance of MockIAuditEventRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIAuditEventRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIAuditEventRepository {
+	mock := &MockIAuditEventRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIAuditEventRepository is an autogenerated mock type for the IAuditEventRepository type
+type MockIAuditEventRepository struct {
+	mock.Mock
+}
+
+type MockIAuditEventRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIAuditEventRepository) EXPECT() *MockIAuditEventRepository_Expecter {
+	return &MockIAuditEventRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetManyByCriterias provides a mock function for the type MockIAuditEventRepository
+func (_mock *MockIAuditEventRepository) GetManyByCriterias(ctx context.Context, tx entities.Transaction, fields []string, criterias map[string]any, orderBys []string, offset int, limit int, opts ...entities.QueryOption) ([]entities.AuditEvent, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, tx, fields, criterias, orderBys, offset, limit, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, tx, fields, criterias, orderBys, offset, limit)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetManyByCriterias")
+	}
+
+	var r0 []entities.AuditEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, []string, map[string]any, []string, int, int, ...entities.QueryOption) ([]entities.AuditEvent, error)); ok {
+		return returnFunc(ctx, tx, fields, criterias, orderBys, offset, limit, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, []string, map[string]any, []string, int, int, ...entities.QueryOption) []entities.AuditEvent); ok {
+		r0 = returnFunc(ctx, tx, fields, criterias, orderBys, offset, limit, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.AuditEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, entities.Transaction, []string, map[string]any, []string, int, int, ...entities.QueryOption) error); ok {
+		r1 = returnFunc(ctx, tx, fields, criterias, orderBys, offset, limit, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAuditEventRepository_GetManyByCriterias_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetManyByCriterias'
+type MockIAuditEventRepository_GetManyByCriterias_Call struct {
+	*mock.Call
+}
+
+// GetManyByCriterias is a helper method to define mock.On call
+//   - ctx
+//   - tx
+//   - fields
+//   - criterias
+//   - orderBys
+//   - offset
+//   - limit
+//   - opts
+func (_e *MockIAuditEventRepository_Expecter) GetManyByCriterias(ctx interface{}, tx interface{}, fields interface{}, criterias interface{}, orderBys interface{}, offset interface{}, limit interface{}, opts ...interface{}) *MockIAuditEventRepository_GetManyByCriterias_Call {
+	return &MockIAuditEventRepository_GetManyByCriterias_Call{Call: _e.mock.On("GetManyByCriterias",
+		append([]interface{}{ctx, tx, fields, criterias, orderBys, offset, limit}, opts...)...)}
+}
+
+func (_c *MockIAuditEventRepository_GetManyByCriterias_Call) Run(run func(ctx context.Context, tx entities.Transaction, fields []string, criterias map[string]any, orderBys []string, offset int, limit int, opts ...entities.QueryOption)) *MockIAuditEventRepository_GetManyByCriterias_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := args[7].([]entities.QueryOption)
+		run(args[0].(context.Context), args[1].(entities.Transaction), args[2].([]string), args[3].(map[string]any), args[4].([]string), args[5].(int), args[6].(int), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIAuditEventRepository_GetManyByCriterias_Call) Return(auditEvents []entities.AuditEvent, err error) *MockIAuditEventRepository_GetManyByCriterias_Call {
+	_c.Call.Return(auditEvents, err)
+	return _c
+}
+
+func (_c *MockIAuditEventRepository_GetManyByCriterias_Call) RunAndReturn(run func(ctx context.Context, tx entities.Transaction, fields []string, criterias map[string]any, orderBys []string, offset int, limit int, opts ...entities.QueryOption) ([]entities.AuditEvent, error)) *MockIAuditEventRepository_GetManyByCriterias_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,251 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package usecases
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+// NewMockIOutboxRepository creates a new instance of MockIOutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIOutboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIOutboxRepository {
+	mock := &MockIOutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIOutboxRepository is an autogenerated mock type for the IOutboxRepository type
+type MockIOutboxRepository struct {
+	mock.Mock
+}
+
+type MockIOutboxRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIOutboxRepository) EXPECT() *MockIOutboxRepository_Expecter {
+	return &MockIOutboxRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function for the type MockIOutboxRepository
+func (_mock *MockIOutboxRepository) Create(ctx context.Context, tx entities.Transaction, message *entities.OutboxMessage) (*entities.OutboxMessage, error) {
+	ret := _mock.Called(ctx, tx, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *entities.OutboxMessage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, *entities.OutboxMessage) (*entities.OutboxMessage, error)); ok {
+		return returnFunc(ctx, tx, message)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, *entities.OutboxMessage) *entities.OutboxMessage); ok {
+		r0 = returnFunc(ctx, tx, message)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.OutboxMessage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, entities.Transaction, *entities.OutboxMessage) error); ok {
+		r1 = returnFunc(ctx, tx, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIOutboxRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockIOutboxRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx
+//   - tx
+//   - message
+func (_e *MockIOutboxRepository_Expecter) Create(ctx interface{}, tx interface{}, message interface{}) *MockIOutboxRepository_Create_Call {
+	return &MockIOutboxRepository_Create_Call{Call: _e.mock.On("Create", ctx, tx, message)}
+}
+
+func (_c *MockIOutboxRepository_Create_Call) Run(run func(ctx context.Context, tx entities.Transaction, message *entities.OutboxMessage)) *MockIOutboxRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(entities.Transaction), args[2].(*entities.OutboxMessage))
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_Create_Call) Return(outboxMessage *entities.OutboxMessage, err error) *MockIOutboxRepository_Create_Call {
+	_c.Call.Return(outboxMessage, err)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_Create_Call) RunAndReturn(run func(ctx context.Context, tx entities.Transaction, message *entities.OutboxMessage) (*entities.OutboxMessage, error)) *MockIOutboxRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPending provides a mock function for the type MockIOutboxRepository
+func (_mock *MockIOutboxRepository) GetPending(ctx context.Context, tx entities.Transaction, limit int) ([]entities.OutboxMessage, error) {
+	ret := _mock.Called(ctx, tx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPending")
+	}
+
+	var r0 []entities.OutboxMessage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, int) ([]entities.OutboxMessage, error)); ok {
+		return returnFunc(ctx, tx, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, int) []entities.OutboxMessage); ok {
+		r0 = returnFunc(ctx, tx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.OutboxMessage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, entities.Transaction, int) error); ok {
+		r1 = returnFunc(ctx, tx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIOutboxRepository_GetPending_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPending'
+type MockIOutboxRepository_GetPending_Call struct {
+	*mock.Call
+}
+
+// GetPending is a helper method to define mock.On call
+//   - ctx
+//   - tx
+//   - limit
+func (_e *MockIOutboxRepository_Expecter) GetPending(ctx interface{}, tx interface{}, limit interface{}) *MockIOutboxRepository_GetPending_Call {
+	return &MockIOutboxRepository_GetPending_Call{Call: _e.mock.On("GetPending", ctx, tx, limit)}
+}
+
+func (_c *MockIOutboxRepository_GetPending_Call) Run(run func(ctx context.Context, tx entities.Transaction, limit int)) *MockIOutboxRepository_GetPending_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(entities.Transaction), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_GetPending_Call) Return(outboxMessages []entities.OutboxMessage, err error) *MockIOutboxRepository_GetPending_Call {
+	_c.Call.Return(outboxMessages, err)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_GetPending_Call) RunAndReturn(run func(ctx context.Context, tx entities.Transaction, limit int) ([]entities.OutboxMessage, error)) *MockIOutboxRepository_GetPending_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkFailed provides a mock function for the type MockIOutboxRepository
+func (_mock *MockIOutboxRepository) MarkFailed(ctx context.Context, tx entities.Transaction, id uint, reason string, dead bool) error {
+	ret := _mock.Called(ctx, tx, id, reason, dead)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkFailed")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, uint, string, bool) error); ok {
+		r0 = returnFunc(ctx, tx, id, reason, dead)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIOutboxRepository_MarkFailed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkFailed'
+type MockIOutboxRepository_MarkFailed_Call struct {
+	*mock.Call
+}
+
+// MarkFailed is a helper method to define mock.On call
+//   - ctx
+//   - tx
+//   - id
+//   - reason
+//   - dead
+func (_e *MockIOutboxRepository_Expecter) MarkFailed(ctx interface{}, tx interface{}, id interface{}, reason interface{}, dead interface{}) *MockIOutboxRepository_MarkFailed_Call {
+	return &MockIOutboxRepository_MarkFailed_Call{Call: _e.mock.On("MarkFailed", ctx, tx, id, reason, dead)}
+}
+
+func (_c *MockIOutboxRepository_MarkFailed_Call) Run(run func(ctx context.Context, tx entities.Transaction, id uint, reason string, dead bool)) *MockIOutboxRepository_MarkFailed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(entities.Transaction), args[2].(uint), args[3].(string), args[4].(bool))
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_MarkFailed_Call) Return(err error) *MockIOutboxRepository_MarkFailed_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_MarkFailed_Call) RunAndReturn(run func(ctx context.Context, tx entities.Transaction, id uint, reason string, dead bool) error) *MockIOutboxRepository_MarkFailed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkSent provides a mock function for the type MockIOutboxRepository
+func (_mock *MockIOutboxRepository) MarkSent(ctx context.Context, tx entities.Transaction, ids []uint) error {
+	ret := _mock.Called(ctx, tx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkSent")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, []uint) error); ok {
+		r0 = returnFunc(ctx, tx, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIOutboxRepository_MarkSent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkSent'
+type MockIOutboxRepository_MarkSent_Call struct {
+	*mock.Call
+}
+
+// MarkSent is a helper method to define mock.On call
+//   - ctx
+//   - tx
+//   - ids
+func (_e *MockIOutboxRepository_Expecter) MarkSent(ctx interface{}, tx interface{}, ids interface{}) *MockIOutboxRepository_MarkSent_Call {
+	return &MockIOutboxRepository_MarkSent_Call{Call: _e.mock.On("MarkSent", ctx, tx, ids)}
+}
+
+func (_c *MockIOutboxRepository_MarkSent_Call) Run(run func(ctx context.Context, tx entities.Transaction, ids []uint)) *MockIOutboxRepository_MarkSent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(entities.Transaction), args[2].([]uint))
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_MarkSent_Call) Return(err error) *MockIOutboxRepository_MarkSent_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_MarkSent_Call) RunAndReturn(run func(ctx context.Context, tx entities.Transaction, ids []uint) error) *MockIOutboxRepository_MarkSent_Call {
+	_c.Call.Return(run)
+	return _c
+}
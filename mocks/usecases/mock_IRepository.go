@@ -0,0 +1,93 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package usecases
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+// NewMockIRepository creates a new instance of MockIRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIRepository {
+	mock := &MockIRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIRepository is an autogenerated mock type for the IRepository type
+type MockIRepository struct {
+	mock.Mock
+}
+
+type MockIRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIRepository) EXPECT() *MockIRepository_Expecter {
+	return &MockIRepository_Expecter{mock: &_m.Mock}
+}
+
+// RunTx provides a mock function for the type MockIRepository
+func (_mock *MockIRepository) RunTx(ctx context.Context, funcs ...entities.DBTxHandleFunc) error {
+	var tmpRet mock.Arguments
+	if len(funcs) > 0 {
+		tmpRet = _mock.Called(ctx, funcs)
+	} else {
+		tmpRet = _mock.Called(ctx)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunTx")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, ...entities.DBTxHandleFunc) error); ok {
+		r0 = returnFunc(ctx, funcs...)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIRepository_RunTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunTx'
+type MockIRepository_RunTx_Call struct {
+	*mock.Call
+}
+
+// RunTx is a helper method to define mock.On call
+//   - ctx
+//   - funcs
+func (_e *MockIRepository_Expecter) RunTx(ctx interface{}, funcs ...interface{}) *MockIRepository_RunTx_Call {
+	return &MockIRepository_RunTx_Call{Call: _e.mock.On("RunTx",
+		append([]interface{}{ctx}, funcs...)...)}
+}
+
+func (_c *MockIRepository_RunTx_Call) Run(run func(ctx context.Context, funcs ...entities.DBTxHandleFunc)) *MockIRepository_RunTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := args[1].([]entities.DBTxHandleFunc)
+		run(args[0].(context.Context), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIRepository_RunTx_Call) Return(err error) *MockIRepository_RunTx_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIRepository_RunTx_Call) RunAndReturn(run func(ctx context.Context, funcs ...entities.DBTxHandleFunc) error) *MockIRepository_RunTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,235 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package usecases
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+	"github.com/tuantran1810/go-di-template/internal/entities"
+)
+
+// NewMockITenantRepository creates a new instance of MockITenantRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockITenantRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockITenantRepository {
+	mock := &MockITenantRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockITenantRepository is an autogenerated mock type for the ITenantRepository type
+type MockITenantRepository struct {
+	mock.Mock
+}
+
+type MockITenantRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockITenantRepository) EXPECT() *MockITenantRepository_Expecter {
+	return &MockITenantRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function for the type MockITenantRepository
+func (_mock *MockITenantRepository) Create(ctx context.Context, tx entities.Transaction, tenant *entities.Tenant) (*entities.Tenant, error) {
+	ret := _mock.Called(ctx, tx, tenant)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *entities.Tenant
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, *entities.Tenant) (*entities.Tenant, error)); ok {
+		return returnFunc(ctx, tx, tenant)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, *entities.Tenant) *entities.Tenant); ok {
+		r0 = returnFunc(ctx, tx, tenant)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Tenant)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, entities.Transaction, *entities.Tenant) error); ok {
+		r1 = returnFunc(ctx, tx, tenant)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITenantRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockITenantRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx
+//   - tx
+//   - tenant
+func (_e *MockITenantRepository_Expecter) Create(ctx interface{}, tx interface{}, tenant interface{}) *MockITenantRepository_Create_Call {
+	return &MockITenantRepository_Create_Call{Call: _e.mock.On("Create", ctx, tx, tenant)}
+}
+
+func (_c *MockITenantRepository_Create_Call) Run(run func(ctx context.Context, tx entities.Transaction, tenant *entities.Tenant)) *MockITenantRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(entities.Transaction), args[2].(*entities.Tenant))
+	})
+	return _c
+}
+
+func (_c *MockITenantRepository_Create_Call) Return(tenant1 *entities.Tenant, err error) *MockITenantRepository_Create_Call {
+	_c.Call.Return(tenant1, err)
+	return _c
+}
+
+func (_c *MockITenantRepository_Create_Call) RunAndReturn(run func(ctx context.Context, tx entities.Transaction, tenant *entities.Tenant) (*entities.Tenant, error)) *MockITenantRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function for the type MockITenantRepository
+func (_mock *MockITenantRepository) Get(ctx context.Context, tx entities.Transaction, id uint, opts ...entities.QueryOption) (*entities.Tenant, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, tx, id, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, tx, id)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *entities.Tenant
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, uint, ...entities.QueryOption) (*entities.Tenant, error)); ok {
+		return returnFunc(ctx, tx, id, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, uint, ...entities.QueryOption) *entities.Tenant); ok {
+		r0 = returnFunc(ctx, tx, id, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Tenant)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, entities.Transaction, uint, ...entities.QueryOption) error); ok {
+		r1 = returnFunc(ctx, tx, id, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITenantRepository_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockITenantRepository_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx
+//   - tx
+//   - id
+//   - opts
+func (_e *MockITenantRepository_Expecter) Get(ctx interface{}, tx interface{}, id interface{}, opts ...interface{}) *MockITenantRepository_Get_Call {
+	return &MockITenantRepository_Get_Call{Call: _e.mock.On("Get",
+		append([]interface{}{ctx, tx, id}, opts...)...)}
+}
+
+func (_c *MockITenantRepository_Get_Call) Run(run func(ctx context.Context, tx entities.Transaction, id uint, opts ...entities.QueryOption)) *MockITenantRepository_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := args[3].([]entities.QueryOption)
+		run(args[0].(context.Context), args[1].(entities.Transaction), args[2].(uint), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockITenantRepository_Get_Call) Return(tenant *entities.Tenant, err error) *MockITenantRepository_Get_Call {
+	_c.Call.Return(tenant, err)
+	return _c
+}
+
+func (_c *MockITenantRepository_Get_Call) RunAndReturn(run func(ctx context.Context, tx entities.Transaction, id uint, opts ...entities.QueryOption) (*entities.Tenant, error)) *MockITenantRepository_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetManyByCriterias provides a mock function for the type MockITenantRepository
+func (_mock *MockITenantRepository) GetManyByCriterias(ctx context.Context, tx entities.Transaction, fields []string, criterias map[string]any, orderBys []string, offset int, limit int, opts ...entities.QueryOption) ([]entities.Tenant, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, tx, fields, criterias, orderBys, offset, limit, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, tx, fields, criterias, orderBys, offset, limit)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetManyByCriterias")
+	}
+
+	var r0 []entities.Tenant
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, []string, map[string]any, []string, int, int, ...entities.QueryOption) ([]entities.Tenant, error)); ok {
+		return returnFunc(ctx, tx, fields, criterias, orderBys, offset, limit, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, []string, map[string]any, []string, int, int, ...entities.QueryOption) []entities.Tenant); ok {
+		r0 = returnFunc(ctx, tx, fields, criterias, orderBys, offset, limit, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Tenant)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, entities.Transaction, []string, map[string]any, []string, int, int, ...entities.QueryOption) error); ok {
+		r1 = returnFunc(ctx, tx, fields, criterias, orderBys, offset, limit, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITenantRepository_GetManyByCriterias_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetManyByCriterias'
+type MockITenantRepository_GetManyByCriterias_Call struct {
+	*mock.Call
+}
+
+// GetManyByCriterias is a helper method to define mock.On call
+//   - ctx
+//   - tx
+//   - fields
+//   - criterias
+//   - orderBys
+//   - offset
+//   - limit
+//   - opts
+func (_e *MockITenantRepository_Expecter) GetManyByCriterias(ctx interface{}, tx interface{}, fields interface{}, criterias interface{}, orderBys interface{}, offset interface{}, limit interface{}, opts ...interface{}) *MockITenantRepository_GetManyByCriterias_Call {
+	return &MockITenantRepository_GetManyByCriterias_Call{Call: _e.mock.On("GetManyByCriterias",
+		append([]interface{}{ctx, tx, fields, criterias, orderBys, offset, limit}, opts...)...)}
+}
+
+func (_c *MockITenantRepository_GetManyByCriterias_Call) Run(run func(ctx context.Context, tx entities.Transaction, fields []string, criterias map[string]any, orderBys []string, offset int, limit int, opts ...entities.QueryOption)) *MockITenantRepository_GetManyByCriterias_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := args[7].([]entities.QueryOption)
+		run(args[0].(context.Context), args[1].(entities.Transaction), args[2].([]string), args[3].(map[string]any), args[4].([]string), args[5].(int), args[6].(int), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockITenantRepository_GetManyByCriterias_Call) Return(tenants []entities.Tenant, err error) *MockITenantRepository_GetManyByCriterias_Call {
+	_c.Call.Return(tenants, err)
+	return _c
+}
+
+func (_c *MockITenantRepository_GetManyByCriterias_Call) RunAndReturn(run func(ctx context.Context, tx entities.Transaction, fields []string, criterias map[string]any, orderBys []string, offset int, limit int, opts ...entities.QueryOption) ([]entities.Tenant, error)) *MockITenantRepository_GetManyByCriterias_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -38,6 +38,64 @@ func (_m *MockIUserAttributeRepository) EXPECT() *MockIUserAttributeRepository_E
 	return &MockIUserAttributeRepository_Expecter{mock: &_m.Mock}
 }
 
+// Aggregate provides a mock function for the type MockIUserAttributeRepository
+func (_mock *MockIUserAttributeRepository) Aggregate(ctx context.Context, tx entities.Transaction, query entities.AggregateQuery) ([]entities.AggregateRow, error) {
+	ret := _mock.Called(ctx, tx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Aggregate")
+	}
+
+	var r0 []entities.AggregateRow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, entities.AggregateQuery) ([]entities.AggregateRow, error)); ok {
+		return returnFunc(ctx, tx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, entities.AggregateQuery) []entities.AggregateRow); ok {
+		r0 = returnFunc(ctx, tx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.AggregateRow)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, entities.Transaction, entities.AggregateQuery) error); ok {
+		r1 = returnFunc(ctx, tx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserAttributeRepository_Aggregate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Aggregate'
+type MockIUserAttributeRepository_Aggregate_Call struct {
+	*mock.Call
+}
+
+// Aggregate is a helper method to define mock.On call
+//   - ctx
+//   - tx
+//   - query
+func (_e *MockIUserAttributeRepository_Expecter) Aggregate(ctx interface{}, tx interface{}, query interface{}) *MockIUserAttributeRepository_Aggregate_Call {
+	return &MockIUserAttributeRepository_Aggregate_Call{Call: _e.mock.On("Aggregate", ctx, tx, query)}
+}
+
+func (_c *MockIUserAttributeRepository_Aggregate_Call) Run(run func(ctx context.Context, tx entities.Transaction, query entities.AggregateQuery)) *MockIUserAttributeRepository_Aggregate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(entities.Transaction), args[2].(entities.AggregateQuery))
+	})
+	return _c
+}
+
+func (_c *MockIUserAttributeRepository_Aggregate_Call) Return(aggregateRows []entities.AggregateRow, err error) *MockIUserAttributeRepository_Aggregate_Call {
+	_c.Call.Return(aggregateRows, err)
+	return _c
+}
+
+func (_c *MockIUserAttributeRepository_Aggregate_Call) RunAndReturn(run func(ctx context.Context, tx entities.Transaction, query entities.AggregateQuery) ([]entities.AggregateRow, error)) *MockIUserAttributeRepository_Aggregate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateMany provides a mock function for the type MockIUserAttributeRepository
 func (_mock *MockIUserAttributeRepository) CreateMany(ctx context.Context, tx entities.Transaction, userAttributes []entities.UserAttribute) ([]entities.UserAttribute, error) {
 	ret := _mock.Called(ctx, tx, userAttributes)
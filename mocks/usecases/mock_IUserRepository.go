@@ -38,6 +38,64 @@ func (_m *MockIUserRepository) EXPECT() *MockIUserRepository_Expecter {
 	return &MockIUserRepository_Expecter{mock: &_m.Mock}
 }
 
+// Aggregate provides a mock function for the type MockIUserRepository
+func (_mock *MockIUserRepository) Aggregate(ctx context.Context, tx entities.Transaction, query entities.AggregateQuery) ([]entities.AggregateRow, error) {
+	ret := _mock.Called(ctx, tx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Aggregate")
+	}
+
+	var r0 []entities.AggregateRow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, entities.AggregateQuery) ([]entities.AggregateRow, error)); ok {
+		return returnFunc(ctx, tx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, entities.AggregateQuery) []entities.AggregateRow); ok {
+		r0 = returnFunc(ctx, tx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.AggregateRow)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, entities.Transaction, entities.AggregateQuery) error); ok {
+		r1 = returnFunc(ctx, tx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserRepository_Aggregate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Aggregate'
+type MockIUserRepository_Aggregate_Call struct {
+	*mock.Call
+}
+
+// Aggregate is a helper method to define mock.On call
+//   - ctx
+//   - tx
+//   - query
+func (_e *MockIUserRepository_Expecter) Aggregate(ctx interface{}, tx interface{}, query interface{}) *MockIUserRepository_Aggregate_Call {
+	return &MockIUserRepository_Aggregate_Call{Call: _e.mock.On("Aggregate", ctx, tx, query)}
+}
+
+func (_c *MockIUserRepository_Aggregate_Call) Run(run func(ctx context.Context, tx entities.Transaction, query entities.AggregateQuery)) *MockIUserRepository_Aggregate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(entities.Transaction), args[2].(entities.AggregateQuery))
+	})
+	return _c
+}
+
+func (_c *MockIUserRepository_Aggregate_Call) Return(aggregateRows []entities.AggregateRow, err error) *MockIUserRepository_Aggregate_Call {
+	_c.Call.Return(aggregateRows, err)
+	return _c
+}
+
+func (_c *MockIUserRepository_Aggregate_Call) RunAndReturn(run func(ctx context.Context, tx entities.Transaction, query entities.AggregateQuery) ([]entities.AggregateRow, error)) *MockIUserRepository_Aggregate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function for the type MockIUserRepository
 func (_mock *MockIUserRepository) Create(ctx context.Context, tx entities.Transaction, user *entities.User) (*entities.User, error) {
 	ret := _mock.Called(ctx, tx, user)
@@ -97,8 +155,14 @@ func (_c *MockIUserRepository_Create_Call) RunAndReturn(run func(ctx context.Con
 }
 
 // FindByUsername provides a mock function for the type MockIUserRepository
-func (_mock *MockIUserRepository) FindByUsername(ctx context.Context, tx entities.Transaction, username string) (*entities.User, error) {
-	ret := _mock.Called(ctx, tx, username)
+func (_mock *MockIUserRepository) FindByUsername(ctx context.Context, tx entities.Transaction, username string, opts ...entities.QueryOption) (*entities.User, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, tx, username, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, tx, username)
+	}
+	ret := tmpRet
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindByUsername")
@@ -106,18 +170,18 @@ func (_mock *MockIUserRepository) FindByUsername(ctx context.Context, tx entitie
 
 	var r0 *entities.User
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, string) (*entities.User, error)); ok {
-		return returnFunc(ctx, tx, username)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, string, ...entities.QueryOption) (*entities.User, error)); ok {
+		return returnFunc(ctx, tx, username, opts...)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, string) *entities.User); ok {
-		r0 = returnFunc(ctx, tx, username)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, string, ...entities.QueryOption) *entities.User); ok {
+		r0 = returnFunc(ctx, tx, username, opts...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entities.User)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, entities.Transaction, string) error); ok {
-		r1 = returnFunc(ctx, tx, username)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, entities.Transaction, string, ...entities.QueryOption) error); ok {
+		r1 = returnFunc(ctx, tx, username, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -133,13 +197,16 @@ type MockIUserRepository_FindByUsername_Call struct {
 //   - ctx
 //   - tx
 //   - username
-func (_e *MockIUserRepository_Expecter) FindByUsername(ctx interface{}, tx interface{}, username interface{}) *MockIUserRepository_FindByUsername_Call {
-	return &MockIUserRepository_FindByUsername_Call{Call: _e.mock.On("FindByUsername", ctx, tx, username)}
+//   - opts
+func (_e *MockIUserRepository_Expecter) FindByUsername(ctx interface{}, tx interface{}, username interface{}, opts ...interface{}) *MockIUserRepository_FindByUsername_Call {
+	return &MockIUserRepository_FindByUsername_Call{Call: _e.mock.On("FindByUsername",
+		append([]interface{}{ctx, tx, username}, opts...)...)}
 }
 
-func (_c *MockIUserRepository_FindByUsername_Call) Run(run func(ctx context.Context, tx entities.Transaction, username string)) *MockIUserRepository_FindByUsername_Call {
+func (_c *MockIUserRepository_FindByUsername_Call) Run(run func(ctx context.Context, tx entities.Transaction, username string, opts ...entities.QueryOption)) *MockIUserRepository_FindByUsername_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(entities.Transaction), args[2].(string))
+		variadicArgs := args[3].([]entities.QueryOption)
+		run(args[0].(context.Context), args[1].(entities.Transaction), args[2].(string), variadicArgs...)
 	})
 	return _c
 }
@@ -149,61 +216,141 @@ func (_c *MockIUserRepository_FindByUsername_Call) Return(user *entities.User, e
 	return _c
 }
 
-func (_c *MockIUserRepository_FindByUsername_Call) RunAndReturn(run func(ctx context.Context, tx entities.Transaction, username string) (*entities.User, error)) *MockIUserRepository_FindByUsername_Call {
+func (_c *MockIUserRepository_FindByUsername_Call) RunAndReturn(run func(ctx context.Context, tx entities.Transaction, username string, opts ...entities.QueryOption) (*entities.User, error)) *MockIUserRepository_FindByUsername_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// RunTx provides a mock function for the type MockIUserRepository
-func (_mock *MockIUserRepository) RunTx(ctx context.Context, funcs ...entities.DBTxHandleFunc) error {
+// FindByUsernames provides a mock function for the type MockIUserRepository
+func (_mock *MockIUserRepository) FindByUsernames(ctx context.Context, tx entities.Transaction, usernames []string, opts ...entities.QueryOption) ([]entities.User, error) {
 	var tmpRet mock.Arguments
-	if len(funcs) > 0 {
-		tmpRet = _mock.Called(ctx, funcs)
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, tx, usernames, opts)
 	} else {
-		tmpRet = _mock.Called(ctx)
+		tmpRet = _mock.Called(ctx, tx, usernames)
 	}
 	ret := tmpRet
 
 	if len(ret) == 0 {
-		panic("no return value specified for RunTx")
+		panic("no return value specified for FindByUsernames")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, ...entities.DBTxHandleFunc) error); ok {
-		r0 = returnFunc(ctx, funcs...)
+	var r0 []entities.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, []string, ...entities.QueryOption) ([]entities.User, error)); ok {
+		return returnFunc(ctx, tx, usernames, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, []string, ...entities.QueryOption) []entities.User); ok {
+		r0 = returnFunc(ctx, tx, usernames, opts...)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.User)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, entities.Transaction, []string, ...entities.QueryOption) error); ok {
+		r1 = returnFunc(ctx, tx, usernames, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockIUserRepository_RunTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunTx'
-type MockIUserRepository_RunTx_Call struct {
+// MockIUserRepository_FindByUsernames_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByUsernames'
+type MockIUserRepository_FindByUsernames_Call struct {
 	*mock.Call
 }
 
-// RunTx is a helper method to define mock.On call
+// FindByUsernames is a helper method to define mock.On call
 //   - ctx
-//   - funcs
-func (_e *MockIUserRepository_Expecter) RunTx(ctx interface{}, funcs ...interface{}) *MockIUserRepository_RunTx_Call {
-	return &MockIUserRepository_RunTx_Call{Call: _e.mock.On("RunTx",
-		append([]interface{}{ctx}, funcs...)...)}
+//   - tx
+//   - usernames
+//   - opts
+func (_e *MockIUserRepository_Expecter) FindByUsernames(ctx interface{}, tx interface{}, usernames interface{}, opts ...interface{}) *MockIUserRepository_FindByUsernames_Call {
+	return &MockIUserRepository_FindByUsernames_Call{Call: _e.mock.On("FindByUsernames",
+		append([]interface{}{ctx, tx, usernames}, opts...)...)}
+}
+
+func (_c *MockIUserRepository_FindByUsernames_Call) Run(run func(ctx context.Context, tx entities.Transaction, usernames []string, opts ...entities.QueryOption)) *MockIUserRepository_FindByUsernames_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := args[3].([]entities.QueryOption)
+		run(args[0].(context.Context), args[1].(entities.Transaction), args[2].([]string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIUserRepository_FindByUsernames_Call) Return(users []entities.User, err error) *MockIUserRepository_FindByUsernames_Call {
+	_c.Call.Return(users, err)
+	return _c
+}
+
+func (_c *MockIUserRepository_FindByUsernames_Call) RunAndReturn(run func(ctx context.Context, tx entities.Transaction, usernames []string, opts ...entities.QueryOption) ([]entities.User, error)) *MockIUserRepository_FindByUsernames_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByUuids provides a mock function for the type MockIUserRepository
+func (_mock *MockIUserRepository) FindByUuids(ctx context.Context, tx entities.Transaction, uuids []string, opts ...entities.QueryOption) ([]entities.User, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, tx, uuids, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, tx, uuids)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByUuids")
+	}
+
+	var r0 []entities.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, []string, ...entities.QueryOption) ([]entities.User, error)); ok {
+		return returnFunc(ctx, tx, uuids, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Transaction, []string, ...entities.QueryOption) []entities.User); ok {
+		r0 = returnFunc(ctx, tx, uuids, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, entities.Transaction, []string, ...entities.QueryOption) error); ok {
+		r1 = returnFunc(ctx, tx, uuids, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserRepository_FindByUuids_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByUuids'
+type MockIUserRepository_FindByUuids_Call struct {
+	*mock.Call
+}
+
+// FindByUuids is a helper method to define mock.On call
+//   - ctx
+//   - tx
+//   - uuids
+//   - opts
+func (_e *MockIUserRepository_Expecter) FindByUuids(ctx interface{}, tx interface{}, uuids interface{}, opts ...interface{}) *MockIUserRepository_FindByUuids_Call {
+	return &MockIUserRepository_FindByUuids_Call{Call: _e.mock.On("FindByUuids",
+		append([]interface{}{ctx, tx, uuids}, opts...)...)}
 }
 
-func (_c *MockIUserRepository_RunTx_Call) Run(run func(ctx context.Context, funcs ...entities.DBTxHandleFunc)) *MockIUserRepository_RunTx_Call {
+func (_c *MockIUserRepository_FindByUuids_Call) Run(run func(ctx context.Context, tx entities.Transaction, uuids []string, opts ...entities.QueryOption)) *MockIUserRepository_FindByUuids_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		variadicArgs := args[1].([]entities.DBTxHandleFunc)
-		run(args[0].(context.Context), variadicArgs...)
+		variadicArgs := args[3].([]entities.QueryOption)
+		run(args[0].(context.Context), args[1].(entities.Transaction), args[2].([]string), variadicArgs...)
 	})
 	return _c
 }
 
-func (_c *MockIUserRepository_RunTx_Call) Return(err error) *MockIUserRepository_RunTx_Call {
-	_c.Call.Return(err)
+func (_c *MockIUserRepository_FindByUuids_Call) Return(users []entities.User, err error) *MockIUserRepository_FindByUuids_Call {
+	_c.Call.Return(users, err)
 	return _c
 }
 
-func (_c *MockIUserRepository_RunTx_Call) RunAndReturn(run func(ctx context.Context, funcs ...entities.DBTxHandleFunc) error) *MockIUserRepository_RunTx_Call {
+func (_c *MockIUserRepository_FindByUuids_Call) RunAndReturn(run func(ctx context.Context, tx entities.Transaction, uuids []string, opts ...entities.QueryOption) ([]entities.User, error)) *MockIUserRepository_FindByUuids_Call {
 	_c.Call.Return(run)
 	return _c
 }